@@ -23,14 +23,14 @@ import (
 // New creates a new AddressSetManager instance based on the useNftables flag.
 // If useNftables is false, it returns an ipset-based implementation.
 // If useNftables is true, it returns an nftables-based implementation.
-func New(useNftables bool, enableIPv6 bool) (AddressSetManager, error) {
+func New(useNftables bool, enableIPv4 bool, enableIPv6 bool) (AddressSetManager, error) {
 	if useNftables {
-		return nftables.NewHostSetManager(config.ProbeIPSet, enableIPv6)
+		return nftables.NewHostSetManager(config.ProbeIPSet, enableIPv4, enableIPv6)
 	}
 
 	// Use existing ipset implementation
 	linDeps := ipset.RealNlDeps()
-	ipsetInstance, err := ipset.NewIPSet(config.ProbeIPSet, enableIPv6, linDeps)
+	ipsetInstance, err := ipset.NewIPSet(config.ProbeIPSet, enableIPv4, enableIPv6, linDeps)
 	if err != nil {
 		return nil, err
 	}
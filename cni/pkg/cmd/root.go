@@ -95,6 +95,9 @@ var rootCmd = &cobra.Command{
 
 		installer := install.NewInstaller(&cfg.InstallConfig, installDaemonReady)
 
+		// Enrollment results are shared between the ambient node agent and the repair controller.
+		var enrollment *nodeagent.EnrollmentResults
+
 		if cfg.InstallConfig.AmbientEnabled {
 			// Start ambient controller
 
@@ -117,6 +120,13 @@ var rootCmd = &cobra.Command{
 				log.Warn("NativeNftables is enabled along with ForceIptablesBinary. Using native nftables and ignoring iptables")
 			}
 
+			enrollment = nodeagent.NewEnrollmentResults()
+
+			var checkpointPath string
+			if cfg.InstallConfig.AmbientCheckpointPodState {
+				checkpointPath = filepath.Join(cfg.InstallConfig.CNIAgentRunDir, constants.PodStateCheckpointName)
+			}
+
 			ambientAgent, err := nodeagent.NewServer(ctx, watchServerReady, cniEventAddr,
 				nodeagent.AmbientArgs{
 					SystemNamespace:            nodeagent.SystemNamespace,
@@ -126,9 +136,12 @@ var rootCmd = &cobra.Command{
 					ExcludeNamespaces:          util.SplitExcludeNamespaces(cfg.InstallConfig.ExcludeNamespaces),
 					DNSCapture:                 cfg.InstallConfig.AmbientDNSCapture,
 					EnableIPv6:                 cfg.InstallConfig.AmbientIPv6,
+					IPv6Only:                   cfg.InstallConfig.AmbientIPv6Only,
 					ReconcilePodRulesOnStartup: cfg.InstallConfig.AmbientReconcilePodRulesOnStartup,
 					NativeNftables:             cfg.InstallConfig.NativeNftables,
 					ForceIptablesBinary:        cfg.InstallConfig.ForceIptablesBinary,
+					CheckpointPath:             checkpointPath,
+					EnrollmentResults:          enrollment,
 				})
 			if err != nil {
 				return fmt.Errorf("failed to create ambient nodeagent service: %v", err)
@@ -182,7 +195,7 @@ var rootCmd = &cobra.Command{
 		}
 		// TODO Note that during an "upgrade shutdown" in ambient mode,
 		// repair will (necessarily) be unavailable.
-		repair.StartRepair(ctx, cfg.RepairConfig)
+		repair.StartRepair(ctx, cfg.RepairConfig, enrollment)
 
 		// Note that even though we "install" the CNI plugin here *after* we start the node agent,
 		// it will block ambient-enabled pods from starting until `watchServerReady` == true
@@ -242,6 +255,10 @@ func init() {
 	registerStringParameter(constants.ZtunnelUDSAddress, "/var/run/ztunnel/ztunnel.sock", "The UDS server address which ztunnel will connect to")
 	registerBooleanParameter(constants.AmbientEnabled, false, "Whether ambient controller is enabled")
 	registerBooleanParameter(constants.EnableAmbientDetectionRetry, false, "Whether or not is ambient check is retried on error in the cni plugin")
+	registerBooleanParameter(constants.AmbientIPv6Only, false,
+		"Whether ambient capture is IPv6 only. If set, no IPv4 rules or address sets are created, and IPv6 support is enabled")
+	registerBooleanParameter(constants.AmbientCheckpointPodState, false,
+		"Whether the node agent checkpoints enrolled pod state to the agent run dir, and resumes from it on restart")
 	// Repair
 	registerBooleanParameter(constants.RepairEnabled, true, "Whether to enable race condition repair or not")
 	registerBooleanParameter(constants.RepairDeletePods, false, "Controller will delete pods when detecting pod broken by race condition")
@@ -264,6 +281,10 @@ func init() {
 		"A set of label selectors in label=value format that will be added to the pod list filters")
 	registerStringParameter(constants.RepairFieldSelectors, "",
 		"A set of field selectors in label=value format that will be added to the pod list filters")
+	registerBooleanParameter(constants.RepairAmbientPods, false,
+		"Whether the repair controller also acts on pods the ambient node agent failed to enroll, using the configured repair action")
+	registerIntegerParameter(constants.RepairAmbientRateLimit, 10,
+		"Maximum number of actions per minute the repair controller takes on pods that failed ambient enrollment")
 }
 
 func registerStringParameter(name, value, usage string) {
@@ -331,9 +352,11 @@ func constructConfig() (*config.Config, error) {
 		AmbientEnablementSelector:         viper.GetString(constants.AmbientEnablementSelector),
 		AmbientDNSCapture:                 viper.GetBool(constants.AmbientDNSCapture),
 		AmbientIPv6:                       viper.GetBool(constants.AmbientIPv6),
+		AmbientIPv6Only:                   viper.GetBool(constants.AmbientIPv6Only),
 		AmbientDisableSafeUpgrade:         viper.GetBool(constants.AmbientDisableSafeUpgrade),
 		AmbientReconcilePodRulesOnStartup: viper.GetBool(constants.AmbientReconcilePodRulesOnStartup),
 		EnableAmbientDetectionRetry:       viper.GetBool(constants.EnableAmbientDetectionRetry),
+		AmbientCheckpointPodState:         viper.GetBool(constants.AmbientCheckpointPodState),
 
 		NativeNftables:      viper.GetBool(constants.NativeNftables),
 		ForceIptablesBinary: os.Getenv("FORCE_IPTABLES_BINARY"),
@@ -367,6 +390,8 @@ func constructConfig() (*config.Config, error) {
 		InitExitCode:        viper.GetInt(constants.RepairInitExitCode),
 		LabelSelectors:      viper.GetString(constants.RepairLabelSelectors),
 		FieldSelectors:      viper.GetString(constants.RepairFieldSelectors),
+		RepairAmbientPods:   viper.GetBool(constants.RepairAmbientPods),
+		AmbientRateLimit:    viper.GetInt(constants.RepairAmbientRateLimit),
 		NativeNftables:      viper.GetBool(constants.NativeNftables),
 		ForceIptablesBinary: os.Getenv("FORCE_IPTABLES_BINARY"),
 	}
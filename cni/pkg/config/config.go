@@ -63,6 +63,7 @@ type PodLevelOverrides struct {
 type AmbientConfig struct {
 	TraceLogging           bool       `json:"IPTABLES_TRACE_LOGGING"`
 	EnableIPv6             bool       `json:"ENABLE_INBOUND_IPV6"`
+	IPv6Only               bool       `json:"IPV6_ONLY"`
 	RedirectDNS            bool       `json:"REDIRECT_DNS"`
 	HostProbeSNATAddress   netip.Addr `json:"HOST_PROBE_SNAT_ADDRESS"`
 	HostProbeV6SNATAddress netip.Addr `json:"HOST_PROBE_V6_SNAT_ADDRESS"`
@@ -71,6 +72,12 @@ type AmbientConfig struct {
 	ForceApply             bool       `json:"FORCE_APPLY"`
 }
 
+// IPv4Enabled returns false if only IPv6 is in use (IPv6Only), in which case no IPv4 rules or address sets
+// should be created. IPv6Only is only meaningful along with EnableIPv6.
+func (c *AmbientConfig) IPv4Enabled() bool {
+	return !c.IPv6Only
+}
+
 // GetConfig converts AmbientConfig to tools common config format
 func GetConfig(c *AmbientConfig) *cfg.Config {
 	return &cfg.Config{
@@ -154,6 +161,9 @@ type InstallConfig struct {
 	// Whether ipv6 is enabled for ambient capture
 	AmbientIPv6 bool
 
+	// Whether ambient capture is ipv6 only (no ipv4 rules or address sets are created)
+	AmbientIPv6Only bool
+
 	// Feature flag to disable safe upgrade. Will be removed in future releases.
 	AmbientDisableSafeUpgrade bool
 
@@ -163,6 +173,9 @@ type InstallConfig struct {
 	// Whether to retry checking if a pod is ambient in the cni plugin when there are errors
 	EnableAmbientDetectionRetry bool
 
+	// Whether the node agent checkpoints enrolled pod state to CNIAgentRunDir, and resumes from it on restart
+	AmbientCheckpointPodState bool
+
 	// Whether native nftables should be used instead of iptable rules for traffic redirection
 	NativeNftables bool
 
@@ -210,6 +223,12 @@ type RepairConfig struct {
 	LabelSelectors string
 	FieldSelectors string
 
+	// Whether to also act on pods the ambient node agent failed to enroll
+	RepairAmbientPods bool
+
+	// Maximum number of actions per minute taken on pods that failed ambient enrollment
+	AmbientRateLimit int
+
 	// Whether to repair pods by running nftables rules
 	NativeNftables bool
 
@@ -249,9 +268,11 @@ func (c InstallConfig) String() string {
 	b.WriteString("AmbientEnablementSelector: " + c.AmbientEnablementSelector + "\n")
 	b.WriteString("AmbientDNSCapture: " + fmt.Sprint(c.AmbientDNSCapture) + "\n")
 	b.WriteString("AmbientIPv6: " + fmt.Sprint(c.AmbientIPv6) + "\n")
+	b.WriteString("AmbientIPv6Only: " + fmt.Sprint(c.AmbientIPv6Only) + "\n")
 	b.WriteString("AmbientDisableSafeUpgrade: " + fmt.Sprint(c.AmbientDisableSafeUpgrade) + "\n")
 	b.WriteString("AmbientReconcilePodRulesOnStartup: " + fmt.Sprint(c.AmbientReconcilePodRulesOnStartup) + "\n")
 	b.WriteString("EnableAmbientDetectionRetry: " + fmt.Sprint(c.EnableAmbientDetectionRetry) + "\n")
+	b.WriteString("AmbientCheckpointPodState: " + fmt.Sprint(c.AmbientCheckpointPodState) + "\n")
 
 	b.WriteString("NativeNftables: " + fmt.Sprint(c.NativeNftables) + "\n")
 	b.WriteString("ForceIptablesBinary: " + fmt.Sprint(c.ForceIptablesBinary) + "\n")
@@ -272,6 +293,8 @@ func (c RepairConfig) String() string {
 	b.WriteString("InitExitCode: " + fmt.Sprint(c.InitExitCode) + "\n")
 	b.WriteString("LabelSelectors: " + c.LabelSelectors + "\n")
 	b.WriteString("FieldSelectors: " + c.FieldSelectors + "\n")
+	b.WriteString("RepairAmbientPods: " + fmt.Sprint(c.RepairAmbientPods) + "\n")
+	b.WriteString("AmbientRateLimit: " + fmt.Sprint(c.AmbientRateLimit) + "\n")
 	b.WriteString("NativeNftables: " + fmt.Sprint(c.NativeNftables) + "\n")
 	b.WriteString("ForceIptablesBinary: " + fmt.Sprint(c.ForceIptablesBinary) + "\n")
 	return b.String()
@@ -45,9 +45,11 @@ const (
 	AmbientEnablementSelector         = "ambient-enablement-selector"
 	AmbientDNSCapture                 = "ambient-dns-capture"
 	AmbientIPv6                       = "ambient-ipv6"
+	AmbientIPv6Only                   = "ambient-ipv6-only"
 	AmbientDisableSafeUpgrade         = "ambient-disable-safe-upgrade"
 	AmbientReconcilePodRulesOnStartup = "ambient-reconcile-pod-rules-on-startup"
 	EnableAmbientDetectionRetry       = "enable-ambient-detection-retry"
+	AmbientCheckpointPodState         = "ambient-checkpoint-pod-state"
 
 	NativeNftables = "native-nftables"
 
@@ -65,22 +67,25 @@ const (
 	RepairInitExitCode       = "repair-init-container-exit-code"
 	RepairLabelSelectors     = "repair-label-selectors"
 	RepairFieldSelectors     = "repair-field-selectors"
+	RepairAmbientPods        = "repair-ambient-pods"
+	RepairAmbientRateLimit   = "repair-ambient-rate-limit"
 )
 
 // Internal constants
 const (
-	DefaultKubeconfigMode = 0o600
-	CNIConfModeDefault    = 0o600
-	CNIConfModeGroupRead  = 0o640
-	CNIAgentLogScope      = "cni-agent"
-	CNIPluginLogScope     = "cni-plugin"
-	CNIAddEventPath       = "/cmdadd"
-	UDSLogPath            = "/log"
-	CNIEventSocketName    = "pluginevent.sock"
-	LogUDSSocketName      = "log.sock"
-	LocalRollingLogName   = "istio-cni.log"
-	RollingLogMaxSizeMB   = 10
-	CNIPluginKubeconfName = "istio-cni-kubeconfig"
+	DefaultKubeconfigMode  = 0o600
+	CNIConfModeDefault     = 0o600
+	CNIConfModeGroupRead   = 0o640
+	CNIAgentLogScope       = "cni-agent"
+	CNIPluginLogScope      = "cni-plugin"
+	CNIAddEventPath        = "/cmdadd"
+	UDSLogPath             = "/log"
+	CNIEventSocketName     = "pluginevent.sock"
+	LogUDSSocketName       = "log.sock"
+	PodStateCheckpointName = "pod-state.json"
+	LocalRollingLogName    = "istio-cni.log"
+	RollingLogMaxSizeMB    = 10
+	CNIPluginKubeconfName  = "istio-cni-kubeconfig"
 	// K8s liveness and readiness endpoints
 	LivenessEndpoint                   = "/healthz"
 	ReadinessEndpoint                  = "/readyz"
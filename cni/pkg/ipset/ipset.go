@@ -51,15 +51,19 @@ type NetlinkIpsetDeps interface {
 //
 // BUT netlink lib doesn't support adding things to `list:set` types yet, and current tagged release
 // doesn't support creating `list:set` types yet (is in main branch tho).
-// So this will actually create 2 underlying ipsets, one for v4 and one for v6
-func NewIPSet(name string, v6 bool, deps NetlinkIpsetDeps) (IPSet, error) {
+// So this will actually create 2 underlying ipsets, one for v4 and one for v6.
+//
+// Only the sets for the enabled IP families are created; IPs from a disabled family are ignored.
+func NewIPSet(name string, v4 bool, v6 bool, deps NetlinkIpsetDeps) (IPSet, error) {
 	var err error
 	set := IPSet{
-		V4Name: fmt.Sprintf(V4Name, name),
 		Deps:   deps,
 		Prefix: name,
 	}
-	err = deps.ipsetIPHashCreate(set.V4Name, false)
+	if v4 {
+		set.V4Name = fmt.Sprintf(V4Name, name)
+		err = deps.ipsetIPHashCreate(set.V4Name, false)
+	}
 	if v6 {
 		set.V6Name = fmt.Sprintf(V6Name, name)
 		v6err := deps.ipsetIPHashCreate(set.V6Name, true)
@@ -68,86 +72,92 @@ func NewIPSet(name string, v6 bool, deps NetlinkIpsetDeps) (IPSet, error) {
 	return set, err
 }
 
+// setNames returns the names of all the sets that were created.
+func (m *IPSet) setNames() []string {
+	var names []string
+	if m.V4Name != "" {
+		names = append(names, m.V4Name)
+	}
+	if m.V6Name != "" {
+		names = append(names, m.V6Name)
+	}
+	return names
+}
+
+// setFor returns the (already Unmap'd) IP, and the name of the set it belongs in.
+// The name is empty if the IP family of the set was not enabled.
+func (m *IPSet) setFor(ip netip.Addr) (netip.Addr, string) {
+	ip = ip.Unmap()
+	// We have already Unmap'd, so we can do a simple IsV6 y/n check now
+	if ip.Is6() {
+		return ip, m.V6Name
+	}
+	return ip, m.V4Name
+}
+
 func (m *IPSet) DestroySet() error {
 	var err error
-	err = m.Deps.destroySet(m.V4Name)
-
-	if m.V6Name != "" {
-		v6err := m.Deps.destroySet(m.V6Name)
-		err = errors.Join(err, v6err)
+	for _, name := range m.setNames() {
+		err = errors.Join(err, m.Deps.destroySet(name))
 	}
 	return err
 }
 
 func (m *IPSet) AddIP(ip netip.Addr, ipProto uint8, comment string, replace bool) error {
-	ipToInsert := ip.Unmap()
-
-	// We have already Unmap'd, so we can do a simple IsV6 y/n check now
-	if ipToInsert.Is6() {
-		return m.Deps.addIP(m.V6Name, ipToInsert, ipProto, comment, replace)
+	ipToInsert, name := m.setFor(ip)
+	if name == "" {
+		return nil
 	}
-	return m.Deps.addIP(m.V4Name, ipToInsert, ipProto, comment, replace)
+	return m.Deps.addIP(name, ipToInsert, ipProto, comment, replace)
 }
 
 func (m *IPSet) DeleteIP(ip netip.Addr, ipProto uint8) error {
-	ipToDel := ip.Unmap()
-
-	// We have already Unmap'd, so we can do a simple IsV6 y/n check now
-	if ipToDel.Is6() {
-		return m.Deps.deleteIP(m.V6Name, ipToDel, ipProto)
+	ipToDel, name := m.setFor(ip)
+	if name == "" {
+		return nil
 	}
-	return m.Deps.deleteIP(m.V4Name, ipToDel, ipProto)
+	return m.Deps.deleteIP(name, ipToDel, ipProto)
 }
 
 func (m *IPSet) Flush() error {
 	var err error
-	err = m.Deps.flush(m.V4Name)
-
-	if m.V6Name != "" {
-		v6err := m.Deps.flush(m.V6Name)
-		err = errors.Join(err, v6err)
+	for _, name := range m.setNames() {
+		err = errors.Join(err, m.Deps.flush(name))
 	}
 	return err
 }
 
 func (m *IPSet) ClearEntriesWithComment(comment string) error {
 	var err error
-	err = m.Deps.clearEntriesWithComment(m.V4Name, comment)
-
-	if m.V6Name != "" {
-		v6err := m.Deps.clearEntriesWithComment(m.V6Name, comment)
-		err = errors.Join(err, v6err)
+	for _, name := range m.setNames() {
+		err = errors.Join(err, m.Deps.clearEntriesWithComment(name, comment))
 	}
 	return err
 }
 
 func (m *IPSet) ClearEntriesWithIP(ip netip.Addr) error {
-	ipToClear := ip.Unmap()
-
-	if ipToClear.Is6() {
-		return m.Deps.clearEntriesWithIP(m.V6Name, ipToClear)
+	ipToClear, name := m.setFor(ip)
+	if name == "" {
+		return nil
 	}
-	return m.Deps.clearEntriesWithIP(m.V4Name, ipToClear)
+	return m.Deps.clearEntriesWithIP(name, ipToClear)
 }
 
 func (m *IPSet) ClearEntriesWithIPAndComment(ip netip.Addr, comment string) (string, error) {
-	ipToClear := ip.Unmap()
-
-	if ipToClear.Is6() {
-		return m.Deps.clearEntriesWithIPAndComment(m.V6Name, ipToClear, comment)
+	ipToClear, name := m.setFor(ip)
+	if name == "" {
+		return "", nil
 	}
-	return m.Deps.clearEntriesWithIPAndComment(m.V4Name, ipToClear, comment)
+	return m.Deps.clearEntriesWithIPAndComment(name, ipToClear, comment)
 }
 
 func (m *IPSet) ListEntriesByIP() ([]netip.Addr, error) {
 	var err error
 	var set []netip.Addr
-	set, err = m.Deps.listEntriesByIP(m.V4Name)
-
-	if m.V6Name != "" {
-		v6set, v6err := m.Deps.listEntriesByIP(m.V6Name)
-		err = errors.Join(err, v6err)
-		set = append(set, v6set...)
+	for _, name := range m.setNames() {
+		entries, listErr := m.Deps.listEntriesByIP(name)
+		err = errors.Join(err, listErr)
+		set = append(set, entries...)
 	}
 	return set, err
 }
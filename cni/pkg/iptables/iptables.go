@@ -84,11 +84,15 @@ func NewIptablesConfigurator(
 	err := util.RunAsHost(func() error {
 		iptVer, err := hostDeps.DetectIptablesVersion(false)
 		if err != nil {
-			return err
+			if hostCfg.IPv4Enabled() {
+				return err
+			}
+			log.Warnf("Failed to detect a working iptables binary; continuing because only IPv6 is in use: %v", err)
+			iptVer = dep.IptablesVersion{}
+		} else {
+			log.Debugf("found iptables binary: %+v", iptVer)
 		}
 
-		log.Debugf("found iptables binary: %+v", iptVer)
-
 		configurator.iptV = iptVer
 
 		ipt6Ver, err := hostDeps.DetectIptablesVersion(true)
@@ -149,18 +153,31 @@ func (cfg *IptablesConfigurator) executeDeleteCommands(log *istiolog.Scope) {
 		{"-t", "raw", "-X", ChainInpodOutput},
 	}
 
-	iptablesVariant := []dep.IptablesVersion{}
-	iptablesVariant = append(iptablesVariant, cfg.iptV)
+	for _, iptVer := range cfg.iptablesVariants() {
+		for _, cmd := range deleteCmds {
+			_, _ = cfg.ext.Run(log, true, iptablesconstants.IPTables, iptVer, nil, cmd...)
+		}
+	}
+}
 
+// iptablesVariants returns the iptables binaries to use, one for each enabled IP family.
+func (cfg *IptablesConfigurator) iptablesVariants() []*dep.IptablesVersion {
+	var variants []*dep.IptablesVersion
+	if cfg.cfg.IPv4Enabled() {
+		variants = append(variants, &cfg.iptV)
+	}
 	if cfg.cfg.EnableIPv6 {
-		iptablesVariant = append(iptablesVariant, cfg.ipt6V)
+		variants = append(variants, &cfg.ipt6V)
 	}
+	return variants
+}
 
-	for _, iptVer := range iptablesVariant {
-		for _, cmd := range deleteCmds {
-			_, _ = cfg.ext.Run(log, true, iptablesconstants.IPTables, &iptVer, nil, cmd...)
-		}
+// ipv4Version returns the iptables binary to use for IPv4, or nil if IPv4 is not in use.
+func (cfg *IptablesConfigurator) ipv4Version() *dep.IptablesVersion {
+	if !cfg.cfg.IPv4Enabled() {
+		return nil
 	}
+	return &cfg.iptV
 }
 
 // Setup iptables rules for in-pod mode. Ideally this should be an idempotent function.
@@ -441,13 +458,12 @@ func (cfg *IptablesConfigurator) executeCommands(log *istiolog.Scope, iptablesBu
 		if guardrails {
 			log.Info("Removing guardrails")
 			guardrailsCleanup := iptablesBuilder.BuildCleanupGuardrails()
-			_ = cfg.executeIptablesCommands(log, &cfg.iptV, guardrailsCleanup)
-			if cfg.cfg.EnableIPv6 {
-				_ = cfg.executeIptablesCommands(log, &cfg.ipt6V, guardrailsCleanup)
+			for _, ver := range cfg.iptablesVariants() {
+				_ = cfg.executeIptablesCommands(log, ver, guardrailsCleanup)
 			}
 		}
 	}()
-	residueExists, deltaExists := iptablescapture.VerifyIptablesState(log, cfg.ext, iptablesBuilder, &cfg.iptV, &cfg.ipt6V)
+	residueExists, deltaExists := iptablescapture.VerifyIptablesState(log, cfg.ext, iptablesBuilder, cfg.ipv4Version(), &cfg.ipt6V)
 	if residueExists && deltaExists && !cfg.cfg.Reconcile {
 		log.Warn("reconcile is needed but no-reconcile flag is set. Unexpected behavior may occur due to preexisting iptables rules")
 	}
@@ -458,13 +474,9 @@ func (cfg *IptablesConfigurator) executeCommands(log *istiolog.Scope, iptablesBu
 			log.Info("Setting up guardrails")
 			guardrailsCleanup := iptablesBuilder.BuildCleanupGuardrails()
 			guardrailsRules := iptablesBuilder.BuildGuardrails()
-			iptVersions := []dep.IptablesVersion{cfg.iptV}
-			if cfg.cfg.EnableIPv6 {
-				iptVersions = append(iptVersions, cfg.ipt6V)
-			}
-			for _, ver := range iptVersions {
-				cfg.tryExecuteIptablesCommands(log, &ver, guardrailsCleanup)
-				if err := cfg.executeIptablesCommands(log, &ver, guardrailsRules); err != nil {
+			for _, ver := range cfg.iptablesVariants() {
+				cfg.tryExecuteIptablesCommands(log, ver, guardrailsCleanup)
+				if err := cfg.executeIptablesCommands(log, ver, guardrailsRules); err != nil {
 					return err
 				}
 				guardrails = true
@@ -472,7 +484,9 @@ func (cfg *IptablesConfigurator) executeCommands(log *istiolog.Scope, iptablesBu
 		}
 		// Remove old iptables
 		log.Info("Performing cleanup of existing iptables")
-		cfg.tryExecuteIptablesCommands(log, &cfg.iptV, iptablesBuilder.BuildCleanupV4())
+		if cfg.cfg.IPv4Enabled() {
+			cfg.tryExecuteIptablesCommands(log, &cfg.iptV, iptablesBuilder.BuildCleanupV4())
+		}
 		if cfg.cfg.EnableIPv6 {
 			cfg.tryExecuteIptablesCommands(log, &cfg.ipt6V, iptablesBuilder.BuildCleanupV6())
 		}
@@ -480,7 +494,7 @@ func (cfg *IptablesConfigurator) executeCommands(log *istiolog.Scope, iptablesBu
 		// Remove leftovers from non-matching istio iptables cfg
 		if cfg.cfg.Reconcile {
 			log.Info("Performing cleanup of any unexpected leftovers from previous iptables executions")
-			cfg.cleanupIstioLeftovers(log, cfg.ext, iptablesBuilder, &cfg.iptV, &cfg.ipt6V)
+			cfg.cleanupIstioLeftovers(log, cfg.ext, iptablesBuilder, cfg.ipv4Version(), &cfg.ipt6V)
 		}
 	}
 
@@ -488,7 +502,9 @@ func (cfg *IptablesConfigurator) executeCommands(log *istiolog.Scope, iptablesBu
 	if (deltaExists || cfg.cfg.ForceApply) && !cfg.cfg.CleanupOnly {
 		log.Info("Applying iptables chains and rules")
 		// Execute iptables-restore
-		execErrs = append(execErrs, cfg.executeIptablesRestoreCommand(log, iptablesBuilder.BuildV4Restore(), &cfg.iptV))
+		if cfg.cfg.IPv4Enabled() {
+			execErrs = append(execErrs, cfg.executeIptablesRestoreCommand(log, iptablesBuilder.BuildV4Restore(), &cfg.iptV))
+		}
 		// Execute ip6tables-restore
 		if cfg.cfg.EnableIPv6 {
 			execErrs = append(execErrs, cfg.executeIptablesRestoreCommand(log, iptablesBuilder.BuildV6Restore(), &cfg.ipt6V))
@@ -501,7 +517,7 @@ func (cfg *IptablesConfigurator) cleanupIstioLeftovers(log *istiolog.Scope, ext
 	iptV *dep.IptablesVersion, ipt6V *dep.IptablesVersion,
 ) {
 	for _, ipVer := range []*dep.IptablesVersion{iptV, ipt6V} {
-		if ipVer.DetectedBinary == "" {
+		if ipVer == nil || ipVer.DetectedBinary == "" {
 			continue
 		}
 		output, err := ext.Run(log, true, iptablesconstants.IPTablesSave, ipVer, nil)
@@ -596,8 +612,9 @@ func (cfg *IptablesConfigurator) DeleteHostRules() {
 	}
 
 	err := util.RunAsHost(func() error {
-		runCommands(builder.BuildCleanupV4(), &cfg.iptV)
-
+		if cfg.cfg.IPv4Enabled() {
+			runCommands(builder.BuildCleanupV4(), &cfg.iptV)
+		}
 		if cfg.cfg.EnableIPv6 {
 			runCommands(builder.BuildCleanupV6(), &cfg.ipt6V)
 		}
@@ -634,17 +651,21 @@ func (cfg *IptablesConfigurator) AppendHostRules() *builder.IptablesRuleBuilder
 	// we cannot make assumptions there.
 
 	// -A OUTPUT -m owner --socket-exists -p tcp -m set --match-set istio-inpod-probes dst,dst -j SNAT --to-source 169.254.7.127
-	iptablesBuilder.AppendRuleV4(
-		ChainHostPostrouting, "nat",
-		"-m", "owner",
-		"--socket-exists",
-		"-p", "tcp",
-		"-m", "set",
-		"--match-set", fmt.Sprintf(ipset.V4Name, config.ProbeIPSet),
-		"dst",
-		"-j", "SNAT",
-		"--to-source", cfg.cfg.HostProbeSNATAddress.String(),
-	)
+	//
+	// On IPv6-only nodes there is no V4 set to match against
+	if cfg.cfg.IPv4Enabled() {
+		iptablesBuilder.AppendRuleV4(
+			ChainHostPostrouting, "nat",
+			"-m", "owner",
+			"--socket-exists",
+			"-p", "tcp",
+			"-m", "set",
+			"--match-set", fmt.Sprintf(ipset.V4Name, config.ProbeIPSet),
+			"dst",
+			"-j", "SNAT",
+			"--to-source", cfg.cfg.HostProbeSNATAddress.String(),
+		)
+	}
 
 	// For V6 we have to use a different set and a different SNAT IP
 	if cfg.cfg.EnableIPv6 {
@@ -35,7 +35,7 @@ import (
 
 func createHostsideProbeIpset(isV6 bool) (ipset.IPSet, error) {
 	linDeps := ipset.RealNlDeps()
-	probeSet, err := ipset.NewIPSet(config.ProbeIPSet, isV6, linDeps)
+	probeSet, err := ipset.NewIPSet(config.ProbeIPSet, true, isV6, linDeps)
 	if err != nil {
 		return probeSet, err
 	}
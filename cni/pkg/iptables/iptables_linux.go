@@ -44,7 +44,10 @@ func DelInpodMarkIPRule(cfg *cniconfig.AmbientConfig) error {
 
 func forEachInpodMarkIPRule(cfg *cniconfig.AmbientConfig, f func(*netlink.Rule) error) error {
 	var rules []*netlink.Rule
-	families := []int{unix.AF_INET}
+	var families []int
+	if cfg.IPv4Enabled() {
+		families = append(families, unix.AF_INET)
+	}
 	if cfg.EnableIPv6 {
 		families = append(families, unix.AF_INET6)
 	}
@@ -100,7 +103,10 @@ func forEachLoopbackRoute(cfg *cniconfig.AmbientConfig, operation string, f func
 	}
 
 	// Set up netlink routes for localhost
-	cidrs := []string{"0.0.0.0/0"}
+	var cidrs []string
+	if cfg.IPv4Enabled() {
+		cidrs = append(cidrs, "0.0.0.0/0")
+	}
 	if cfg.EnableIPv6 {
 		// IPv6 may be enabled, but only partially
 		v, err := ReadSysctl(ipv6DisabledLo)
@@ -31,10 +31,10 @@ func TestIptablesPodOverrides(t *testing.T) {
 	cases := GetCommonInPodTestCases()
 
 	for _, tt := range cases {
-		for _, ipv6 := range []bool{false, true} {
-			t.Run(tt.name+"_"+ipstr(ipv6), func(t *testing.T) {
+		for _, family := range ipFamilies {
+			t.Run(tt.name+"_"+string(family), func(t *testing.T) {
 				cfg := constructTestConfig()
-				cfg.EnableIPv6 = ipv6
+				family.configure(cfg)
 				tt.config(cfg)
 				ext := &dep.DependenciesStub{}
 				iptConfigurator, _, _ := NewIptablesConfigurator(cfg, cfg, ext, ext, EmptyNlDeps())
@@ -43,7 +43,7 @@ func TestIptablesPodOverrides(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				compareToGolden(t, ipv6, tt.name, ext.ExecutedAll)
+				compareToGolden(t, family, tt.name, ext.ExecutedAll)
 			})
 		}
 	}
@@ -66,14 +66,32 @@ func TestIPv6NotAvailable(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestIPv4NotAvailable(t *testing.T) {
+	setup(t)
+	cfg := constructTestConfig()
+	ext := &dep.DependenciesStub{
+		ForceIPv4DetectionFail: true,
+	}
+
+	// Istio shouldn't fail on IPv6-only nodes if iptables is unavailable, as long as ip6tables is.
+	cfg.EnableIPv6 = true
+	cfg.IPv6Only = true
+	_, _, err := NewIptablesConfigurator(cfg, cfg, ext, ext, EmptyNlDeps())
+	assert.NoError(t, err)
+
+	cfg.IPv6Only = false
+	_, _, err = NewIptablesConfigurator(cfg, cfg, ext, ext, EmptyNlDeps())
+	assert.Error(t, err)
+}
+
 func TestIptablesHostRules(t *testing.T) {
 	cases := GetCommonHostTestCases()
 
 	for _, tt := range cases {
-		for _, ipv6 := range []bool{false, true} {
-			t.Run(tt.name+"_"+ipstr(ipv6), func(t *testing.T) {
+		for _, family := range ipFamilies {
+			t.Run(tt.name+"_"+string(family), func(t *testing.T) {
 				cfg := constructTestConfig()
-				cfg.EnableIPv6 = ipv6
+				family.configure(cfg)
 				cfg.HostProbeSNATAddress = netip.MustParseAddr("169.254.7.127")
 				cfg.HostProbeV6SNATAddress = netip.MustParseAddr("fd16:9254:7127:1337:ffff:ffff:ffff:ffff")
 				tt.config(cfg)
@@ -84,7 +102,7 @@ func TestIptablesHostRules(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				compareToGolden(t, ipv6, tt.name, ext.ExecutedAll)
+				compareToGolden(t, family, tt.name, ext.ExecutedAll)
 			})
 		}
 	}
@@ -103,7 +121,7 @@ func TestInvokedTwiceIsIdempotent(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			compareToGolden(t, false, tt.name, ext.ExecutedAll)
+			compareToGolden(t, ipv4, tt.name, ext.ExecutedAll)
 
 			*ext = dep.DependenciesStub{}
 			// run another time to make sure we are idempotent
@@ -111,24 +129,33 @@ func TestInvokedTwiceIsIdempotent(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			compareToGolden(t, false, tt.name, ext.ExecutedAll)
+			compareToGolden(t, ipv4, tt.name, ext.ExecutedAll)
 		})
 	}
 }
 
-func ipstr(ipv6 bool) string {
-	if ipv6 {
-		return "ipv6"
-	}
-	return "ipv4"
+// ipFamily is the IP family setup rules are generated for. Each has its own golden file.
+type ipFamily string
+
+const (
+	ipv4      ipFamily = "ipv4"
+	dualStack ipFamily = "ipv6"
+	ipv6Only  ipFamily = "ipv6only"
+)
+
+var ipFamilies = []ipFamily{ipv4, dualStack, ipv6Only}
+
+func (f ipFamily) configure(cfg *config.AmbientConfig) {
+	cfg.EnableIPv6 = f != ipv4
+	cfg.IPv6Only = f == ipv6Only
 }
 
-func compareToGolden(t *testing.T, ipv6 bool, name string, actual []string) {
+func compareToGolden(t *testing.T, family ipFamily, name string, actual []string) {
 	t.Helper()
 	gotBytes := []byte(strings.Join(actual, "\n"))
 	goldenFile := filepath.Join("testdata", name+".golden")
-	if ipv6 {
-		goldenFile = filepath.Join("testdata", name+"_ipv6.golden")
+	if family != ipv4 {
+		goldenFile = filepath.Join("testdata", name+"_"+string(family)+".golden")
 	}
 	testutil.CompareContent(t, gotBytes, goldenFile)
 }
@@ -30,12 +30,13 @@ type HostNftSetManager struct {
 	v4SetName   string
 	v6SetName   string
 	prefix      string
+	enableIPv4  bool
 	enableIPv6  bool
 	nftProvider NftProviderFunc
 }
 
 // NewHostSetManager creates a new nftables-based host set manager
-func NewHostSetManager(setPrefix string, enableIPv6 bool) (*HostNftSetManager, error) {
+func NewHostSetManager(setPrefix string, enableIPv4 bool, enableIPv6 bool) (*HostNftSetManager, error) {
 	// Lets use the same naming convention as ipset
 	v4SetName := fmt.Sprintf("%s-v4", setPrefix)
 	v6SetName := fmt.Sprintf("%s-v6", setPrefix)
@@ -52,6 +53,7 @@ func NewHostSetManager(setPrefix string, enableIPv6 bool) (*HostNftSetManager, e
 		v4SetName:   v4SetName,
 		v6SetName:   v6SetName,
 		prefix:      setPrefix,
+		enableIPv4:  enableIPv4,
 		enableIPv6:  enableIPv6,
 		nftProvider: nftProvider,
 	}
@@ -76,12 +78,14 @@ func (h *HostNftSetManager) initializeSets() error {
 	// Create table
 	tx.Add(&knftables.Table{})
 
-	// Create IPv4 set
-	tx.Add(&knftables.Set{
-		Name:    h.v4SetName,
-		Type:    "ipv4_addr",
-		Comment: knftables.PtrTo("UUID of the pods that are part of ambient mesh"),
-	})
+	// Create IPv4 set if enabled
+	if h.enableIPv4 {
+		tx.Add(&knftables.Set{
+			Name:    h.v4SetName,
+			Type:    "ipv4_addr",
+			Comment: knftables.PtrTo("UUID of the pods that are part of ambient mesh"),
+		})
+	}
 
 	// Create IPv6 set if enabled
 	if h.enableIPv6 {
@@ -103,13 +107,10 @@ func (h *HostNftSetManager) initializeSets() error {
 // AddIP adds an IP address to the appropriate set (v4 or v6)
 func (h *HostNftSetManager) AddIP(ip netip.Addr, _ uint8, comment string, replace bool) error {
 	ipToInsert := ip.Unmap()
-	setName := h.v4SetName
-	if ipToInsert.Is6() {
-		if !h.enableIPv6 {
-			log.Debugf("IPv6 is not enabled. Skipping the addition of IP: %v", ip)
-			return nil
-		}
-		setName = h.v6SetName
+	setName, enabled := h.setFor(ipToInsert)
+	if !enabled {
+		log.Debugf("IP family is not enabled. Skipping the addition of IP: %v", ip)
+		return nil
 	}
 
 	nft, err := h.nftProvider(knftables.InetFamily, AmbientNatTable)
@@ -142,13 +143,10 @@ func (h *HostNftSetManager) DeleteIP(ip netip.Addr, _ uint8) error {
 	}
 
 	ipToDel := ip.Unmap()
-	setName := h.v4SetName
-	if ipToDel.Is6() {
-		if !h.enableIPv6 {
-			log.Debugf("IPv6 is not enabled. Skipping the deletion of IP: %v", ip)
-			return nil
-		}
-		setName = h.v6SetName
+	setName, enabled := h.setFor(ipToDel)
+	if !enabled {
+		log.Debugf("IP family is not enabled. Skipping the deletion of IP: %v", ip)
+		return nil
 	}
 
 	tx := nft.NewTransaction()
@@ -176,10 +174,12 @@ func (h *HostNftSetManager) Flush() error {
 
 	tx := nft.NewTransaction()
 
-	// Flush IPv4 set
-	tx.Flush(&knftables.Set{
-		Name: h.v4SetName,
-	})
+	// Flush IPv4 set if enabled
+	if h.enableIPv4 {
+		tx.Flush(&knftables.Set{
+			Name: h.v4SetName,
+		})
+	}
 
 	// Flush IPv6 set if enabled
 	if h.enableIPv6 {
@@ -224,7 +224,9 @@ func (h *HostNftSetManager) ClearEntriesWithComment(comment string) error {
 	}
 
 	// A pod can have multiple IPs, so we have to clear it from both v4 and v6 sets.
-	err = h.clearEntriesFromSetWithComment(nft, h.v4SetName, comment)
+	if h.enableIPv4 {
+		err = h.clearEntriesFromSetWithComment(nft, h.v4SetName, comment)
+	}
 
 	// Clear from IPv6 set if enabled
 	if h.enableIPv6 {
@@ -252,12 +254,9 @@ func (h *HostNftSetManager) ClearEntriesWithIPAndComment(ip netip.Addr, comment
 	}
 
 	ipToCheck := ip.Unmap()
-	setName := h.v4SetName
-	if ipToCheck.Is6() {
-		if !h.enableIPv6 {
-			return "", fmt.Errorf("request to delete %s from the set, but ipv6 is not enabled", ipToCheck.String())
-		}
-		setName = h.v6SetName
+	setName, enabled := h.setFor(ipToCheck)
+	if !enabled {
+		return "", fmt.Errorf("request to delete %s from the set, but its IP family is not enabled", ipToCheck.String())
 	}
 
 	elements, err := nft.ListElements(context.TODO(), "set", setName)
@@ -307,18 +306,20 @@ func (h *HostNftSetManager) ListEntriesByIP() ([]netip.Addr, error) {
 
 	var allIPs []netip.Addr
 
-	// List elements from IPv4 set
-	v4Elements, err := nft.ListElements(context.TODO(), "set", h.v4SetName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list elements from IPv4 set %s: %w", h.v4SetName, err)
-	}
+	// List elements from IPv4 set if enabled
+	if h.enableIPv4 {
+		v4Elements, err := nft.ListElements(context.TODO(), "set", h.v4SetName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list elements from IPv4 set %s: %w", h.v4SetName, err)
+		}
 
-	for _, elem := range v4Elements {
-		if len(elem.Key) > 0 {
-			if ip, err := netip.ParseAddr(elem.Key[0]); err == nil {
-				allIPs = append(allIPs, ip)
-			} else {
-				log.Warnf("Failed to parse IPv4 address %s: %v", elem.Key[0], err)
+		for _, elem := range v4Elements {
+			if len(elem.Key) > 0 {
+				if ip, err := netip.ParseAddr(elem.Key[0]); err == nil {
+					allIPs = append(allIPs, ip)
+				} else {
+					log.Warnf("Failed to parse IPv4 address %s: %v", elem.Key[0], err)
+				}
 			}
 		}
 	}
@@ -344,6 +345,14 @@ func (h *HostNftSetManager) ListEntriesByIP() ([]netip.Addr, error) {
 	return allIPs, nil
 }
 
+// setFor returns the name of the set for the given (Unmap'd) IP, and whether its IP family is enabled.
+func (h *HostNftSetManager) setFor(ip netip.Addr) (string, bool) {
+	if ip.Is6() {
+		return h.v6SetName, h.enableIPv6
+	}
+	return h.v4SetName, h.enableIPv4
+}
+
 // clearEntriesFromSetWithComment is a helper function to clear entries with a specific comment from a set
 func (h *HostNftSetManager) clearEntriesFromSetWithComment(nft builder.NftablesAPI, setName, comment string) error {
 	elements, err := nft.ListElements(context.TODO(), "set", setName)
@@ -218,7 +218,7 @@ func (cfg *NftablesConfigurator) AppendInpodRules(podOverrides config.PodLevelOv
 		// CLI: nft add rule inet istio-ambient-nat istio-prerouting meta l4proto tcp ip saddr 169.254.7.127 counter accept
 		//
 		// DESC: If this is one of our node-probe ports and is from our SNAT-ed/"special" hostside IP, short-circuit out here
-		rb.AppendRule(IstioPreroutingChain, AmbientNatTable,
+		cfg.appendV4RuleIfSupported(rb, IstioPreroutingChain, AmbientNatTable,
 			"meta l4proto tcp",
 			"ip saddr", cfg.cfg.HostProbeSNATAddress.String(), Counter,
 			"accept",
@@ -235,7 +235,7 @@ func (cfg *NftablesConfigurator) AppendInpodRules(podOverrides config.PodLevelOv
 
 	// CLI: nft add rule inet istio-ambient-nat istio-output meta l4proto tcp ip daddr 169.254.7.127 counter accept
 	// DESC: Anything coming BACK from the pod healthcheck port with a dest of our SNAT-ed hostside IP we also short-circuit.
-	rb.AppendRule(IstioOutputChain, AmbientNatTable,
+	cfg.appendV4RuleIfSupported(rb, IstioOutputChain, AmbientNatTable,
 		"meta l4proto tcp",
 		"ip daddr", cfg.cfg.HostProbeSNATAddress.String(), Counter,
 		"accept",
@@ -254,7 +254,7 @@ func (cfg *NftablesConfigurator) AppendInpodRules(podOverrides config.PodLevelOv
 		//
 		// DESC: Anything that is not bound for localhost and does not have the mark, REDIRECT to ztunnel inbound plaintext port <INPLAINPORT>
 		// Skip 15008, which will go direct without redirect needed.
-		rb.AppendRule(IstioPreroutingChain, AmbientNatTable,
+		cfg.appendV4RuleIfSupported(rb, IstioPreroutingChain, AmbientNatTable,
 			"ip daddr", "!=", "127.0.0.1/32",
 			"tcp dport", "!=", fmt.Sprint(config.ZtunnelInboundPort),
 			"mark and 0xfff ", "!=", fmt.Sprintf("0x%x", config.InpodMark), Counter,
@@ -293,7 +293,7 @@ func (cfg *NftablesConfigurator) AppendInpodRules(podOverrides config.PodLevelOv
 		)
 
 		// CLI: nft add rule inet istio-ambient-nat istio-output ip daddr != 127.0.0.1/32 tcp dport 53 mark and 0xfff != 0x539 counter redirect to :15053
-		rb.AppendRule(
+		cfg.appendV4RuleIfSupported(rb,
 			IstioOutputChain, AmbientNatTable,
 			"ip daddr", "!=", "127.0.0.1/32",
 			"tcp dport", "53",
@@ -349,7 +349,7 @@ func (cfg *NftablesConfigurator) AppendInpodRules(podOverrides config.PodLevelOv
 	// Do not redirect app calls to back itself via Ztunnel when using the endpoint address
 	// e.g. appN => appN by lo
 	// CLI: nft add rule inet istio-ambient-nat istio-output oifname "lo" ip daddr != 127.0.0.1 counter accept
-	rb.AppendRule(
+	cfg.appendV4RuleIfSupported(rb,
 		IstioOutputChain, AmbientNatTable,
 		"oifname", "lo",
 		"ip daddr",
@@ -369,7 +369,7 @@ func (cfg *NftablesConfigurator) AppendInpodRules(podOverrides config.PodLevelOv
 	// CLI: nft add rule inet istio-ambient-nat istio-output meta l4proto tcp ip daddr != 127.0.0.1 mark and 0xfff != 0x539 counter redirect to :15001
 	//
 	// DESC: If this is outbound, not bound for localhost, and does not have our packet mark, redirect to ztunnel proxy <OUTPORT>
-	rb.AppendRule(
+	cfg.appendV4RuleIfSupported(rb,
 		IstioOutputChain, AmbientNatTable,
 		"meta l4proto tcp",
 		"ip daddr",
@@ -392,6 +392,14 @@ func (cfg *NftablesConfigurator) AppendInpodRules(podOverrides config.PodLevelOv
 	return cfg.executeCommands(rb)
 }
 
+// appendV4RuleIfSupported appends a rule only if IPv4 is in use, the IPv4 counterpart of
+// builder.AppendV6RuleIfSupported.
+func (cfg *NftablesConfigurator) appendV4RuleIfSupported(rb *builder.NftablesRuleBuilder, chain string, table string, params ...string) {
+	if cfg.cfg.IPv4Enabled() {
+		rb.AppendRule(chain, table, params...)
+	}
+}
+
 // DeleteInpodRules removes nftables rules from a pod's network namespace
 func (cfg *NftablesConfigurator) DeleteInpodRules(log *istiolog.Scope) error {
 	log.Info("removing nftables inpod rules")
@@ -471,7 +479,7 @@ func (cfg *NftablesConfigurator) CreateHostRulesForHealthChecks() error {
 	//	It is portable across cgroup versions and k8s distributions. Also, it's slightly stricter than
 	//  iptables "--socket-exists" match which only checks for host-originated sockets.
 
-	rb.AppendRule(PostroutingChain, AmbientNatTable, "meta l4proto tcp", "skuid", kubeletUID,
+	cfg.appendV4RuleIfSupported(rb, PostroutingChain, AmbientNatTable, "meta l4proto tcp", "skuid", kubeletUID,
 		"ip", "daddr", fmt.Sprintf("@%s-v4", config.ProbeIPSet), Counter, "snat", "to", cfg.cfg.HostProbeSNATAddress.String())
 
 	// For V6 we have to use a different set and a different SNAT IP
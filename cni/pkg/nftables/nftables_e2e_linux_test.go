@@ -50,7 +50,7 @@ func dumpRuleset(t *testing.T) string {
 func createHostsideProbeNftSets(t *testing.T) func() {
 	t.Helper()
 
-	setManager, err := NewHostSetManager(config.ProbeIPSet, true)
+	setManager, err := NewHostSetManager(config.ProbeIPSet, true, true)
 	if err != nil {
 		t.Fatalf("Failed to create host set manager: %v", err)
 	}
@@ -80,10 +80,10 @@ func TestNftablesPodOverrides(t *testing.T) {
 	cases := GetCommonInPodTestCases()
 
 	for _, tt := range cases {
-		for _, ipv6 := range []bool{false, true} {
-			t.Run(tt.name+"_"+ipstr(ipv6), func(t *testing.T) {
+		for _, family := range ipFamilies {
+			t.Run(tt.name+"_"+string(family), func(t *testing.T) {
 				cfg := constructTestConfig()
-				cfg.EnableIPv6 = ipv6
+				family.configure(cfg)
 				tt.config(cfg)
 				ext := &dep.DependenciesStub{}
 
@@ -102,7 +102,7 @@ func TestNftablesPodOverrides(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				compareToGolden(t, ipv6, tt.name, mock.GetCapturedRules())
+				compareToGolden(t, family, tt.name, mock.GetCapturedRules())
 			})
 		}
 	}
@@ -112,10 +112,10 @@ func TestNftablesHostRules(t *testing.T) {
 	cases := GetCommonHostTestCases()
 
 	for _, tt := range cases {
-		for _, ipv6 := range []bool{false, true} {
-			t.Run(tt.name+"_"+ipstr(ipv6), func(t *testing.T) {
+		for _, family := range ipFamilies {
+			t.Run(tt.name+"_"+string(family), func(t *testing.T) {
 				cfg := constructTestConfig()
-				cfg.EnableIPv6 = ipv6
+				family.configure(cfg)
 				tt.config(cfg)
 				ext := &dep.DependenciesStub{}
 
@@ -142,7 +142,7 @@ func TestNftablesHostRules(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				compareToGolden(t, ipv6, tt.name, mock.GetCapturedRules())
+				compareToGolden(t, family, tt.name, mock.GetCapturedRules())
 			})
 		}
 	}
@@ -171,7 +171,7 @@ func TestInvokedTwiceIsIdempotent(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			compareToGolden(t, false, tt.name, mock.GetCapturedRules())
+			compareToGolden(t, ipv4, tt.name, mock.GetCapturedRules())
 
 			// Reset the mock to capture only the second run
 			mock = NewMockNftablesCapture()
@@ -184,7 +184,7 @@ func TestInvokedTwiceIsIdempotent(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			compareToGolden(t, false, tt.name, mock.GetCapturedRules())
+			compareToGolden(t, ipv4, tt.name, mock.GetCapturedRules())
 		})
 	}
 }
@@ -228,19 +228,28 @@ func TestCreateInpodRulesConcurrent(t *testing.T) {
 	wg.Wait()
 }
 
-func ipstr(ipv6 bool) string {
-	if ipv6 {
-		return "ipv6"
-	}
-	return "ipv4"
+// ipFamily is the IP family setup rules are generated for. Each has its own golden file.
+type ipFamily string
+
+const (
+	ipv4      ipFamily = "ipv4"
+	dualStack ipFamily = "ipv6"
+	ipv6Only  ipFamily = "ipv6only"
+)
+
+var ipFamilies = []ipFamily{ipv4, dualStack, ipv6Only}
+
+func (f ipFamily) configure(cfg *config.AmbientConfig) {
+	cfg.EnableIPv6 = f != ipv4
+	cfg.IPv6Only = f == ipv6Only
 }
 
-func compareToGolden(t *testing.T, ipv6 bool, name string, actual []string) {
+func compareToGolden(t *testing.T, family ipFamily, name string, actual []string) {
 	t.Helper()
 	gotBytes := []byte(strings.Join(actual, "\n"))
 	goldenFile := filepath.Join("testdata", name+".golden")
-	if ipv6 {
-		goldenFile = filepath.Join("testdata", name+"_ipv6.golden")
+	if family != ipv4 {
+		goldenFile = filepath.Join("testdata", name+"_"+string(family)+".golden")
 	}
 	testutil.CompareContent(t, gotBytes, goldenFile)
 }
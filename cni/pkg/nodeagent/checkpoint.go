@@ -0,0 +1,323 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodeagent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"istio.io/istio/cni/pkg/config"
+	"istio.io/istio/pkg/util/sets"
+)
+
+// checkpointVersion is bumped whenever the on-disk format changes in a way older agents cannot read.
+// A checkpoint with a different version is discarded on load.
+const checkpointVersion = 1
+
+// checkpointFlushInterval is how long changes are collected before the checkpoint is written. Pods are often enrolled
+// and removed in bulk (node startup, drain), so writing once per change would rewrite the whole file for every pod.
+const checkpointFlushInterval = time.Second
+
+// PodCheckpointEntry is the persisted state of a single enrolled pod.
+type PodCheckpointEntry struct {
+	UID       string `json:"uid"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// NetnsPath is the netns path reported by the CNI plugin, if known.
+	// Pods discovered by scanning procfs do not have one.
+	NetnsPath  string `json:"netnsPath,omitempty"`
+	NetnsInode uint64 `json:"netnsInode"`
+	// RuleBackend is the traffic rule backend ("iptables" or "nftables") the in-pod rules were programmed with.
+	RuleBackend string `json:"ruleBackend"`
+	// RuleHash identifies the set of in-pod rules that was programmed. If the node agent
+	// version, its configuration or the pod level overrides change, so does the hash.
+	RuleHash string `json:"ruleHash"`
+}
+
+type podCheckpointFile struct {
+	Version int                  `json:"version"`
+	Pods    []PodCheckpointEntry `json:"pods"`
+}
+
+// podStateCheckpoint persists the enrolled pod state of the node agent to a (host path) file,
+// so that a restarted node agent can resume from it and only touch pods whose state differs.
+//
+// Changes are written by Run, at most once per checkpointFlushInterval, and on shutdown. Changes lost in a crash
+// only mean more work on the next startup, as the checkpointed state of every pod is verified before it is used.
+//
+// A nil *podStateCheckpoint is valid, and behaves as a disabled checkpoint.
+type podStateCheckpoint struct {
+	path    string
+	backend string
+	// ruleSeed is mixed into every rule hash; it captures node-wide state (agent version,
+	// backend, pod traffic config) that affects the in-pod rules of every pod.
+	ruleSeed string
+
+	mu      sync.Mutex
+	entries map[string]PodCheckpointEntry
+	// dirty is set if entries changed since the checkpoint was last written.
+	dirty bool
+
+	// flushMu serializes writes of the checkpoint file.
+	flushMu sync.Mutex
+	// changed is notified when entries change, to trigger a write.
+	changed chan struct{}
+}
+
+// newPodStateCheckpoint creates a checkpoint backed by the file at path, loading any existing state.
+// A missing, unreadable or incompatible checkpoint file is not an error - the node agent simply
+// falls back to rebuilding its state from scratch.
+func newPodStateCheckpoint(path string, backend string, ruleSeed string) *podStateCheckpoint {
+	c := &podStateCheckpoint{
+		path:     path,
+		backend:  backend,
+		ruleSeed: ruleSeed,
+		entries:  map[string]PodCheckpointEntry{},
+		changed:  make(chan struct{}, 1),
+	}
+	entries, err := readPodCheckpoint(path)
+	if err != nil {
+		log.Warnf("ignoring pod state checkpoint %s: %v", path, err)
+		return c
+	}
+	for _, e := range entries {
+		c.entries[e.UID] = e
+	}
+	log.Infof("loaded pod state checkpoint %s with %d pods", path, len(c.entries))
+	return c
+}
+
+func readPodCheckpoint(path string) ([]PodCheckpointEntry, error) {
+	by, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f podCheckpointFile
+	if err := json.Unmarshal(by, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	if f.Version != checkpointVersion {
+		return nil, fmt.Errorf("unsupported checkpoint version %d (expected %d)", f.Version, checkpointVersion)
+	}
+	return f.Pods, nil
+}
+
+// ruleSeedFor computes the node-wide portion of the rule hash.
+func ruleSeedFor(agentVersion string, backend string, podCfg *config.AmbientConfig) string {
+	cfg, _ := json.Marshal(podCfg)
+	return agentVersion + "/" + backend + "/" + string(cfg)
+}
+
+// RuleHash returns the hash identifying the in-pod rules this node agent programs for the given overrides.
+func (c *podStateCheckpoint) RuleHash(overrides config.PodLevelOverrides) string {
+	if c == nil {
+		return ""
+	}
+	o, _ := json.Marshal(overrides)
+	sum := sha256.Sum256([]byte(c.ruleSeed + "/" + string(o)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns the checkpointed state of the pod with the given uid, if any.
+func (c *podStateCheckpoint) Get(uid string) (PodCheckpointEntry, bool) {
+	if c == nil {
+		return PodCheckpointEntry{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, f := c.entries[uid]
+	return e, f
+}
+
+// Matches returns true if the checkpointed state of the pod matches the given netns and rules exactly,
+// in which case the pod does not need to be touched.
+func (c *podStateCheckpoint) Matches(pod *corev1.Pod, netns Netns, ruleHash string) bool {
+	if netns == nil {
+		return false
+	}
+	e, f := c.Get(string(pod.UID))
+	return f &&
+		e.NetnsInode == netns.Inode() &&
+		e.RuleBackend == c.backend &&
+		e.RuleHash == ruleHash
+}
+
+// Record stores the state of a pod that was successfully enrolled.
+// nspath may be empty if the netns path is not known, in which case a previously recorded path
+// for the same netns is kept.
+func (c *podStateCheckpoint) Record(pod *corev1.Pod, netns Netns, nspath string, ruleHash string) {
+	if c == nil || netns == nil {
+		return
+	}
+	uid := string(pod.UID)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if nspath == "" {
+		if old, f := c.entries[uid]; f && old.NetnsInode == netns.Inode() {
+			nspath = old.NetnsPath
+		}
+	}
+	c.entries[uid] = PodCheckpointEntry{
+		UID:         uid,
+		Namespace:   pod.Namespace,
+		Name:        pod.Name,
+		NetnsPath:   nspath,
+		NetnsInode:  netns.Inode(),
+		RuleBackend: c.backend,
+		RuleHash:    ruleHash,
+	}
+	c.markDirtyUnderLock()
+}
+
+// Remove drops the pod from the checkpoint.
+func (c *podStateCheckpoint) Remove(uid string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, f := c.entries[uid]; !f {
+		return
+	}
+	delete(c.entries, uid)
+	c.markDirtyUnderLock()
+}
+
+// Retain drops every pod not in the given set of uids.
+// This is used at startup, to forget pods that went away while the node agent was not running.
+func (c *podStateCheckpoint) Retain(uids sets.String) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for uid := range c.entries {
+		if !uids.Contains(uid) {
+			log.Debugf("dropping stale pod %s from checkpoint", uid)
+			delete(c.entries, uid)
+		}
+	}
+	c.markDirtyUnderLock()
+}
+
+func (c *podStateCheckpoint) markDirtyUnderLock() {
+	c.dirty = true
+	select {
+	case c.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Run writes the checkpoint whenever it changes, coalescing the changes made within checkpointFlushInterval, until
+// the context is done. Pending changes are written before it returns.
+func (c *podStateCheckpoint) Run(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	defer c.Flush()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.changed:
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(checkpointFlushInterval):
+		}
+		c.Flush()
+	}
+}
+
+// Flush writes the checkpoint if it changed since it was last written.
+func (c *podStateCheckpoint) Flush() {
+	if c == nil {
+		return
+	}
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	c.mu.Lock()
+	if !c.dirty {
+		c.mu.Unlock()
+		return
+	}
+	c.dirty = false
+	by, err := c.marshalUnderLock()
+	c.mu.Unlock()
+	if err != nil {
+		log.Errorf("failed to marshal pod state checkpoint: %v", err)
+		return
+	}
+	if err := writeFileAtomic(c.path, by); err != nil {
+		// Failing to checkpoint is never fatal, the next startup will just do more work.
+		log.Errorf("failed to write pod state checkpoint %s: %v", c.path, err)
+		c.mu.Lock()
+		c.dirty = true
+		c.mu.Unlock()
+	}
+}
+
+func (c *podStateCheckpoint) marshalUnderLock() ([]byte, error) {
+	f := podCheckpointFile{
+		Version: checkpointVersion,
+		Pods:    make([]PodCheckpointEntry, 0, len(c.entries)),
+	}
+	for _, e := range c.entries {
+		f.Pods = append(f.Pods, e)
+	}
+	sort.Slice(f.Pods, func(i, j int) bool {
+		return f.Pods[i].UID < f.Pods[j].UID
+	})
+	return json.Marshal(f)
+}
+
+// writeFileAtomic writes the file via a temporary file and rename, so that a crash mid-write
+// never leaves a truncated checkpoint behind.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,177 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodeagent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/istio/cni/pkg/config"
+	"istio.io/istio/pkg/test/util/assert"
+	"istio.io/istio/pkg/test/util/retry"
+	"istio.io/istio/pkg/util/sets"
+	"istio.io/istio/tools/istio-iptables/pkg/dependencies"
+)
+
+func checkpointTestPod() *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "foo",
+		Namespace: "bar",
+		UID:       "863b91d4-4b68-4efa-917f-4b560e3e86aa",
+	}}
+}
+
+func TestPodStateCheckpointRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pod-state.json")
+	pod := checkpointTestPod()
+	ns := newFakeNsInode(1, 42)
+
+	c := newPodStateCheckpoint(path, "iptables", "seed")
+	hash := c.RuleHash(config.PodLevelOverrides{})
+	c.Record(pod, ns, "/var/run/netns/foo", hash)
+	c.Flush()
+
+	// A fresh checkpoint should resume the same state from disk
+	reloaded := newPodStateCheckpoint(path, "iptables", "seed")
+	entry, f := reloaded.Get(string(pod.UID))
+	assert.Equal(t, f, true)
+	assert.Equal(t, entry, PodCheckpointEntry{
+		UID:         string(pod.UID),
+		Namespace:   "bar",
+		Name:        "foo",
+		NetnsPath:   "/var/run/netns/foo",
+		NetnsInode:  42,
+		RuleBackend: "iptables",
+		RuleHash:    hash,
+	})
+	assert.Equal(t, reloaded.Matches(pod, ns, hash), true)
+
+	// Any difference in netns, rules or backend is a mismatch
+	assert.Equal(t, reloaded.Matches(pod, newFakeNsInode(2, 43), hash), false)
+	assert.Equal(t, reloaded.Matches(pod, ns, reloaded.RuleHash(config.PodLevelOverrides{IngressMode: true})), false)
+	assert.Equal(t, newPodStateCheckpoint(path, "nftables", "seed").Matches(pod, ns, hash), false)
+	assert.Equal(t, newPodStateCheckpoint(path, "iptables", "other-seed").RuleHash(config.PodLevelOverrides{}) == hash, false)
+
+	// Recording without a path keeps the previously known path for the same netns
+	reloaded.Record(pod, ns, "", hash)
+	entry, _ = reloaded.Get(string(pod.UID))
+	assert.Equal(t, entry.NetnsPath, "/var/run/netns/foo")
+
+	reloaded.Retain(sets.New[string]())
+	reloaded.Flush()
+	_, f = newPodStateCheckpoint(path, "iptables", "seed").Get(string(pod.UID))
+	assert.Equal(t, f, false)
+}
+
+func TestPodStateCheckpointRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pod-state.json")
+	pod := checkpointTestPod()
+	c := newPodStateCheckpoint(path, "iptables", "seed")
+	persisted := func() bool {
+		_, f := newPodStateCheckpoint(path, "iptables", "seed").Get(string(pod.UID))
+		return f
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.Run(ctx)
+		close(done)
+	}()
+
+	// Changes are written in the background
+	c.Record(pod, newFakeNsInode(1, 42), "/var/run/netns/foo", c.RuleHash(config.PodLevelOverrides{}))
+	retry.UntilOrFail(t, persisted, retry.Timeout(5*time.Second))
+
+	// Pending changes are written on shutdown
+	c.Remove(string(pod.UID))
+	cancel()
+	<-done
+	assert.Equal(t, persisted(), false)
+}
+
+func TestPodStateCheckpointIgnoresInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pod-state.json")
+	assert.NoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+	c := newPodStateCheckpoint(path, "iptables", "seed")
+	_, f := c.Get(string(checkpointTestPod().UID))
+	assert.Equal(t, f, false)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"version":999,"pods":[{"uid":"863b91d4-4b68-4efa-917f-4b560e3e86aa"}]}`), 0o600))
+	c = newPodStateCheckpoint(path, "iptables", "seed")
+	_, f = c.Get(string(checkpointTestPod().UID))
+	assert.Equal(t, f, false)
+}
+
+func TestNilPodStateCheckpoint(t *testing.T) {
+	var c *podStateCheckpoint
+	pod := checkpointTestPod()
+	c.Record(pod, newFakeNsInode(1, 1), "", "")
+	c.Remove(string(pod.UID))
+	c.Retain(sets.New[string]())
+	c.Flush()
+	c.Run(context.Background())
+	assert.Equal(t, c.Matches(pod, newFakeNsInode(1, 1), c.RuleHash(config.PodLevelOverrides{})), false)
+}
+
+func TestConstructInitialSnapResumesFromCheckpoint(t *testing.T) {
+	cases := []struct {
+		name           string
+		inode          uint64
+		expectedRescan bool
+	}{
+		{name: "unchanged pod is not touched", inode: 42, expectedRescan: false},
+		{name: "changed netns is reconciled", inode: 43, expectedRescan: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			setupLogging()
+
+			podCfg := config.AmbientConfig{Reconcile: true}
+			fakeDeps := &dependencies.DependenciesStub{}
+			fixture := getTestFixureWithIptablesConfig(ctx, fakeDeps, &podCfg, &podCfg)
+			fixture.podNsMap.openNetns = openNsTestOverrideWithInodes(tt.inode)
+			netServer := fixture.netServer
+			pod := checkpointTestPod()
+
+			path := filepath.Join(t.TempDir(), "pod-state.json")
+			netServer.checkpoint = newPodStateCheckpoint(path, "iptables", "seed")
+			hash := netServer.checkpoint.RuleHash(getPodLevelTrafficOverrides(pod))
+			netServer.checkpoint.Record(pod, newFakeNsInode(1, 42), "/var/run/netns/foo", hash)
+			stale := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "gone", Namespace: "bar", UID: "gone"}}
+			netServer.checkpoint.Record(stale, newFakeNsInode(2, 7), "/var/run/netns/gone", hash)
+
+			assert.NoError(t, netServer.ConstructInitialSnapshot([]*corev1.Pod{pod}))
+			if fixture.podNsMap.Get(string(pod.UID)) == nil {
+				t.Fatal("expected pod to be in cache")
+			}
+			assert.Equal(t, len(fakeDeps.ExecutedAll) != 0, tt.expectedRescan)
+
+			// Pods that are no longer around are dropped from the checkpoint
+			_, f := netServer.checkpoint.Get("gone")
+			assert.Equal(t, f, false)
+			_, f = netServer.checkpoint.Get(string(pod.UID))
+			assert.Equal(t, f, true)
+		})
+	}
+}
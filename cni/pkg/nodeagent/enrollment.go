@@ -0,0 +1,146 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodeagent
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// EnrollmentFailureReason classifies why the node agent failed to enroll a pod in the mesh.
+type EnrollmentFailureReason string
+
+const (
+	// EnrollmentFailureNetns means the pod netns could not be found or opened.
+	EnrollmentFailureNetns EnrollmentFailureReason = "NetnsError"
+	// EnrollmentFailureInpodRules means the in-pod traffic redirection rules could not be created.
+	EnrollmentFailureInpodRules EnrollmentFailureReason = "InpodRulesError"
+	// EnrollmentFailureZtunnel means ztunnel was not connected, or did not ack the pod.
+	EnrollmentFailureZtunnel EnrollmentFailureReason = "ZtunnelError"
+)
+
+// EnrollmentResult is the outcome of the latest attempt to enroll a pod in the mesh.
+type EnrollmentResult struct {
+	Pod types.NamespacedName
+	UID types.UID
+	// Reason is empty if the pod was enrolled successfully.
+	Reason EnrollmentFailureReason
+	Err    error
+}
+
+// Failed returns true if the enrollment attempt failed.
+func (r EnrollmentResult) Failed() bool {
+	return r.Reason != ""
+}
+
+// EnrollmentResults tracks the latest enrollment result of each pod on the node, so that
+// other components (such as the repair controller) can act on enrollment failures.
+//
+// A nil *EnrollmentResults is valid, and drops all results.
+type EnrollmentResults struct {
+	mu       sync.RWMutex
+	results  map[types.NamespacedName]EnrollmentResult
+	handlers []func(EnrollmentResult)
+	retry    func(pod *corev1.Pod)
+}
+
+func NewEnrollmentResults() *EnrollmentResults {
+	return &EnrollmentResults{
+		results: map[types.NamespacedName]EnrollmentResult{},
+	}
+}
+
+// AddHandler registers a handler that is called (synchronously) with every new enrollment result.
+// Handlers must not block.
+func (r *EnrollmentResults) AddHandler(h func(EnrollmentResult)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers = append(r.handlers, h)
+}
+
+// Get returns the latest enrollment result for the given pod.
+func (r *EnrollmentResults) Get(key types.NamespacedName) (EnrollmentResult, bool) {
+	if r == nil {
+		return EnrollmentResult{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	res, f := r.results[key]
+	return res, f
+}
+
+// Retry asks the node agent to attempt to enroll the pod again.
+// Returns false if the node agent is not able to retry.
+func (r *EnrollmentResults) Retry(pod *corev1.Pod) bool {
+	if r == nil {
+		return false
+	}
+	r.mu.RLock()
+	retry := r.retry
+	r.mu.RUnlock()
+	if retry == nil {
+		return false
+	}
+	retry(pod)
+	return true
+}
+
+// SetRetry registers the function used to retry the enrollment of a pod.
+func (r *EnrollmentResults) SetRetry(retry func(pod *corev1.Pod)) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retry = retry
+}
+
+func (r *EnrollmentResults) reportFailure(pod *corev1.Pod, reason EnrollmentFailureReason, err error) {
+	r.Report(EnrollmentResult{Pod: podKey(pod), UID: pod.UID, Reason: reason, Err: err})
+}
+
+func (r *EnrollmentResults) reportSuccess(pod *corev1.Pod) {
+	r.Report(EnrollmentResult{Pod: podKey(pod), UID: pod.UID})
+}
+
+// Report records a new enrollment result, and notifies all handlers.
+func (r *EnrollmentResults) Report(res EnrollmentResult) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.results[res.Pod] = res
+	handlers := r.handlers
+	r.mu.Unlock()
+	for _, h := range handlers {
+		h(res)
+	}
+}
+
+// forget drops the result for a pod that was removed from the mesh.
+func (r *EnrollmentResults) forget(pod *corev1.Pod) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.results, podKey(pod))
+}
+
+func podKey(pod *corev1.Pod) types.NamespacedName {
+	return types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+}
@@ -196,16 +196,21 @@ func (s *InformerHandlers) enqueueNamespace(o controllers.Object) {
 		// spurious events for them to avoid triggering extra
 		// ztunnel node reconciliation checks.
 		if !util.IsZtunnelPod(s.systemNamespace, pod) {
-			log.Debugf("Enqueuing pod %s/%s", pod.Namespace, pod.Name)
-			s.queue.Add(controllers.Event{
-				New:   pod,
-				Old:   pod,
-				Event: controllers.EventUpdate,
-			})
+			s.enqueuePod(pod)
 		}
 	}
 }
 
+// enqueuePod fires a synthetic update event for the pod, which will (re)enroll or remove it as needed.
+func (s *InformerHandlers) enqueuePod(pod *corev1.Pod) {
+	log.Debugf("Enqueuing pod %s/%s", pod.Namespace, pod.Name)
+	s.queue.Add(controllers.Event{
+		New:   pod,
+		Old:   pod,
+		Event: controllers.EventUpdate,
+	})
+}
+
 func (s *InformerHandlers) isNamespaceExcluded(namespace string) bool {
 	_, excluded := s.excludeNamespaces[namespace]
 	return excluded
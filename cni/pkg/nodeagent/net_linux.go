@@ -25,6 +25,7 @@ import (
 
 	"istio.io/istio/cni/pkg/trafficmanager"
 	"istio.io/istio/pkg/slices"
+	"istio.io/istio/pkg/util/sets"
 )
 
 // Adapts CNI to ztunnel server. decoupled from k8s for easier integration testing.
@@ -33,6 +34,10 @@ type NetServer struct {
 	currentPodSnapshot *podNetnsCache
 	trafficManager     trafficmanager.TrafficRuleManager
 	podNs              PodNetnsFinder
+	// checkpoint persists enrolled pod state across node agent restarts. May be nil (disabled).
+	checkpoint *podStateCheckpoint
+	// enrollment records the outcome of every enrollment attempt. May be nil (disabled).
+	enrollment *EnrollmentResults
 	// allow overriding for tests
 	netnsRunner func(fdable NetnsFd, toRun func() error) error
 }
@@ -50,6 +55,9 @@ var _ MeshDataplane = &NetServer{}
 // - For each of these existing snapshotted pods, steps into their netNS, and reconciles their
 // existing iptables rules against the expected set of rules. This is used to handle reconciling
 // iptables rule drift/changes between versions.
+//
+// If pod state checkpointing is enabled, pods whose netns and rules match the checkpoint left behind by
+// the previous node agent are resumed as-is, and only the pods whose state differs are touched.
 func (s *NetServer) ConstructInitialSnapshot(existingAmbientPods []*corev1.Pod) error {
 	var consErr []error
 
@@ -61,16 +69,34 @@ func (s *NetServer) ConstructInitialSnapshot(existingAmbientPods []*corev1.Pod)
 
 	if s.trafficManager.ReconcileModeEnabled() {
 		log.Info("inpod reconcile mode enabled")
+		skipped := 0
 		for _, pod := range existingAmbientPods {
 			log := log.WithLabels("ns", pod.Namespace, "name", pod.Name)
+			ruleHash := s.checkpoint.RuleHash(getPodLevelTrafficOverrides(pod))
+			if s.checkpoint.Matches(pod, s.currentPodSnapshot.Get(string(pod.UID)), ruleHash) {
+				log.Debug("inpod rules match checkpoint, skipping reconcile")
+				skipped++
+				continue
+			}
 			log.Debug("upgrading and reconciling inpod rules for already-running pod if necessary")
 			err := s.reconcileExistingPod(pod)
 			if err != nil {
 				// for now, we will simply log an error, no need to append this error to the generic snapshot list
 				log.Errorf("failed to reconcile inpod rules for pod, try restarting the pod, or removing and re-adding it to the mesh: %v", err)
+				continue
 			}
+			s.checkpoint.Record(pod, s.currentPodSnapshot.Get(string(pod.UID)), "", ruleHash)
+		}
+		if s.checkpoint != nil {
+			log.Infof("reconciled inpod rules for %d pods, %d pods were unchanged since the last checkpoint",
+				len(existingAmbientPods)-skipped, skipped)
 		}
 	}
+
+	// Forget any checkpointed pods that went away while we were not running.
+	s.checkpoint.Retain(sets.New(slices.Map(existingAmbientPods, func(p *corev1.Pod) string {
+		return string(p.UID)
+	})...))
 	return errors.Join(consErr...)
 }
 
@@ -79,12 +105,14 @@ func (s *NetServer) ConstructInitialSnapshot(existingAmbientPods []*corev1.Pod)
 func (s *NetServer) Start(ctx context.Context) {
 	log.Debug("starting ztunnel server")
 	go s.ztunnelServer.Run(ctx)
+	go s.checkpoint.Run(ctx)
 }
 
-// Stop stops the ztunnel connection listen server.
+// Stop stops the ztunnel connection listen server, and writes any pending pod state checkpoint changes.
 func (s *NetServer) Stop(_ bool) {
 	log.Debug("stopping ztunnel server")
 	s.ztunnelServer.Close()
+	s.checkpoint.Flush()
 }
 
 // SyncHostProbeIPSet is a no-op for the inner NetServer: the host probe ipset is owned
@@ -119,6 +147,7 @@ func (s *NetServer) AddPodToMesh(ctx context.Context, pod *corev1.Pod, podIPs []
 	if err != nil {
 		// if we fail, we should not leave a dangling UID in the snapshot.
 		s.currentPodSnapshot.Take(string(pod.UID))
+		s.enrollment.reportFailure(pod, EnrollmentFailureNetns, err)
 		return NewErrNonRetryableAdd(err)
 	}
 
@@ -132,8 +161,10 @@ func (s *NetServer) AddPodToMesh(ctx context.Context, pod *corev1.Pod, podIPs []
 		// and return a NonRetryableError in this case.
 		log.Errorf("failed to update POD inpod: %s/%s %v", pod.Namespace, pod.Name, err)
 		s.currentPodSnapshot.Take(string(pod.UID))
+		s.enrollment.reportFailure(pod, EnrollmentFailureInpodRules, err)
 		return NewErrNonRetryableAdd(err)
 	}
+	s.checkpoint.Record(pod, openNetns, netNs, s.checkpoint.RuleHash(podCfg))
 
 	// For *any* other failures after a successful `CreateInpodRules` call, we must return
 	// the error as-is.
@@ -145,8 +176,10 @@ func (s *NetServer) AddPodToMesh(ctx context.Context, pod *corev1.Pod, podIPs []
 	// `AddPodToMesh`, in case a ztunnel connection later becomes available.
 	log.Debug("notifying subscribed node proxies")
 	if err := s.sendPodToZtunnelAndWaitForAck(ctx, pod, openNetns); err != nil {
+		s.enrollment.reportFailure(pod, EnrollmentFailureZtunnel, err)
 		return err
 	}
+	s.enrollment.reportSuccess(pod)
 	return nil
 }
 
@@ -167,6 +200,8 @@ func (s *NetServer) RemovePodFromMesh(ctx context.Context, pod *corev1.Pod, isDe
 	if openNetns == nil {
 		log.Debug("failed to find pod netns during removal")
 	}
+	s.checkpoint.Remove(string(pod.UID))
+	s.enrollment.forget(pod)
 
 	// If the pod is already deleted or terminated, we do not need to clean up the pod network -- only the host side.
 	if !isDelete {
@@ -273,12 +308,50 @@ func (s *NetServer) buildZtunnelSnapshot(ambientPodUIDs map[types.UID]*corev1.Po
 		s.currentPodSnapshot.Ensure(string(uid))
 	}
 
-	// populate full pod snapshot from cgroups
-	return s.scanProcForPodsAndCache(ambientPodUIDs)
+	// resume what we can from the checkpoint, and populate the rest of the pod snapshot from cgroups
+	return s.scanProcForPodsAndCache(s.restoreFromCheckpoint(ambientPodUIDs))
+}
+
+// restoreFromCheckpoint opens the checkpointed netns of each pod, and verifies it is still the same netns
+// (by inode) before adding it to the cache. Returns the pods that could not be restored, and still need
+// to be found by scanning procfs.
+func (s *NetServer) restoreFromCheckpoint(pods map[types.UID]*corev1.Pod) map[types.UID]*corev1.Pod {
+	if s.checkpoint == nil {
+		return pods
+	}
+	remaining := make(map[types.UID]*corev1.Pod, len(pods))
+	for uid, pod := range pods {
+		entry, f := s.checkpoint.Get(string(uid))
+		if !f || entry.NetnsPath == "" {
+			remaining[uid] = pod
+			continue
+		}
+		netns, err := s.currentPodSnapshot.openNetns(entry.NetnsPath)
+		if err != nil {
+			log.Debugf("failed to open checkpointed netns %s for pod %s/%s: %v", entry.NetnsPath, pod.Namespace, pod.Name, err)
+			remaining[uid] = pod
+			continue
+		}
+		if netns.Inode() != entry.NetnsInode {
+			log.Debugf("checkpointed netns %s for pod %s/%s changed, rescanning", entry.NetnsPath, pod.Namespace, pod.Name)
+			netns.Close()
+			remaining[uid] = pod
+			continue
+		}
+		s.currentPodSnapshot.UpsertPodCacheWithNetns(string(uid), WorkloadInfo{
+			Workload: podToWorkload(pod),
+			Netns:    netns,
+		})
+	}
+	log.Debugf("restored %d pods from checkpoint, %d pods need a procfs scan", len(pods)-len(remaining), len(remaining))
+	return remaining
 }
 
 func (s *NetServer) scanProcForPodsAndCache(pods map[types.UID]*corev1.Pod) error {
 	// TODO: maybe remove existing uids in s.currentPodSnapshot from the filter set.
+	if len(pods) == 0 {
+		return nil
+	}
 	res, err := s.podNs.FindNetnsForPods(pods)
 	if err != nil {
 		return err
@@ -346,6 +346,59 @@ func TestDoesntReturnRetryableErrorOnIptablesFail(t *testing.T) {
 	}
 }
 
+func TestAddPodReportsEnrollmentResults(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	setupLogging()
+	podIPs := []netip.Addr{netip.MustParseAddr("99.9.9.9")}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "foo",
+		Namespace: "bar",
+		UID:       "123",
+	}}
+	key := types.NamespacedName{Namespace: "bar", Name: "foo"}
+
+	cases := []struct {
+		name       string
+		setup      func(f netTestFixture)
+		wantReason EnrollmentFailureReason
+	}{
+		{
+			name:       "success",
+			setup:      func(f netTestFixture) {},
+			wantReason: "",
+		},
+		{
+			name:       "ztunnel failure",
+			setup:      func(f netTestFixture) { f.ztunnelServer.addError = errors.New("fake error") },
+			wantReason: EnrollmentFailureZtunnel,
+		},
+		{
+			name:       "inpod rules failure",
+			setup:      func(f netTestFixture) { f.nlDeps.AddRouteErr = errors.New("fake error") },
+			wantReason: EnrollmentFailureInpodRules,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := getTestFixure(ctx)
+			results := NewEnrollmentResults()
+			fixture.netServer.enrollment = results
+			tt.setup(fixture)
+
+			_ = fixture.netServer.AddPodToMesh(ctx, pod, podIPs, "fakenetns")
+			res, f := results.Get(key)
+			assert.Equal(t, f, true)
+			assert.Equal(t, res.Reason, tt.wantReason)
+			assert.Equal(t, res.UID, pod.UID)
+
+			assert.NoError(t, fixture.netServer.RemovePodFromMesh(ctx, pod, true))
+			_, f = results.Get(key)
+			assert.Equal(t, f, false)
+		})
+	}
+}
+
 func TestConstructInitialSnap(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -56,7 +56,13 @@ type AmbientArgs struct {
 	ExcludeNamespaces          []string
 	DNSCapture                 bool
 	EnableIPv6                 bool
+	IPv6Only                   bool
 	ReconcilePodRulesOnStartup bool
 	NativeNftables             bool
 	ForceIptablesBinary        string
+	// CheckpointPath is the (host path) file enrolled pod state is checkpointed to, so it can be resumed
+	// after a node agent restart. Checkpointing is disabled if empty.
+	CheckpointPath string
+	// EnrollmentResults, if set, receives the outcome of every pod enrollment attempt.
+	EnrollmentResults *EnrollmentResults
 }
@@ -86,7 +86,9 @@ func NewServer(ctx context.Context, ready *atomic.Value, pluginSocket string, ar
 	}
 
 	s.NotReady()
-	s.handlers = setupHandlers(s.ctx, s.kubeClient, s.dataplane, args.SystemNamespace, args.EnablementSelector, args.ExcludeNamespaces)
+	handlers := setupHandlers(s.ctx, s.kubeClient, s.dataplane, args.SystemNamespace, args.EnablementSelector, args.ExcludeNamespaces)
+	args.EnrollmentResults.SetRetry(handlers.enqueuePod)
+	s.handlers = handlers
 
 	cniServer := startCniPluginServer(ctx, pluginSocket, s.handlers, s.dataplane)
 	err = cniServer.Start()
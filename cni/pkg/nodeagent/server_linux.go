@@ -29,14 +29,20 @@ import (
 	"istio.io/istio/cni/pkg/trafficmanager"
 	"istio.io/istio/cni/pkg/util"
 	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/version"
 	dep "istio.io/istio/tools/istio-iptables/pkg/dependencies"
 )
 
 func initMeshDataplane(client kube.Client, args AmbientArgs) (*meshDataplane, error) {
 	// Linux specific startup operations
+	if args.IPv6Only && !args.EnableIPv6 {
+		log.Warn("IPv6-only ambient capture is configured, enabling IPv6 support")
+		args.EnableIPv6 = true
+	}
 	hostCfg := &config.AmbientConfig{
 		RedirectDNS:            args.DNSCapture,
 		EnableIPv6:             args.EnableIPv6,
+		IPv6Only:               args.IPv6Only,
 		HostProbeSNATAddress:   HostProbeSNATIP,
 		HostProbeV6SNATAddress: HostProbeSNATIPV6,
 	}
@@ -44,6 +50,7 @@ func initMeshDataplane(client kube.Client, args AmbientArgs) (*meshDataplane, er
 	podCfg := &config.AmbientConfig{
 		RedirectDNS:            args.DNSCapture,
 		EnableIPv6:             args.EnableIPv6,
+		IPv6Only:               args.IPv6Only,
 		HostProbeSNATAddress:   HostProbeSNATIP,
 		HostProbeV6SNATAddress: HostProbeSNATIPV6,
 		Reconcile:              args.ReconcilePodRulesOnStartup,
@@ -90,7 +97,7 @@ func initMeshDataplane(client kube.Client, args AmbientArgs) (*meshDataplane, er
 	}
 
 	log.Infof("creating host addressSet manager in the node netns")
-	setManager, err := createHostNetworkAddrSetManager(useNftables, hostCfg.EnableIPv6)
+	setManager, err := createHostNetworkAddrSetManager(useNftables, hostCfg.IPv4Enabled(), hostCfg.EnableIPv6)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing host addressSet manager: %w", err)
 	}
@@ -138,6 +145,15 @@ func initMeshDataplane(client kube.Client, args AmbientArgs) (*meshDataplane, er
 		return nil, err
 	}
 	netServer := newNetServer(ztunnelServer, podNsMap, podTrafficManager, podNetns)
+	if args.CheckpointPath != "" {
+		backend := "iptables"
+		if useNftables {
+			backend = "nftables"
+		}
+		log.Infof("pod state checkpointing enabled, using %s", args.CheckpointPath)
+		netServer.checkpoint = newPodStateCheckpoint(args.CheckpointPath, backend, ruleSeedFor(version.Info.Version, backend, podCfg))
+	}
+	netServer.enrollment = args.EnrollmentResults
 
 	return &meshDataplane{
 		kubeClient:         client.Kube(),
@@ -151,11 +167,11 @@ func initMeshDataplane(client kube.Client, args AmbientArgs) (*meshDataplane, er
 // Note that if the set already exists by name, Create will not return an error.
 //
 // We will unconditionally flush our set before use here, so it shouldn't matter.
-func createHostNetworkAddrSetManager(useNftables bool, isV6 bool) (set.AddressSetManager, error) {
+func createHostNetworkAddrSetManager(useNftables bool, isV4 bool, isV6 bool) (set.AddressSetManager, error) {
 	var setManager set.AddressSetManager
 	runErr := util.RunAsHost(func() error {
 		var err error
-		setManager, err = set.New(useNftables, isV6)
+		setManager, err = set.New(useNftables, isV4, isV6)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,146 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repair
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"istio.io/istio/cni/pkg/nodeagent"
+	"istio.io/istio/pkg/kube/controllers"
+)
+
+const (
+	ReasonAmbientEnrollmentFailed = "AmbientEnrollmentFailed"
+
+	// maxAmbientRetries caps how many times we ask the node agent to retry enrolling the same pod.
+	// Past that, the pod is likely broken in a way a retry will not fix.
+	maxAmbientRetries = 5
+)
+
+var errSkipAmbientPod = errors.New("skipping pod")
+
+// WatchAmbientEnrollment configures the controller to also act on pods the node agent failed to enroll
+// in ambient mode, using the same action (repair, delete or label) as for broken sidecar pods.
+// For ambient pods, "repair" asks the node agent to retry the enrollment.
+//
+// Actions are rate limited to cfg.AmbientRateLimit per minute.
+// Must be called before Run.
+func (c *Controller) WatchAmbientEnrollment(results *nodeagent.EnrollmentResults) {
+	limit := c.cfg.AmbientRateLimit
+	if limit <= 0 {
+		limit = 1
+	}
+	c.enrollment = results
+	c.ambientCtx = context.Background()
+	c.ambientLimiter = rate.NewLimiter(rate.Limit(float64(limit)/60), limit)
+	c.ambientRetries = map[types.NamespacedName]ambientRetry{}
+	c.ambientQueue = controllers.NewQueue("repair ambient pods",
+		controllers.WithReconciler(c.ReconcileAmbient),
+		controllers.WithMaxAttempts(5))
+	// Successful enrollments are queued too, so the retries of a pod are reset once it is enrolled.
+	results.AddHandler(func(res nodeagent.EnrollmentResult) {
+		c.ambientQueue.Add(res.Pod)
+	})
+}
+
+type ambientRetry struct {
+	uid     types.UID
+	retries int
+}
+
+func (c *Controller) runAmbient(stop <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stop
+		cancel()
+	}()
+	c.ambientCtx = ctx
+	c.ambientQueue.Run(stop)
+}
+
+func (c *Controller) ReconcileAmbient(key types.NamespacedName) error {
+	pod := c.pods.Get(key.Name, key.Namespace)
+	if pod == nil {
+		delete(c.ambientRetries, key) // Ensure we do not leak
+		return nil
+	}
+	res, f := c.enrollment.Get(key)
+	if f && !res.Failed() && res.UID == pod.UID {
+		// Transient failures over the lifetime of the pod should not use up its retries.
+		delete(c.ambientRetries, key)
+		return nil
+	}
+	// The pod may have been removed from the mesh since the failure was reported.
+	if !f || !res.Failed() || res.UID != pod.UID {
+		return nil
+	}
+	log := repairLog.WithLabels("pod", key.String(), "reason", res.Reason)
+
+	var action func(pod *corev1.Pod, res nodeagent.EnrollmentResult) error
+	var actionType string
+	if c.cfg.RepairPods {
+		action, actionType = c.retryAmbientPod, repairType
+	} else if c.cfg.DeletePods {
+		action, actionType = func(pod *corev1.Pod, _ nodeagent.EnrollmentResult) error { return c.deleteBrokenPod(pod) }, deleteType
+	} else if c.cfg.LabelPods {
+		action, actionType = func(pod *corev1.Pod, _ nodeagent.EnrollmentResult) error { return c.labelBrokenPod(pod) }, labelType
+	} else {
+		return nil
+	}
+
+	if err := c.ambientLimiter.Wait(c.ambientCtx); err != nil {
+		// Shutting down
+		return nil
+	}
+	log.Infof("Pod failed ambient enrollment: %v", res.Err)
+	c.events.Write(pod, corev1.EventTypeWarning, ReasonAmbientEnrollmentFailed, "pod failed ambient enrollment (%s): %v", res.Reason, res.Err)
+
+	m := ambientPodsRepaired.With(typeLabel.Value(actionType), reasonLabel.Value(string(res.Reason)))
+	if err := action(pod, res); err != nil {
+		if errors.Is(err, errSkipAmbientPod) {
+			log.Warnf("Skipping pod: %v", err)
+			m.With(resultLabel.Value(resultSkip)).Increment()
+			return nil
+		}
+		m.With(resultLabel.Value(resultFail)).Increment()
+		return err
+	}
+	m.With(resultLabel.Value(resultSuccess)).Increment()
+	return nil
+}
+
+// retryAmbientPod asks the node agent to retry enrolling the pod.
+func (c *Controller) retryAmbientPod(pod *corev1.Pod, res nodeagent.EnrollmentResult) error {
+	key := res.Pod
+	retry := c.ambientRetries[key]
+	if retry.uid != pod.UID {
+		retry = ambientRetry{uid: pod.UID}
+	}
+	if retry.retries >= maxAmbientRetries {
+		return fmt.Errorf("%w: giving up after %d enrollment retries", errSkipAmbientPod, retry.retries)
+	}
+	if !c.enrollment.Retry(pod) {
+		return fmt.Errorf("node agent is not able to retry enrollment")
+	}
+	retry.retries++
+	c.ambientRetries[key] = retry
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repair
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"istio.io/istio/cni/pkg/config"
+	"istio.io/istio/cni/pkg/nodeagent"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/monitoring/monitortest"
+	"istio.io/istio/pkg/test"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+var ambientPod = makePod(makePodArgs{
+	PodName:             "ambient",
+	InitContainerStatus: &corev1.ContainerStatus{},
+})
+
+func runAmbientController(t *testing.T, client kube.Client, cfg config.RepairConfig) (*Controller, *nodeagent.EnrollmentResults) {
+	c, err := NewRepairController(client, cfg)
+	assert.NoError(t, err)
+	results := nodeagent.NewEnrollmentResults()
+	c.WatchAmbientEnrollment(results)
+	t.Cleanup(func() {
+		assert.NoError(t, c.queue.WaitForClose(time.Second))
+		assert.NoError(t, c.ambientQueue.WaitForClose(time.Second))
+	})
+	stop := test.NewStop(t)
+	client.RunAndWait(stop)
+	go c.Run(stop)
+	kube.WaitForCacheSync("test", stop, c.queue.HasSynced, c.ambientQueue.HasSynced)
+	return c, results
+}
+
+func podKey(pod *corev1.Pod) types.NamespacedName {
+	return types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+}
+
+func failEnrollment(results *nodeagent.EnrollmentResults, pod *corev1.Pod, reason nodeagent.EnrollmentFailureReason) {
+	results.Report(nodeagent.EnrollmentResult{
+		Pod:    podKey(pod),
+		UID:    pod.UID,
+		Reason: reason,
+		Err:    errors.New("fake failure"),
+	})
+}
+
+func TestAmbientLabelPods(t *testing.T) {
+	mt := monitortest.New(t)
+	client := fakeClient(ambientPod, workingPod)
+	c, results := runAmbientController(t, client, config.RepairConfig{
+		LabelPods:        true,
+		LabelKey:         "testkey",
+		LabelValue:       "testval",
+		AmbientRateLimit: 10,
+	})
+
+	// A successful enrollment is never acted on
+	results.Report(nodeagent.EnrollmentResult{Pod: podKey(workingPod), UID: workingPod.UID})
+	failEnrollment(results, ambientPod, nodeagent.EnrollmentFailureNetns)
+
+	assert.EventuallyEqual(t, func() map[string]string {
+		return makePodLabelMap(c.pods.List(metav1.NamespaceAll, klabels.Everything()))
+	}, map[string]string{
+		ambientPod.Name: "testkey=testval",
+		workingPod.Name: "",
+	})
+	mt.Assert(ambientPodsRepaired.Name(),
+		map[string]string{"result": resultSuccess, "type": labelType, "reason": string(nodeagent.EnrollmentFailureNetns)},
+		monitortest.Exactly(1))
+}
+
+func TestAmbientDeletePods(t *testing.T) {
+	mt := monitortest.New(t)
+	client := fakeClient(ambientPod, workingPod)
+	c, results := runAmbientController(t, client, config.RepairConfig{
+		DeletePods:       true,
+		AmbientRateLimit: 10,
+	})
+
+	failEnrollment(results, ambientPod, nodeagent.EnrollmentFailureInpodRules)
+
+	assert.EventuallyEqual(t, func() int {
+		return len(c.pods.List(metav1.NamespaceAll, klabels.Everything()))
+	}, 1)
+	mt.Assert(ambientPodsRepaired.Name(),
+		map[string]string{"result": resultSuccess, "type": deleteType, "reason": string(nodeagent.EnrollmentFailureInpodRules)},
+		monitortest.Exactly(1))
+}
+
+func TestAmbientRepairRetriesAreCapped(t *testing.T) {
+	client := fakeClient(ambientPod)
+	c, err := NewRepairController(client, config.RepairConfig{
+		RepairPods:       true,
+		AmbientRateLimit: 1000,
+	})
+	assert.NoError(t, err)
+	results := nodeagent.NewEnrollmentResults()
+	c.WatchAmbientEnrollment(results)
+	c.ambientQueue.ShutDownEarly()
+	stop := test.NewStop(t)
+	client.RunAndWait(stop)
+	kube.WaitForCacheSync("test", stop, c.pods.HasSynced)
+	t.Cleanup(c.queue.ShutDownEarly)
+
+	failEnrollment(results, ambientPod, nodeagent.EnrollmentFailureZtunnel)
+	// The node agent has not registered a way to retry
+	assert.Error(t, c.ReconcileAmbient(podKey(ambientPod)))
+
+	retried := 0
+	results.SetRetry(func(pod *corev1.Pod) { retried++ })
+	for range maxAmbientRetries + 2 {
+		assert.NoError(t, c.ReconcileAmbient(podKey(ambientPod)))
+	}
+	assert.Equal(t, retried, maxAmbientRetries)
+
+	// Once enrolled, the pod is no longer acted on
+	results.Report(nodeagent.EnrollmentResult{Pod: podKey(ambientPod), UID: ambientPod.UID})
+	assert.NoError(t, c.ReconcileAmbient(podKey(ambientPod)))
+	assert.Equal(t, retried, maxAmbientRetries)
+
+	// A successful enrollment resets the retries, so later failures are retried again
+	failEnrollment(results, ambientPod, nodeagent.EnrollmentFailureZtunnel)
+	assert.NoError(t, c.ReconcileAmbient(podKey(ambientPod)))
+	assert.Equal(t, retried, maxAmbientRetries+1)
+}
@@ -33,4 +33,11 @@ var (
 		"istio_cni_repair_pods_repaired_total",
 		"Total number of pods repaired by repair controller",
 	)
+
+	reasonLabel = monitoring.CreateLabel("reason")
+
+	ambientPodsRepaired = monitoring.NewSum(
+		"istio_cni_repair_ambient_pods_repaired_total",
+		"Total number of ambient pods that failed enrollment and were acted on by the repair controller, by failure reason",
+	)
 )
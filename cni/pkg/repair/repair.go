@@ -18,13 +18,15 @@ import (
 	"context"
 
 	"istio.io/istio/cni/pkg/config"
+	"istio.io/istio/cni/pkg/nodeagent"
 	"istio.io/istio/cni/pkg/scopes"
 	"istio.io/istio/pkg/kube"
 )
 
 var repairLog = scopes.CNIAgent
 
-func StartRepair(ctx context.Context, cfg config.RepairConfig) {
+// StartRepair starts the repair controller. enrollment may be nil if the ambient node agent is not running.
+func StartRepair(ctx context.Context, cfg config.RepairConfig, enrollment *nodeagent.EnrollmentResults) {
 	if !cfg.Enabled {
 		repairLog.Info("CNI repair controller is disabled")
 		return
@@ -40,6 +42,10 @@ func StartRepair(ctx context.Context, cfg config.RepairConfig) {
 	if err != nil {
 		repairLog.Fatalf("Fatal error constructing repair controller: %+v", err)
 	}
+	if cfg.RepairAmbientPods && enrollment != nil {
+		repairLog.Info("CNI repair controller will act on ambient enrollment failures")
+		rc.WatchAmbientEnrollment(enrollment)
+	}
 	go rc.Run(ctx.Done())
 	client.RunAndWait(ctx.Done())
 }
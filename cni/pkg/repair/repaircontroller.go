@@ -19,11 +19,13 @@ import (
 	"fmt"
 	"strings"
 
+	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	"istio.io/istio/cni/pkg/config"
+	"istio.io/istio/cni/pkg/nodeagent"
 	"istio.io/istio/cni/pkg/plugin"
 	"istio.io/istio/pkg/kube"
 	"istio.io/istio/pkg/kube/controllers"
@@ -37,6 +39,13 @@ type Controller struct {
 	cfg          config.RepairConfig
 	events       kclient.EventRecorder
 	repairedPods map[types.NamespacedName]types.UID
+
+	// Ambient enrollment failure handling; only set if WatchAmbientEnrollment is called.
+	enrollment     *nodeagent.EnrollmentResults
+	ambientQueue   controllers.Queue
+	ambientLimiter *rate.Limiter
+	ambientRetries map[types.NamespacedName]ambientRetry
+	ambientCtx     context.Context
 }
 
 func NewRepairController(client kube.Client, cfg config.RepairConfig) (*Controller, error) {
@@ -66,6 +75,9 @@ func NewRepairController(client kube.Client, cfg config.RepairConfig) (*Controll
 
 func (c *Controller) Run(stop <-chan struct{}) {
 	kube.WaitForCacheSync("repair controller", stop, c.pods.HasSynced)
+	if c.enrollment != nil {
+		go c.runAmbient(stop)
+	}
 	c.queue.Run(stop)
 	c.pods.ShutdownHandlers()
 }
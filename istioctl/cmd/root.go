@@ -24,23 +24,30 @@ import (
 	"github.com/spf13/viper"
 
 	"istio.io/istio/istioctl/pkg/admin"
+	"istio.io/istio/istioctl/pkg/ambient"
 	"istio.io/istio/istioctl/pkg/analyze"
 	"istio.io/istio/istioctl/pkg/authz"
 	"istio.io/istio/istioctl/pkg/checkinject"
 	"istio.io/istio/istioctl/pkg/cli"
 	"istio.io/istio/istioctl/pkg/completion"
 	"istio.io/istio/istioctl/pkg/config"
+	"istio.io/istio/istioctl/pkg/configsize"
 	"istio.io/istio/istioctl/pkg/dashboard"
 	"istio.io/istio/istioctl/pkg/describe"
+	"istio.io/istio/istioctl/pkg/envoyfilter"
+	"istio.io/istio/istioctl/pkg/gateway"
 	"istio.io/istio/istioctl/pkg/injector"
 	"istio.io/istio/istioctl/pkg/internaldebug"
 	"istio.io/istio/istioctl/pkg/kubeinject"
+	"istio.io/istio/istioctl/pkg/lbsim"
 	"istio.io/istio/istioctl/pkg/metrics"
+	"istio.io/istio/istioctl/pkg/migrate"
 	"istio.io/istio/istioctl/pkg/multicluster"
 	"istio.io/istio/istioctl/pkg/precheck"
 	"istio.io/istio/istioctl/pkg/proxyconfig"
 	"istio.io/istio/istioctl/pkg/proxystatus"
 	"istio.io/istio/istioctl/pkg/root"
+	"istio.io/istio/istioctl/pkg/sidecar"
 	"istio.io/istio/istioctl/pkg/tag"
 	"istio.io/istio/istioctl/pkg/util"
 	"istio.io/istio/istioctl/pkg/validate"
@@ -199,6 +206,15 @@ debug and diagnose their Istio mesh.
 	experimentalCmd.AddCommand(precheck.Cmd(ctx))
 	experimentalCmd.AddCommand(proxyconfig.StatsConfigCmd(ctx))
 	experimentalCmd.AddCommand(checkinject.Cmd(ctx))
+	experimentalCmd.AddCommand(kubeinject.Cmd(ctx))
+	experimentalCmd.AddCommand(envoyfilter.Cmd(ctx))
+	experimentalCmd.AddCommand(sidecar.Cmd(ctx))
+	experimentalCmd.AddCommand(lbsim.Cmd())
+	experimentalCmd.AddCommand(configsize.Cmd(ctx))
+	experimentalCmd.AddCommand(migrate.Cmd(ctx))
+	experimentalCmd.AddCommand(gateway.Cmd(ctx))
+	experimentalCmd.AddCommand(ztunnelconfig.ExperimentalZtunnelConfig(ctx))
+	experimentalCmd.AddCommand(ambient.Cmd(ctx))
 	rootCmd.AddCommand(waypoint.Cmd(ctx))
 	rootCmd.AddCommand(ztunnelconfig.ZtunnelConfig(ctx))
 
@@ -0,0 +1,248 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ambient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	securityclient "istio.io/client-go/pkg/apis/security/v1"
+	"istio.io/istio/istioctl/pkg/cli"
+	"istio.io/istio/istioctl/pkg/completion"
+	"istio.io/istio/istioctl/pkg/util"
+	ztunnelDump "istio.io/istio/istioctl/pkg/writer/ztunnel/configdump"
+	"istio.io/istio/istioctl/pkg/ztunnelconfig"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/maps"
+	"istio.io/istio/pkg/slices"
+	"istio.io/istio/pkg/util/protomarshal"
+	"istio.io/istio/pkg/util/sets"
+	"istio.io/istio/pkg/workloadapi"
+)
+
+// Cmd returns the experimental commands to troubleshoot ambient mode.
+func Cmd(ctx cli.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ambient",
+		Short: "Troubleshoot workloads in ambient mode",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("unknown subcommand %q", args[0])
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.HelpFunc()(cmd, args)
+			return nil
+		},
+	}
+	cmd.Long = "Troubleshoot workloads in ambient mode.\n\n" + util.ExperimentalMsg
+	cmd.AddCommand(checkCmd(ctx))
+	return cmd
+}
+
+func checkCmd(ctx cli.Context) *cobra.Command {
+	var revision string
+	cmd := &cobra.Command{
+		Use:   "check <namespace|pod>",
+		Short: "Checks each stage of the ambient data path of workloads",
+		Long: `Checks each stage of the ambient data path of the pods of a namespace, or of a single pod:
+
+  Enrollment  traffic of the pod is redirected to Ztunnel by the CNI
+  Istiod      Istiod computes the workload of the pod, as read from its /debug/ambientz endpoint
+  Ztunnel     the Ztunnel of the node proxies the pod and received its workload, as read from its config dump
+  Waypoint    the pod and its services are bound to the waypoints selected by their istio.io/use-waypoint labels
+  Policies    the authorization policies applying to the pod are accepted by Ztunnel and by waypoints
+
+The first failing stage of each pod is highlighted. An argument matching the name of a namespace checks the whole
+namespace; use "pod/<name>" to check a pod with the same name as a namespace.`,
+		Example: `  # Check all the pods of the default namespace
+  istioctl x ambient check default
+
+  # Check a single pod
+  istioctl x ambient check productpage-v1-7d4d7d8b7b-x2j5k -n default
+
+  # Check the pod of a deployment
+  istioctl x ambient check deployment/productpage-v1 -n default`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeClient, err := ctx.CLIClientWithRevision(ctx.RevisionOrDefault(revision))
+			if err != nil {
+				return err
+			}
+			pods, err := targetPods(ctx, kubeClient, args[0])
+			if err != nil {
+				return err
+			}
+			if len(pods) == 0 {
+				return fmt.Errorf("no running pods found for %q", args[0])
+			}
+			state, err := readAmbientState(ctx, kubeClient, pods)
+			if err != nil {
+				return err
+			}
+			reports := slices.Map(pods, func(pod *corev1.Pod) workloadReport {
+				return checkWorkload(state, pod)
+			})
+			printReports(cmd.OutOrStdout(), reports)
+			if slices.FindFunc(reports, workloadReport.failed) != nil {
+				return fmt.Errorf("ambient data path is failing for some pods")
+			}
+			return nil
+		},
+		ValidArgsFunction: completion.ValidPodsNameArgs(ctx),
+	}
+	cmd.PersistentFlags().StringVarP(&revision, "revision", "r", "", "Control plane revision")
+	return cmd
+}
+
+// targetPods returns the pods to check, sorted by name. The argument is either a namespace, or a pod or a
+// resource owning a pod in the namespace of the command.
+func targetPods(ctx cli.Context, kubeClient kube.CLIClient, arg string) ([]*corev1.Pod, error) {
+	_, err := kubeClient.Kube().CoreV1().Namespaces().Get(context.Background(), arg, metav1.GetOptions{})
+	if err == nil {
+		list, err := kubeClient.Kube().CoreV1().Pods(arg).List(context.Background(), metav1.ListOptions{
+			FieldSelector: kube.RunningStatus,
+		})
+		if err != nil {
+			return nil, err
+		}
+		pods := slices.Reference(list.Items)
+		slices.SortBy(pods, func(p *corev1.Pod) string {
+			return p.Name
+		})
+		return pods, nil
+	}
+	if !kerrors.IsNotFound(err) {
+		return nil, err
+	}
+	name, ns, err := ctx.InferPodInfoFromTypedResource(arg, ctx.Namespace())
+	if err != nil {
+		return nil, err
+	}
+	pod, err := kubeClient.Kube().CoreV1().Pods(ns).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return []*corev1.Pod{pod}, nil
+}
+
+// readAmbientState reads the state of ambient mode for a set of pods from the cluster, Istiod and the Ztunnels of
+// their nodes. Errors reaching Istiod or a Ztunnel are recorded in the state, so that they are reported as failing
+// stages.
+func readAmbientState(ctx cli.Context, kubeClient kube.CLIClient, pods []*corev1.Pod) (*ambientState, error) {
+	state := &ambientState{
+		Namespaces: map[string]*corev1.Namespace{},
+		Services:   map[string]*corev1.Service{},
+		Policies:   map[string]*securityclient.AuthorizationPolicy{},
+		Ztunnels:   map[string]ztunnelState{},
+	}
+	namespaces := sets.New(slices.Map(pods, func(p *corev1.Pod) string {
+		return p.Namespace
+	})...)
+	for _, ns := range sets.SortedList(namespaces) {
+		n, err := kubeClient.Kube().CoreV1().Namespaces().Get(context.Background(), ns, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		state.Namespaces[ns] = n
+		services, err := kubeClient.Kube().CoreV1().Services(ns).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range services.Items {
+			svc := &services.Items[i]
+			state.Services[svc.Namespace+"/"+svc.Name] = svc
+		}
+	}
+	// Policies of the root namespace apply to every workload.
+	for _, ns := range sets.SortedList(namespaces.Copy().Insert(ctx.IstioNamespace())) {
+		policies, err := kubeClient.Istio().SecurityV1().AuthorizationPolicies(ns).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range policies.Items {
+			state.Policies[p.Namespace+"/"+p.Name] = p
+		}
+	}
+
+	state.Istiod, state.Index, state.IndexError = readAmbientIndex(kubeClient, ctx.IstioNamespace())
+
+	nodes := sets.New(slices.Map(pods, func(p *corev1.Pod) string {
+		return p.Spec.NodeName
+	})...)
+	for _, node := range sets.SortedList(nodes) {
+		pod, err := ztunnelconfig.PodOnNodeFromDaemonset(node, "ztunnel", ctx.IstioNamespace(), kubeClient)
+		if err != nil {
+			continue
+		}
+		zt := ztunnelState{Pod: pod.Name}
+		b, err := kubeClient.EnvoyDoWithPort(context.Background(), pod.Name, pod.Namespace, "GET", "config_dump",
+			util.DefaultProxyAdminPort)
+		if err == nil {
+			zt.Dump, err = ztunnelDump.ParseDump(b)
+		}
+		zt.Err = err
+		state.Ztunnels[node] = zt
+	}
+	return state, nil
+}
+
+// readAmbientIndex reads the ambient index from the /debug/ambientz endpoint of an Istiod instance. All instances
+// compute the same index, so the first one by name is used.
+func readAmbientIndex(kubeClient kube.CLIClient, istioNamespace string) (string, *ambientIndex, error) {
+	res, err := kubeClient.AllDiscoveryDo(context.Background(), istioNamespace, "debug/ambientz")
+	if err != nil {
+		return "", nil, err
+	}
+	if len(res) == 0 {
+		return "", nil, fmt.Errorf("no running Istiod found in namespace %s", istioNamespace)
+	}
+	istiod := slices.Sort(maps.Keys(res))[0]
+	idx, err := parseAmbientIndex(res[istiod])
+	return istiod, idx, err
+}
+
+func parseAmbientIndex(b []byte) (*ambientIndex, error) {
+	raw := struct {
+		Workloads []json.RawMessage `json:"workloads"`
+		Services  []json.RawMessage `json:"services"`
+	}{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse ambient index: %v", err)
+	}
+	idx := &ambientIndex{}
+	for _, w := range raw.Workloads {
+		wl := &workloadapi.Workload{}
+		if err := protomarshal.UnmarshalAllowUnknown(w, wl); err != nil {
+			return nil, fmt.Errorf("failed to parse workload: %v", err)
+		}
+		idx.Workloads = append(idx.Workloads, wl)
+	}
+	for _, s := range raw.Services {
+		svc := &workloadapi.Service{}
+		if err := protomarshal.UnmarshalAllowUnknown(s, svc); err != nil {
+			return nil, fmt.Errorf("failed to parse service: %v", err)
+		}
+		idx.Services = append(idx.Services, svc)
+	}
+	return idx, nil
+}
@@ -0,0 +1,410 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ambient
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/api/annotation"
+	"istio.io/api/label"
+	istioapimeta "istio.io/api/meta/v1alpha1"
+	typev1beta1 "istio.io/api/type/v1beta1"
+	securityclient "istio.io/client-go/pkg/apis/security/v1"
+	ambientutil "istio.io/istio/istioctl/pkg/util/ambient"
+	"istio.io/istio/istioctl/pkg/writer/table"
+	ztunnelDump "istio.io/istio/istioctl/pkg/writer/ztunnel/configdump"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/maps"
+	"istio.io/istio/pkg/slices"
+	"istio.io/istio/pkg/util/sets"
+	"istio.io/istio/pkg/workloadapi"
+)
+
+type stageStatus int
+
+const (
+	stagePassed stageStatus = iota
+	stageFailed
+	stageSkipped
+)
+
+const (
+	stageEnrollment = "Enrollment"
+	stageIstiod     = "Istiod"
+	stageZtunnel    = "Ztunnel"
+	stageWaypoint   = "Waypoint"
+	stagePolicies   = "Policies"
+)
+
+type stage struct {
+	Name    string
+	Status  stageStatus
+	Details []string
+}
+
+// workloadReport holds the result of each stage of the ambient data path for a Pod.
+type workloadReport struct {
+	Pod    *corev1.Pod
+	Stages []stage
+}
+
+func (r workloadReport) failed() bool {
+	return slices.FindFunc(r.Stages, func(s stage) bool {
+		return s.Status == stageFailed
+	}) != nil
+}
+
+// ambientState is the state of ambient mode in the cluster, which the checks are based on.
+type ambientState struct {
+	Namespaces map[string]*corev1.Namespace
+	Services   map[string]*corev1.Service
+	Policies   map[string]*securityclient.AuthorizationPolicy
+
+	// Istiod is the Istiod instance the ambient index was read from.
+	Istiod     string
+	Index      *ambientIndex
+	IndexError error
+
+	// Ztunnels holds the Ztunnel of each node.
+	Ztunnels map[string]ztunnelState
+}
+
+// ambientIndex is the ambient index of Istiod, as served by /debug/ambientz.
+type ambientIndex struct {
+	Workloads []*workloadapi.Workload
+	Services  []*workloadapi.Service
+}
+
+type ztunnelState struct {
+	Pod  string
+	Dump *ztunnelDump.ZtunnelDump
+	Err  error
+}
+
+// checkWorkload checks each stage of the ambient data path of a Pod.
+func checkWorkload(state *ambientState, pod *corev1.Pod) workloadReport {
+	r := workloadReport{Pod: pod}
+	enrollment, inAmbient := checkEnrollment(state.Namespaces[pod.Namespace], pod)
+	r.Stages = append(r.Stages, enrollment)
+	if !inAmbient {
+		for _, name := range []string{stageIstiod, stageZtunnel, stageWaypoint, stagePolicies} {
+			r.Stages = append(r.Stages, stage{Name: name, Status: stageSkipped, Details: []string{"pod is not in ambient mode"}})
+		}
+		return r
+	}
+	istiod, wl := checkIstiod(state, pod)
+	r.Stages = append(r.Stages, istiod, checkZtunnel(state, pod))
+	if wl == nil {
+		for _, name := range []string{stageWaypoint, stagePolicies} {
+			r.Stages = append(r.Stages, stage{Name: name, Status: stageSkipped, Details: []string{"workload is unknown to Istiod"}})
+		}
+		return r
+	}
+	r.Stages = append(r.Stages, checkWaypoint(state, pod, wl), checkPolicies(state, wl))
+	return r
+}
+
+func checkEnrollment(ns *corev1.Namespace, pod *corev1.Pod) (stage, bool) {
+	s := stage{Name: stageEnrollment}
+	if _, f := pod.Annotations[annotation.SidecarStatus.Name]; f {
+		s.Status = stageSkipped
+		s.Details = []string{"pod has a sidecar"}
+		return s, false
+	}
+	mode, f := pod.Labels[label.IoIstioDataplaneMode.Name]
+	if mode == constants.DataplaneModeNone {
+		s.Status = stageSkipped
+		s.Details = []string{fmt.Sprintf("pod is labeled %s=%s", label.IoIstioDataplaneMode.Name, mode)}
+		return s, false
+	}
+	if mode != constants.DataplaneModeAmbient && (f || !ambientutil.InAmbient(ns)) {
+		s.Status = stageSkipped
+		s.Details = []string{fmt.Sprintf("neither the pod nor its namespace is labeled %s=%s",
+			label.IoIstioDataplaneMode.Name, constants.DataplaneModeAmbient)}
+		return s, false
+	}
+	switch pod.Annotations[annotation.AmbientRedirection.Name] {
+	case constants.AmbientRedirectionEnabled:
+		s.Details = []string{"traffic is redirected to Ztunnel by the CNI"}
+	case constants.AmbientRedirectionPending:
+		s.Status = stageFailed
+		s.Details = []string{fmt.Sprintf("the CNI redirected traffic but could not hand the pod to Ztunnel; "+
+			"check the Ztunnel on node %s", pod.Spec.NodeName)}
+	default:
+		s.Status = stageFailed
+		s.Details = []string{fmt.Sprintf("traffic is not redirected to Ztunnel; check the istio-cni-node pod on node %s, "+
+			"and restart the pod if it started before the CNI was ready", pod.Spec.NodeName)}
+	}
+	return s, true
+}
+
+func checkIstiod(state *ambientState, pod *corev1.Pod) (stage, *workloadapi.Workload) {
+	s := stage{Name: stageIstiod}
+	if state.IndexError != nil {
+		s.Status = stageFailed
+		s.Details = []string{fmt.Sprintf("failed to read the ambient index of Istiod: %v", state.IndexError)}
+		return s, nil
+	}
+	wl := state.Index.workload(pod.Namespace, pod.Name)
+	if wl == nil {
+		s.Status = stageFailed
+		s.Details = []string{fmt.Sprintf("%s has no workload for the pod; check that the pod has an IP", state.Istiod)}
+		return s, nil
+	}
+	if wl.TunnelProtocol != workloadapi.TunnelProtocol_HBONE {
+		s.Status = stageFailed
+		s.Details = []string{fmt.Sprintf("%s does not see the pod as enrolled (protocol %v)", state.Istiod, wl.TunnelProtocol)}
+		return s, wl
+	}
+	s.Details = []string{fmt.Sprintf("%s sends the workload to Ztunnel", state.Istiod)}
+	return s, wl
+}
+
+func checkZtunnel(state *ambientState, pod *corev1.Pod) stage {
+	s := stage{Name: stageZtunnel, Status: stageFailed}
+	zt, f := state.Ztunnels[pod.Spec.NodeName]
+	if !f {
+		s.Details = []string{fmt.Sprintf("no Ztunnel found on node %s", pod.Spec.NodeName)}
+		return s
+	}
+	if zt.Err != nil {
+		s.Details = []string{fmt.Sprintf("failed to read the configuration of %s: %v", zt.Pod, zt.Err)}
+		return s
+	}
+	// The workload state is only reported by Ztunnels which proxy pods from within their network namespace.
+	if zt.Dump.WorkloadState != nil {
+		var ws *ztunnelDump.WorkloadState
+		for _, st := range zt.Dump.WorkloadState {
+			if st.Info.Name == pod.Name && st.Info.Namespace == pod.Namespace {
+				ws = &st
+				break
+			}
+		}
+		if ws == nil {
+			s.Details = []string{fmt.Sprintf("%s is not proxying the pod", zt.Pod)}
+			return s
+		}
+		if ws.State != "" && ws.State != "Up" {
+			s.Details = []string{fmt.Sprintf("%s reports the pod as %s", zt.Pod, ws.State)}
+			return s
+		}
+	}
+	wl := slices.FindFunc(zt.Dump.Workloads, func(w *ztunnelDump.ZtunnelWorkload) bool {
+		return w.Name == pod.Name && w.Namespace == pod.Namespace
+	})
+	if wl == nil {
+		s.Details = []string{fmt.Sprintf("%s has not received the workload from Istiod", zt.Pod)}
+		return s
+	}
+	if (*wl).Status != "Healthy" {
+		s.Details = []string{fmt.Sprintf("%s has the workload as %s", zt.Pod, (*wl).Status)}
+		return s
+	}
+	s.Status = stagePassed
+	s.Details = []string{fmt.Sprintf("%s proxies the pod", zt.Pod)}
+	return s
+}
+
+func checkWaypoint(state *ambientState, pod *corev1.Pod, wl *workloadapi.Workload) stage {
+	s := stage{Name: stageWaypoint, Status: stageSkipped}
+	var failures, bindings []string
+	nsLabel := ""
+	if ns := state.Namespaces[pod.Namespace]; ns != nil {
+		nsLabel = ns.Labels[label.IoIstioUseWaypoint.Name]
+	}
+	if wl.Waypoint != nil {
+		bindings = append(bindings, fmt.Sprintf("workload uses waypoint %s", state.Index.waypointName(wl.Waypoint)))
+	} else if v := pod.Labels[label.IoIstioUseWaypoint.Name]; v != "" && v != "none" {
+		failures = append(failures, fmt.Sprintf("pod is labeled %s=%s but is not bound to a waypoint", label.IoIstioUseWaypoint.Name, v))
+	}
+	for _, key := range sets.SortedList(sets.New(maps.Keys(wl.Services)...)) {
+		svc := state.Index.service(key)
+		if svc == nil {
+			continue
+		}
+		name := svc.Namespace + "/" + svc.Name
+		if ks := state.Services[name]; ks != nil {
+			if c := kubernetesCondition(ks.Status.Conditions, model.WaypointBound); c != nil && c.Status == metav1.ConditionFalse {
+				failures = append(failures, fmt.Sprintf("service %s: %s: %s", name, c.Reason, c.Message))
+				continue
+			}
+		}
+		if svc.Waypoint != nil {
+			bindings = append(bindings, fmt.Sprintf("service %s uses waypoint %s", name, state.Index.waypointName(svc.Waypoint)))
+			continue
+		}
+		want := nsLabel
+		if ks := state.Services[name]; ks != nil && ks.Labels[label.IoIstioUseWaypoint.Name] != "" {
+			want = ks.Labels[label.IoIstioUseWaypoint.Name]
+		}
+		if want != "" && want != "none" {
+			failures = append(failures, fmt.Sprintf("service %s should use waypoint %s but is not bound to it", name, want))
+		}
+	}
+	switch {
+	case len(failures) > 0:
+		s.Status = stageFailed
+		s.Details = append(failures, bindings...)
+	case len(bindings) > 0:
+		s.Status = stagePassed
+		s.Details = bindings
+	default:
+		s.Details = []string{"no waypoint is used"}
+	}
+	return s
+}
+
+func checkPolicies(state *ambientState, wl *workloadapi.Workload) stage {
+	s := stage{Name: stagePolicies, Status: stageSkipped}
+	var failures, accepted []string
+	check := func(key string, cond model.ConditionType, enforcer string) {
+		if p := state.Policies[key]; p != nil {
+			if c := istioCondition(p, cond); c != nil && c.Status == string(metav1.ConditionFalse) {
+				failures = append(failures, fmt.Sprintf("%s policy %s: %s: %s", enforcer, key, c.Reason, c.Message))
+				return
+			}
+		}
+		accepted = append(accepted, fmt.Sprintf("%s enforces %s", enforcer, key))
+	}
+	for _, key := range wl.AuthorizationPolicies {
+		check(key, model.ZtunnelAccepted, "ztunnel")
+	}
+	services := sets.New[string]()
+	for key := range wl.Services {
+		if svc := state.Index.service(key); svc != nil {
+			services.Insert(svc.Name)
+		}
+	}
+	for _, key := range sets.SortedList(sets.New(maps.Keys(state.Policies)...)) {
+		p := state.Policies[key]
+		if p.Namespace != wl.Namespace {
+			continue
+		}
+		targetsWorkload := slices.FindFunc(p.Spec.TargetRefs, func(ref *typev1beta1.PolicyTargetReference) bool {
+			return ref.Kind == gvk.Service.Kind && ref.Group == "" && services.Contains(ref.Name)
+		}) != nil
+		if targetsWorkload {
+			check(key, model.WaypointAccepted, "waypoint")
+		}
+	}
+	switch {
+	case len(failures) > 0:
+		s.Status = stageFailed
+		s.Details = append(failures, accepted...)
+	case len(accepted) > 0:
+		s.Status = stagePassed
+		s.Details = accepted
+	default:
+		s.Details = []string{"no authorization policy applies"}
+	}
+	return s
+}
+
+func (i *ambientIndex) workload(namespace, name string) *workloadapi.Workload {
+	wl := slices.FindFunc(i.Workloads, func(w *workloadapi.Workload) bool {
+		return w.Namespace == namespace && w.Name == name
+	})
+	if wl == nil {
+		return nil
+	}
+	return *wl
+}
+
+// service returns the service with a namespace/hostname key.
+func (i *ambientIndex) service(key string) *workloadapi.Service {
+	svc := slices.FindFunc(i.Services, func(s *workloadapi.Service) bool {
+		return s.Namespace+"/"+s.Hostname == key
+	})
+	if svc == nil {
+		return nil
+	}
+	return *svc
+}
+
+// waypointName returns the namespace/name of the Service of a waypoint.
+func (i *ambientIndex) waypointName(gw *workloadapi.GatewayAddress) string {
+	if h := gw.GetHostname(); h != nil {
+		if svc := i.service(h.Namespace + "/" + h.Hostname); svc != nil {
+			return svc.Namespace + "/" + svc.Name
+		}
+		return h.Hostname
+	}
+	// Istiod serves addresses as strings in /debug/ambientz.
+	addr := string(gw.GetAddress().GetAddress())
+	for _, svc := range i.Services {
+		for _, a := range svc.Addresses {
+			if string(a.Address) == addr {
+				return svc.Namespace + "/" + svc.Name
+			}
+		}
+	}
+	return addr
+}
+
+func kubernetesCondition(conditions []metav1.Condition, t model.ConditionType) *metav1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == string(t) {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+func istioCondition(p *securityclient.AuthorizationPolicy, t model.ConditionType) *istioapimeta.IstioCondition {
+	for _, c := range p.Status.Conditions {
+		if c.Type == string(t) {
+			return c
+		}
+	}
+	return nil
+}
+
+func printReports(w io.Writer, reports []workloadReport) {
+	for i, r := range reports {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		result := color.New(color.FgGreen).Sprint("OK")
+		if r.failed() {
+			result = color.New(color.FgRed).Sprint("FAILING")
+		}
+		fmt.Fprintf(w, "Pod %s/%s on node %s: %s\n", r.Pod.Namespace, r.Pod.Name, r.Pod.Spec.NodeName, result)
+		tw := table.NewStyleWriter(w)
+		tw.SetAddRowFunc(func(obj any) table.Row {
+			s := obj.(stage)
+			status := table.NewCell("✔", color.FgGreen)
+			switch s.Status {
+			case stageFailed:
+				status = table.NewCell("✘", color.FgRed)
+			case stageSkipped:
+				status = table.NewCell("-")
+			}
+			return table.Row{Cells: []table.Cell{table.NewCell(s.Name), status, table.NewCell(strings.Join(s.Details, "; "))}}
+		})
+		tw.AddHeader("STAGE", "STATUS", "DETAILS")
+		for _, s := range r.Stages {
+			tw.AddRow(s)
+		}
+		tw.Flush()
+	}
+}
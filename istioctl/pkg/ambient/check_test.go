@@ -0,0 +1,144 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ambient
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/api/annotation"
+	"istio.io/api/label"
+	istioapimeta "istio.io/api/meta/v1alpha1"
+	"istio.io/api/security/v1beta1"
+	typev1beta1 "istio.io/api/type/v1beta1"
+	securityclient "istio.io/client-go/pkg/apis/security/v1"
+	ztunnelDump "istio.io/istio/istioctl/pkg/writer/ztunnel/configdump"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/test/util"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/slices"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestCheck(t *testing.T) {
+	b, err := os.ReadFile("testdata/ambientz.json")
+	assert.NoError(t, err)
+	index, err := parseAmbientIndex(b)
+	assert.NoError(t, err)
+
+	pod := func(name, node, redirection string) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: map[string]string{}, Labels: map[string]string{}},
+			Spec:       corev1.PodSpec{NodeName: node},
+		}
+		if redirection != "" {
+			p.Annotations[annotation.AmbientRedirection.Name] = redirection
+		}
+		return p
+	}
+	sidecar := pod("details-v1", "node-1", "")
+	sidecar.Annotations[annotation.SidecarStatus.Name] = "{}"
+	optOut := pod("legacy-v1", "node-1", "")
+	optOut.Labels[label.IoIstioDataplaneMode.Name] = constants.DataplaneModeNone
+	pods := []*corev1.Pod{
+		sidecar,
+		optOut,
+		pod("productpage-v1", "node-1", constants.AmbientRedirectionEnabled),
+		pod("ratings-v1", "node-1", constants.AmbientRedirectionPending),
+		pod("reviews-v1", "node-1", constants.AmbientRedirectionEnabled),
+		pod("unknown-v1", "node-2", ""),
+	}
+
+	ztunnelWorkload := func(name string) *ztunnelDump.ZtunnelWorkload {
+		return &ztunnelDump.ZtunnelWorkload{Name: name, Namespace: "default", Status: "Healthy", Node: "node-1"}
+	}
+	workloadState := func(name string) ztunnelDump.WorkloadState {
+		return ztunnelDump.WorkloadState{State: "Up", Info: ztunnelDump.WorkloadInfo{Name: name, Namespace: "default"}}
+	}
+	state := &ambientState{
+		Namespaces: map[string]*corev1.Namespace{
+			"default": {ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{
+				label.IoIstioDataplaneMode.Name: constants.DataplaneModeAmbient,
+			}}},
+		},
+		Services: map[string]*corev1.Service{
+			"default/reviews": {
+				ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: "default", Labels: map[string]string{
+					label.IoIstioUseWaypoint.Name: "reviews-waypoint",
+				}},
+				Status: corev1.ServiceStatus{Conditions: []metav1.Condition{{
+					Type:    string(model.WaypointBound),
+					Status:  metav1.ConditionFalse,
+					Reason:  "WaypointIsNotReady",
+					Message: "waypoint default/reviews-waypoint was not found",
+				}}},
+			},
+		},
+		Policies: map[string]*securityclient.AuthorizationPolicy{
+			"default/allow-nothing": {
+				ObjectMeta: metav1.ObjectMeta{Name: "allow-nothing", Namespace: "default"},
+				Status: istioapimeta.IstioStatus{Conditions: []*istioapimeta.IstioCondition{{
+					Type: string(model.ZtunnelAccepted), Status: string(metav1.ConditionTrue),
+				}}},
+			},
+			"default/productpage-viewer": {
+				ObjectMeta: metav1.ObjectMeta{Name: "productpage-viewer", Namespace: "default"},
+				Spec: v1beta1.AuthorizationPolicy{TargetRefs: []*typev1beta1.PolicyTargetReference{{
+					Kind: "Service", Name: "productpage",
+				}}},
+				Status: istioapimeta.IstioStatus{Conditions: []*istioapimeta.IstioCondition{{
+					Type: string(model.WaypointAccepted), Status: string(metav1.ConditionTrue),
+				}}},
+			},
+			"default/reviews-l7": {
+				ObjectMeta: metav1.ObjectMeta{Name: "reviews-l7", Namespace: "default"},
+				Status: istioapimeta.IstioStatus{Conditions: []*istioapimeta.IstioCondition{{
+					Type:    string(model.ZtunnelAccepted),
+					Status:  string(metav1.ConditionFalse),
+					Reason:  "UnsupportedValue",
+					Message: "ztunnel does not support HTTP attributes (found: methods). In ambient mode you must use a waypoint proxy to enforce HTTP rules.",
+				}}},
+			},
+		},
+		Istiod: "istiod-6c4f8d5b9-abcde",
+		Index:  index,
+		Ztunnels: map[string]ztunnelState{
+			"node-1": {
+				Pod: "ztunnel-x7k2p",
+				Dump: &ztunnelDump.ZtunnelDump{
+					Workloads: []*ztunnelDump.ZtunnelWorkload{ztunnelWorkload("productpage-v1"), ztunnelWorkload("reviews-v1")},
+					WorkloadState: map[string]ztunnelDump.WorkloadState{
+						"1": workloadState("productpage-v1"),
+						"2": workloadState("reviews-v1"),
+					},
+				},
+			},
+			"node-2": {Pod: "ztunnel-9qv4m", Err: fmt.Errorf("connection refused")},
+		},
+	}
+
+	reports := slices.Map(pods, func(p *corev1.Pod) workloadReport {
+		return checkWorkload(state, p)
+	})
+	out := &bytes.Buffer{}
+	printReports(out, reports)
+	util.CompareContent(t, out.Bytes(), "testdata/check.golden")
+	assert.Equal(t, slices.Map(reports, workloadReport.failed), []bool{false, false, false, true, true, true})
+}
@@ -0,0 +1,240 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configsize
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"istio.io/istio/istioctl/pkg/cli"
+	"istio.io/istio/istioctl/pkg/clioptions"
+	"istio.io/istio/istioctl/pkg/completion"
+	"istio.io/istio/istioctl/pkg/multixds"
+	"istio.io/istio/istioctl/pkg/util"
+	pilotutil "istio.io/istio/pilot/pkg/networking/util"
+	"istio.io/istio/pilot/pkg/xds"
+	v3 "istio.io/istio/pilot/pkg/xds/v3"
+)
+
+// Cmd reports the size of the configuration istiod generates for proxies.
+func Cmd(ctx cli.Context) *cobra.Command {
+	var (
+		opts         clioptions.ControlPlaneOptions
+		centralOpts  clioptions.CentralControlPlaneOptions
+		top          int
+		outputFormat string
+	)
+	cmd := &cobra.Command{
+		Use:   "config-size [<pod-name>[.<namespace>]]",
+		Short: "Report the size of the configuration generated for proxies",
+		Long: `Config-size reports the number and size of the listeners, routes, virtual hosts, clusters and endpoints that
+Istiod generates for proxies.
+
+Without arguments, the proxies in the namespace are listed, largest configuration first. Measuring a proxy requires
+Istiod to regenerate its configuration, so proxies are only listed one namespace at a time. For a single proxy, the
+configuration is also attributed to the Services, ServiceEntries, VirtualServices and DestinationRules that caused it
+to be generated, and the config objects that inflate the configuration the most are listed first. Clusters and
+endpoints are attributed to the service they were generated for, or to the DestinationRule for subset clusters;
+virtual hosts are attributed to the VirtualService that configured their routes, or to the service otherwise.
+
+Large configurations can usually be reduced by limiting the services visible to the proxy with a Sidecar resource.`,
+		Example: `  # List the proxies in the default namespace, largest configuration first
+  istioctl x config-size
+
+  # List the proxies in a namespace
+  istioctl x config-size -n bookinfo
+
+  # Show the config objects contributing the most to the configuration of a pod
+  istioctl x config-size productpage-v1-7d9cf8b4d6-m2xk9.default`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			kubeClient, err := ctx.CLIClientWithRevision(ctx.RevisionOrDefault(opts.Revision))
+			if err != nil {
+				return err
+			}
+			query := url.Values{}
+			if len(args) > 0 {
+				podName, ns, err := ctx.InferPodInfoFromTypedResource(args[0], ctx.Namespace())
+				if err != nil {
+					return err
+				}
+				query.Set("proxyID", podName+"."+ns)
+			} else {
+				query.Set("namespace", ctx.NamespaceOrDefault(ctx.Namespace()))
+			}
+			resourceName := "configsize?" + query.Encode()
+			xdsRequest := discovery.DiscoveryRequest{
+				ResourceNames: []string{resourceName},
+				Node: &core.Node{
+					Id: "debug~0.0.0.0~istioctl~cluster.local",
+				},
+				TypeUrl: v3.DebugType,
+			}
+			// The proxy is only connected to one instance of Istiod, so ask all of them.
+			xdsResponses, err := multixds.AllRequestAndProcessXds(&xdsRequest, centralOpts, ctx.IstioNamespace(),
+				"", "", kubeClient, multixds.DefaultOptions)
+			if err != nil {
+				return err
+			}
+			if len(args) > 0 {
+				size, err := proxyConfigSize(xdsResponses)
+				if err != nil {
+					return err
+				}
+				if top > 0 && top < len(size.Contributors) {
+					size.Contributors = size.Contributors[:top]
+				}
+				return printProxyConfigSize(c.OutOrStdout(), size, outputFormat)
+			}
+			sizes := allConfigSizes(xdsResponses)
+			if top > 0 && top < len(sizes) {
+				sizes = sizes[:top]
+			}
+			return printConfigSizes(c.OutOrStdout(), sizes, outputFormat)
+		},
+		ValidArgsFunction: completion.ValidPodsNameArgs(ctx),
+	}
+	opts.AttachControlPlaneFlags(cmd)
+	centralOpts.AttachControlPlaneFlags(cmd)
+	cmd.Flags().IntVar(&top, "top", 10, "Number of proxies, or config objects for a single proxy, to show. 0 shows all of them")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", util.TableFormat, "Output format: one of table|json|yaml")
+	cmd.Long += "\n\n" + util.ExperimentalMsg
+	return cmd
+}
+
+// proxyConfigSize returns the report from the Istiod instance the proxy is connected to.
+func proxyConfigSize(drs map[string]*discovery.DiscoveryResponse) (*xds.ConfigSizeDebug, error) {
+	var errs []string
+	for _, dr := range drs {
+		for _, resource := range dr.Resources {
+			size := &xds.ConfigSizeDebug{}
+			if err := json.Unmarshal(resource.Value, size); err != nil {
+				// Instances the proxy is not connected to respond with an error message.
+				errs = append(errs, strings.TrimSpace(string(resource.Value)))
+				continue
+			}
+			return size, nil
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to get the config size: %s", errs[0])
+	}
+	return nil, fmt.Errorf("no response from Istiod")
+}
+
+// allConfigSizes merges the reports of all Istiod instances, largest configuration first.
+func allConfigSizes(drs map[string]*discovery.DiscoveryResponse) []xds.ConfigSizeDebug {
+	out := []xds.ConfigSizeDebug{}
+	for _, dr := range drs {
+		for _, resource := range dr.Resources {
+			var sizes []xds.ConfigSizeDebug
+			if err := json.Unmarshal(resource.Value, &sizes); err != nil {
+				continue
+			}
+			out = append(out, sizes...)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].TotalBytes != out[j].TotalBytes {
+			return out[i].TotalBytes > out[j].TotalBytes
+		}
+		return out[i].ProxyID < out[j].ProxyID
+	})
+	return out
+}
+
+func printStructured(w io.Writer, obj any, format string) (bool, error) {
+	switch format {
+	case util.JSONFormat:
+		b, err := json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			return true, err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return true, err
+	case util.YamlFormat:
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return true, err
+		}
+		_, err = fmt.Fprint(w, string(b))
+		return true, err
+	case util.TableFormat:
+		return false, nil
+	default:
+		return true, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func printConfigSizes(w io.Writer, sizes []xds.ConfigSizeDebug, format string) error {
+	if done, err := printStructured(w, sizes, format); done {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(tw, "PROXY\tLISTENERS\tROUTES\tVIRTUAL HOSTS\tCLUSTERS\tENDPOINTS\tSIZE")
+	for _, s := range sizes {
+		r := s.Resources
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%s\n", s.ProxyID, r.Listeners.Count, r.Routes.Count, r.VirtualHosts.Count,
+			r.Clusters.Count, r.Endpoints.Count, pilotutil.ByteCount(s.TotalBytes))
+	}
+	return tw.Flush()
+}
+
+func printProxyConfigSize(w io.Writer, size *xds.ConfigSizeDebug, format string) error {
+	if done, err := printStructured(w, size, format); done {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(tw, "RESOURCE\tCOUNT\tSIZE")
+	r := size.Resources
+	for _, row := range []struct {
+		name string
+		size xds.ConfigSizeResource
+	}{
+		{"Listeners", r.Listeners},
+		{"Routes", r.Routes},
+		{"Virtual hosts", r.VirtualHosts},
+		{"Clusters", r.Clusters},
+		{"Endpoints", r.Endpoints},
+	} {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", row.name, row.size.Count, pilotutil.ByteCount(row.size.Bytes))
+	}
+	fmt.Fprintf(tw, "Total\t\t%s\n", pilotutil.ByteCount(size.TotalBytes))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if len(size.Contributors) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tNAME\tNAMESPACE\tCLUSTERS\tVIRTUAL HOSTS\tENDPOINTS\tSIZE")
+	for _, c := range size.Contributors {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%d\t%s\n", c.Kind, c.Name, c.Namespace, c.Clusters, c.VirtualHosts, c.Endpoints,
+			pilotutil.ByteCount(c.Bytes))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,108 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configsize
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"istio.io/istio/istioctl/pkg/util"
+	"istio.io/istio/pilot/pkg/xds"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func debugResponse(t *testing.T, obj any) *discovery.DiscoveryResponse {
+	value, ok := obj.(string)
+	if !ok {
+		b, err := json.Marshal(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		value = string(b)
+	}
+	return &discovery.DiscoveryResponse{Resources: []*anypb.Any{{Value: []byte(value)}}}
+}
+
+func TestProxyConfigSize(t *testing.T) {
+	want := xds.ConfigSizeDebug{
+		ProxyID:    "productpage.default",
+		TotalBytes: 4500,
+		Resources: xds.ConfigSizeResources{
+			Listeners: xds.ConfigSizeResource{Count: 2, Bytes: 1000},
+			Routes:    xds.ConfigSizeResource{Count: 1, Bytes: 500},
+			Clusters:  xds.ConfigSizeResource{Count: 3, Bytes: 3000},
+		},
+		Contributors: []xds.ConfigSizeContributor{
+			{Kind: "DestinationRule", Name: "reviews", Namespace: "default", Clusters: 2, Bytes: 2000},
+			{Kind: "Service", Name: "reviews", Namespace: "default", Clusters: 1, Bytes: 1000},
+		},
+	}
+	got, err := proxyConfigSize(map[string]*discovery.DiscoveryResponse{
+		"istiod-1": debugResponse(t, `{"statusCode":"404"}Proxy not connected to this Pilot instance.`),
+		"istiod-2": debugResponse(t, want),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, *got, want)
+
+	_, err = proxyConfigSize(map[string]*discovery.DiscoveryResponse{
+		"istiod-1": debugResponse(t, `{"statusCode":"404"}Proxy not connected to this Pilot instance.`),
+	})
+	if err == nil || !strings.Contains(err.Error(), "Proxy not connected") {
+		t.Fatalf("expected an error reporting the proxy is not connected, got %v", err)
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, printProxyConfigSize(&out, got, util.TableFormat))
+	assert.Equal(t, out.String(), `RESOURCE        COUNT   SIZE
+Listeners       2       1.0kB
+Routes          1       500B
+Virtual hosts   0       0B
+Clusters        3       3.0kB
+Endpoints       0       0B
+Total                   4.5kB
+
+KIND              NAME      NAMESPACE   CLUSTERS   VIRTUAL HOSTS   ENDPOINTS   SIZE
+DestinationRule   reviews   default     2          0               0           2.0kB
+Service           reviews   default     1          0               0           1.0kB
+`)
+}
+
+func TestAllConfigSizes(t *testing.T) {
+	got := allConfigSizes(map[string]*discovery.DiscoveryResponse{
+		"istiod-1": debugResponse(t, []xds.ConfigSizeDebug{
+			{ProxyID: "a.default", TotalBytes: 100},
+			{ProxyID: "b.default", TotalBytes: 300},
+		}),
+		"istiod-2": debugResponse(t, []xds.ConfigSizeDebug{
+			{ProxyID: "c.default", TotalBytes: 200, Resources: xds.ConfigSizeResources{Clusters: xds.ConfigSizeResource{Count: 4}}},
+		}),
+	})
+	ids := []string{}
+	for _, s := range got {
+		ids = append(ids, s.ProxyID)
+	}
+	assert.Equal(t, ids, []string{"b.default", "c.default", "a.default"})
+
+	var out bytes.Buffer
+	assert.NoError(t, printConfigSizes(&out, got[1:2], util.TableFormat))
+	assert.Equal(t, out.String(), `PROXY       LISTENERS   ROUTES   VIRTUAL HOSTS   CLUSTERS   ENDPOINTS   SIZE
+c.default   0           0        0               4          0           200B
+`)
+}
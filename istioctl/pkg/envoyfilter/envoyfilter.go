@@ -0,0 +1,196 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoyfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"istio.io/istio/istioctl/pkg/cli"
+	"istio.io/istio/istioctl/pkg/util"
+	"istio.io/istio/istioctl/pkg/util/configgen"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/pkg/version"
+)
+
+// Cmd groups commands used for inspecting EnvoyFilters.
+func Cmd(ctx cli.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "envoyfilter",
+		Short: "Inspect Istio EnvoyFilters",
+	}
+
+	cmd.AddCommand(previewCmd(ctx))
+	cmd.Long += "\n\n" + util.ExperimentalMsg
+	return cmd
+}
+
+func previewCmd(ctx cli.Context) *cobra.Command {
+	var (
+		filenames    []string
+		meshFile     string
+		proxyType    string
+		proxyLabels  map[string]string
+		proxyIP      string
+		proxyVersion string
+		showDiff     bool
+		outputFormat string
+	)
+	cmd := &cobra.Command{
+		Use:   "preview <name>[.<namespace>]",
+		Short: "Preview the effect of an EnvoyFilter on the configuration of a proxy",
+		Long: `Preview generates the configuration of a proxy offline, with and without the given EnvoyFilter, and reports
+which listeners, clusters and route configurations each patch of the EnvoyFilter changed.
+Patches that did not change anything, because they did not match or were a no-op, are flagged.
+
+The proxy and its environment are described by the input files, which may contain Istio configuration and
+Kubernetes Services. The proxy is a member of the Services in its namespace which select its labels.`,
+		Example: `  # Preview the effect of EnvoyFilter lua-filter in namespace default on a sidecar with label app=productpage
+  istioctl x envoyfilter preview lua-filter -f services.yaml -f envoyfilters.yaml -l app=productpage
+
+  # Preview an EnvoyFilter in the root namespace on an ingress gateway, and show the changes
+  istioctl x envoyfilter preview add-header.istio-system -f config.yaml --proxy-type router \
+    -n istio-system -l istio=ingressgateway --diff`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				cmd.Println(cmd.UsageString())
+				return fmt.Errorf("preview requires <name>[.<namespace>]")
+			}
+			if len(filenames) == 0 {
+				return fmt.Errorf("at least one input file is required")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var pt model.NodeType
+			switch proxyType {
+			case string(model.SidecarProxy):
+				pt = model.SidecarProxy
+			case string(model.Router):
+				pt = model.Router
+			default:
+				return fmt.Errorf("unsupported proxy type %q, must be %q or %q", proxyType, model.SidecarProxy, model.Router)
+			}
+			proxyNamespace := ctx.NamespaceOrDefault(ctx.Namespace())
+			name, namespace, ok := strings.Cut(args[0], ".")
+			if !ok {
+				namespace = proxyNamespace
+			}
+
+			in, err := configgen.ReadInputs(filenames)
+			if err != nil {
+				return err
+			}
+			if meshFile != "" {
+				if in.Mesh, err = mesh.ReadMeshConfig(meshFile); err != nil {
+					return fmt.Errorf("failed to read mesh config %s: %v", meshFile, err)
+				}
+			}
+			report, err := Preview(in, name, namespace, configgen.ProxyOptions{
+				Type:         pt,
+				Namespace:    proxyNamespace,
+				Labels:       proxyLabels,
+				IP:           proxyIP,
+				IstioVersion: proxyVersion,
+			}, showDiff)
+			if err != nil {
+				return err
+			}
+			return printReport(cmd.OutOrStdout(), report, outputFormat)
+		},
+	}
+	cmd.Flags().StringSliceVarP(&filenames, "filename", "f", nil,
+		"Input files with the Istio configuration and Kubernetes Services used to generate the proxy configuration")
+	cmd.Flags().StringVar(&meshFile, "meshConfigFile", "", "Mesh config file to use, instead of the default mesh config")
+	cmd.Flags().StringVar(&proxyType, "proxy-type", string(model.SidecarProxy),
+		fmt.Sprintf("Type of the proxy, %q or %q", model.SidecarProxy, model.Router))
+	cmd.Flags().StringToStringVarP(&proxyLabels, "labels", "l", nil, "Labels of the proxy workload")
+	cmd.Flags().StringVar(&proxyIP, "ip", "10.0.0.1", "IP address of the proxy")
+	cmd.Flags().StringVar(&proxyVersion, "proxy-version", version.Info.Version,
+		"Istio version of the proxy, used to match the proxyVersion of patches")
+	cmd.Flags().BoolVar(&showDiff, "diff", false, "Show the changes made to each modified resource")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", util.TableFormat, "Output format: one of table|json|yaml")
+	return cmd
+}
+
+func printReport(w io.Writer, report *Report, format string) error {
+	switch format {
+	case util.JSONFormat:
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	case util.YamlFormat:
+		b, err := yaml.Marshal(report)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(w, string(b))
+		return err
+	case util.TableFormat:
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+
+	if !report.Selected {
+		fmt.Fprintf(w, "Warning: EnvoyFilter %s does not apply to %s\n", report.EnvoyFilter, report.Proxy)
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(tw, "PATCH\tAPPLY TO\tOPERATION\tCONTEXT\tCHANGES")
+	for _, p := range report.Patches {
+		changes := []string{"<none>"}
+		if p.Note != "" {
+			changes = []string{"<not previewed>"}
+		} else if len(p.Changes) > 0 {
+			changes = changes[:0]
+			for _, c := range p.Changes {
+				changes = append(changes, fmt.Sprintf("%s/%s (%s)", c.Type, c.Name, c.Change))
+			}
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", p.Index, p.ApplyTo, p.Operation, p.Context, changes[0])
+		for _, c := range changes[1:] {
+			fmt.Fprintf(tw, "\t\t\t\t%s\n", c)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, p := range report.Patches {
+		switch {
+		case p.Note != "":
+			fmt.Fprintf(w, "Note: patch %d: %s\n", p.Index, p.Note)
+		case p.NoEffect() && report.Selected:
+			fmt.Fprintf(w, "Warning: patch %d had no effect: it did not match any configuration, or did not change it\n", p.Index)
+		}
+	}
+	for _, p := range report.Patches {
+		for _, c := range p.Changes {
+			if c.Diff != "" {
+				fmt.Fprintf(w, "\nPatch %d:\n%s", p.Index, c.Diff)
+			}
+		}
+	}
+	return nil
+}
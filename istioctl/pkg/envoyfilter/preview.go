@@ -0,0 +1,231 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoyfilter
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"google.golang.org/protobuf/proto"
+
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/istioctl/pkg/util/configgen"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/networking/core"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/util/protomarshal"
+)
+
+// Change describes how a patch changed a generated resource.
+type Change string
+
+const (
+	Added    Change = "added"
+	Removed  Change = "removed"
+	Modified Change = "modified"
+)
+
+// ResourceChange is a single listener, cluster or route configuration changed by a patch.
+type ResourceChange struct {
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Change Change `json:"change"`
+	Diff   string `json:"diff,omitempty"`
+}
+
+// PatchResult is the effect of a single patch of the previewed EnvoyFilter.
+type PatchResult struct {
+	Index     int              `json:"index"`
+	ApplyTo   string           `json:"applyTo"`
+	Operation string           `json:"operation"`
+	Context   string           `json:"context,omitempty"`
+	Changes   []ResourceChange `json:"changes,omitempty"`
+	// Note explains why the effect of the patch could not be previewed, if set.
+	Note string `json:"note,omitempty"`
+}
+
+// NoEffect returns true if the patch did not change any generated resource.
+func (p PatchResult) NoEffect() bool {
+	return len(p.Changes) == 0 && p.Note == ""
+}
+
+// Report is the result of previewing an EnvoyFilter against a proxy.
+type Report struct {
+	EnvoyFilter string `json:"envoyFilter"`
+	Proxy       string `json:"proxy"`
+	// Selected is false if the EnvoyFilter does not apply to the proxy at all, because of its
+	// namespace, workload selector, target references or proxy version matches.
+	Selected bool          `json:"selected"`
+	Patches  []PatchResult `json:"patches"`
+}
+
+// Preview generates the configuration of the proxy without the given EnvoyFilter, then applies its patches one at a
+// time, and reports which listeners, clusters and route configurations each patch changed.
+// Patches are applied cumulatively, so patches that depend on previous ones are previewed correctly.
+func Preview(in *configgen.Inputs, name, namespace string, proxy configgen.ProxyOptions, withDiff bool) (*Report, error) {
+	var target *config.Config
+	var base []config.Config
+	for i, c := range in.Configs {
+		if c.GroupVersionKind == gvk.EnvoyFilter && c.Name == name && c.Namespace == namespace {
+			target = &in.Configs[i]
+			continue
+		}
+		base = append(base, c)
+	}
+	if target == nil {
+		return nil, fmt.Errorf("EnvoyFilter %s.%s not found in the input files", name, namespace)
+	}
+	spec := target.Spec.(*networking.EnvoyFilter)
+
+	report := &Report{
+		EnvoyFilter: name + "." + namespace,
+		Proxy:       proxy.String(),
+	}
+
+	previous, _, err := generate(in, base, proxy, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i, cp := range spec.ConfigPatches {
+		partial := target.DeepCopy()
+		partialSpec := partial.Spec.(*networking.EnvoyFilter)
+		partialSpec.ConfigPatches = partialSpec.ConfigPatches[:i+1]
+		current, selected, err := generate(in, append(slices.Clone(base), partial), proxy, &partial)
+		if err != nil {
+			return nil, err
+		}
+		report.Selected = report.Selected || selected
+
+		res := PatchResult{
+			Index:     i,
+			ApplyTo:   cp.GetApplyTo().String(),
+			Operation: cp.GetPatch().GetOperation().String(),
+		}
+		if cp.GetMatch().GetContext() != networking.EnvoyFilter_ANY {
+			res.Context = cp.GetMatch().GetContext().String()
+		}
+		if cp.GetApplyTo() == networking.EnvoyFilter_EXTENSION_CONFIG {
+			res.Note = "extension configurations are served over ECDS, and are not previewed"
+		} else if res.Changes, err = diffResources(previous, current, withDiff); err != nil {
+			return nil, err
+		}
+		report.Patches = append(report.Patches, res)
+		previous = current
+	}
+	return report, nil
+}
+
+type resourceKey struct {
+	Type string
+	Name string
+}
+
+type resources map[resourceKey]proto.Message
+
+// generate builds the listeners, clusters and routes of the proxy from the given configs. If ef is set, it also
+// returns whether it applies to the proxy.
+func generate(in *configgen.Inputs, configs []config.Config, opts configgen.ProxyOptions, ef *config.Config) (resources, bool, error) {
+	out := resources{}
+	selected := false
+	err := configgen.Generate(in, configs, opts, func(cg *core.ConfigGenTest, p *model.Proxy) {
+		listeners := cg.Listeners(p)
+		for _, l := range listeners {
+			out[resourceKey{"Listener", l.Name}] = l
+		}
+		for _, c := range cg.Clusters(p) {
+			out[resourceKey{"Cluster", c.Name}] = c
+		}
+		for _, r := range cg.RoutesFromListeners(p, listeners) {
+			out[resourceKey{"RouteConfiguration", r.Name}] = r
+		}
+		if ef != nil {
+			selected = selectsProxy(cg.PushContext().EnvoyFilters(p), ef)
+		}
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return out, selected, nil
+}
+
+func selectsProxy(efw *model.MergedEnvoyFilterWrapper, ef *config.Config) bool {
+	if efw == nil {
+		return false
+	}
+	for _, patches := range efw.Patches {
+		for _, p := range patches {
+			if p.Name == ef.Name && p.Namespace == ef.Namespace {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func diffResources(before, after resources, withDiff bool) ([]ResourceChange, error) {
+	var changes []ResourceChange
+	for k, b := range before {
+		a, f := after[k]
+		switch {
+		case !f:
+			changes = append(changes, ResourceChange{Type: k.Type, Name: k.Name, Change: Removed})
+		case !proto.Equal(a, b):
+			change := ResourceChange{Type: k.Type, Name: k.Name, Change: Modified}
+			if withDiff {
+				d, err := unifiedDiff(k, b, a)
+				if err != nil {
+					return nil, err
+				}
+				change.Diff = d
+			}
+			changes = append(changes, change)
+		}
+	}
+	for k := range after {
+		if _, f := before[k]; !f {
+			changes = append(changes, ResourceChange{Type: k.Type, Name: k.Name, Change: Added})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Type != changes[j].Type {
+			return changes[i].Type < changes[j].Type
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes, nil
+}
+
+func unifiedDiff(k resourceKey, before, after proto.Message) (string, error) {
+	b, err := protomarshal.ToJSONWithIndent(before, "  ")
+	if err != nil {
+		return "", err
+	}
+	a, err := protomarshal.ToJSONWithIndent(after, "  ")
+	if err != nil {
+		return "", err
+	}
+	name := strings.ToLower(k.Type) + "/" + k.Name
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(b),
+		B:        difflib.SplitLines(a),
+		FromFile: name + " (before)",
+		ToFile:   name + " (after)",
+		Context:  3,
+	})
+}
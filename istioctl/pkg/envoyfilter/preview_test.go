@@ -0,0 +1,125 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoyfilter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"istio.io/istio/istioctl/pkg/util/configgen"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func readTestInputs(t *testing.T) *configgen.Inputs {
+	in, err := configgen.ReadInputs([]string{"testdata/services.yaml", "testdata/envoyfilter.yaml"})
+	assert.NoError(t, err)
+	assert.Equal(t, len(in.Services), 2)
+	assert.Equal(t, len(in.Configs), 1)
+	return in
+}
+
+func TestPreview(t *testing.T) {
+	in := readTestInputs(t)
+	report, err := Preview(in, "productpage-lua", "default", configgen.ProxyOptions{
+		Type:      model.SidecarProxy,
+		Namespace: "default",
+		Labels:    map[string]string{"app": "productpage"},
+		IP:        "10.0.0.1",
+	}, true)
+	assert.NoError(t, err)
+
+	assert.Equal(t, report.Selected, true)
+	assert.Equal(t, len(report.Patches), 3)
+
+	lua := report.Patches[0]
+	assert.Equal(t, lua.ApplyTo, "HTTP_FILTER")
+	assert.Equal(t, lua.Context, "SIDECAR_INBOUND")
+	assert.Equal(t, len(lua.Changes), 1)
+	assert.Equal(t, lua.Changes[0].Type, "Listener")
+	assert.Equal(t, lua.Changes[0].Name, model.VirtualInboundListenerName)
+	assert.Equal(t, lua.Changes[0].Change, Modified)
+	if !strings.Contains(lua.Changes[0].Diff, "envoy.filters.http.lua") {
+		t.Fatalf("expected diff to contain the lua filter, got %s", lua.Changes[0].Diff)
+	}
+
+	cluster := report.Patches[1]
+	assert.Equal(t, cluster.Changes, []ResourceChange{{
+		Type:   "Cluster",
+		Name:   "outbound|9080||reviews.default.svc.cluster.local",
+		Change: Modified,
+		Diff:   cluster.Changes[0].Diff,
+	}})
+
+	// The ratings service does not exist
+	assert.Equal(t, report.Patches[2].NoEffect(), true)
+}
+
+func TestPreviewNotSelected(t *testing.T) {
+	in := readTestInputs(t)
+	report, err := Preview(in, "productpage-lua", "default", configgen.ProxyOptions{
+		Type:      model.SidecarProxy,
+		Namespace: "default",
+		Labels:    map[string]string{"app": "reviews"},
+		IP:        "10.0.0.2",
+	}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, report.Selected, false)
+	for _, p := range report.Patches {
+		assert.Equal(t, p.NoEffect(), true)
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, printReport(&out, report, "table"))
+	if !strings.HasPrefix(out.String(), "Warning: EnvoyFilter productpage-lua.default does not apply to sidecar in namespace default") {
+		t.Fatalf("unexpected output: %s", out.String())
+	}
+}
+
+func TestPreviewMissingEnvoyFilter(t *testing.T) {
+	in := readTestInputs(t)
+	_, err := Preview(in, "missing", "default", configgen.ProxyOptions{Type: model.SidecarProxy, Namespace: "default", IP: "10.0.0.1"}, false)
+	assert.Error(t, err)
+}
+
+func TestPrintReport(t *testing.T) {
+	report := &Report{
+		EnvoyFilter: "lua.default",
+		Proxy:       "sidecar in namespace default",
+		Selected:    true,
+		Patches: []PatchResult{
+			{
+				Index: 0, ApplyTo: "HTTP_FILTER", Operation: "INSERT_BEFORE", Context: "SIDECAR_INBOUND",
+				Changes: []ResourceChange{
+					{Type: "Listener", Name: "virtualInbound", Change: Modified},
+					{Type: "Listener", Name: "0.0.0.0_80", Change: Modified},
+				},
+			},
+			{Index: 1, ApplyTo: "CLUSTER", Operation: "MERGE"},
+			{Index: 2, ApplyTo: "EXTENSION_CONFIG", Operation: "ADD", Note: "not previewed"},
+		},
+	}
+	var out bytes.Buffer
+	assert.NoError(t, printReport(&out, report, "table"))
+	assert.Equal(t, out.String(), `PATCH   APPLY TO           OPERATION       CONTEXT           CHANGES
+0       HTTP_FILTER        INSERT_BEFORE   SIDECAR_INBOUND   Listener/virtualInbound (modified)
+                                                             Listener/0.0.0.0_80 (modified)
+1       CLUSTER            MERGE                             <none>
+2       EXTENSION_CONFIG   ADD                               <not previewed>
+Warning: patch 1 had no effect: it did not match any configuration, or did not change it
+Note: patch 2: not previewed
+`)
+}
@@ -0,0 +1,373 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	clientnetworking "istio.io/client-go/pkg/apis/networking/v1beta1"
+	"istio.io/istio/istioctl/pkg/cli"
+	"istio.io/istio/istioctl/pkg/install/k8sversion"
+	"istio.io/istio/istioctl/pkg/util"
+	"istio.io/istio/pilot/pkg/config/kube/crdclient"
+	"istio.io/istio/pilot/pkg/config/kube/gatewaycommon"
+	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/inject"
+	"istio.io/istio/pkg/test/util/yml"
+)
+
+const (
+	injectConfigMapName = "istio-sidecar-injector"
+	meshConfigMapName   = "istio"
+)
+
+// Cmd provides tools for the gateways deployed for Gateway API Gateways.
+func Cmd(ctx cli.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gateway",
+		Short: "Tools for the gateways Istio deploys for Gateway API Gateways",
+	}
+	cmd.AddCommand(renderCmd(ctx))
+	return cmd
+}
+
+type renderArgs struct {
+	files            []string
+	revision         string
+	injectConfigFile string
+	valuesFile       string
+	meshConfigFile   string
+	kubeVersion      string
+}
+
+func renderCmd(ctx cli.Context) *cobra.Command {
+	args := renderArgs{}
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Render the resources Istio deploys for Gateways",
+		Long: `Render prints the Deployments, Services, ServiceAccounts, HorizontalPodAutoscalers and PodDisruptionBudgets
+Istiod deploys for Gateway API Gateways and waypoints, so that they can be reviewed or managed with GitOps.
+
+The resources are rendered with the same templates and logic as Istiod, taking into account the GatewayClass, the
+gateway.istio.io annotations, the GatewayClass defaults and infrastructure parameters ConfigMaps, the ListenerSets
+and the Namespaces in the files, as well as ProxyConfig resources selecting the gateways.
+
+The injection templates, values and mesh config of the revision are read from the cluster, unless they are all
+provided with --injectConfigFile, --valuesFile and --meshConfigFile. When reading from the cluster, the GatewayClass
+defaults, infrastructure parameters and Namespaces that are not in the files are read from the cluster as well.
+
+As for Istiod, the built-in GatewayClasses are enabled with environment variables: waypoints are only rendered with
+PILOT_ENABLE_AMBIENT=true.`,
+		Example: `  # Render the resources deployed for a Gateway
+  istioctl x gateway render -f gateway.yaml
+
+  # Render the resources deployed for a waypoint by the canary revision
+  istioctl x gateway render -f waypoint.yaml --revision canary
+
+  # Render the resources without a cluster
+  istioctl x gateway render -f gateway.yaml --injectConfigFile inject-config.yaml \
+    --valuesFile values.json --meshConfigFile mesh.yaml --kube-version 1.33`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			if len(args.files) == 0 {
+				return fmt.Errorf("no files provided, use -f to provide the Gateways to render")
+			}
+			opts, err := renderOptions(ctx, args)
+			if err != nil {
+				return err
+			}
+			rendered, err := gatewaycommon.Render(opts)
+			if err != nil {
+				return err
+			}
+			if len(rendered) == 0 {
+				return fmt.Errorf("no Gateway deployed by revision %q found", args.revision)
+			}
+			printRendered(c.OutOrStdout(), rendered)
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVarP(&args.files, "filename", "f", nil,
+		"Files with the Gateways to render, and the GatewayClasses, ListenerSets, ConfigMaps, Namespaces and ProxyConfigs affecting them")
+	cmd.Flags().StringVarP(&args.revision, "revision", "r", "", "Revision deploying the Gateways")
+	cmd.Flags().StringVar(&args.injectConfigFile, "injectConfigFile", "",
+		"Injection configuration filename, with the gateway templates. Cannot be used with --revision")
+	cmd.Flags().StringVar(&args.valuesFile, "valuesFile", "", "Injection values configuration filename")
+	cmd.Flags().StringVar(&args.meshConfigFile, "meshConfigFile", "", "Mesh configuration filename")
+	cmd.Flags().StringVar(&args.kubeVersion, "kube-version", "",
+		fmt.Sprintf("Kubernetes version to render for, such as 1.33. Defaults to the version of the cluster, or 1.%d without a cluster",
+			k8sversion.MinK8SVersion))
+	cmd.Long += "\n\n" + util.ExperimentalMsg
+	return cmd
+}
+
+// renderOptions reads the objects to render from the files, and the configuration of the revision from the files
+// or the cluster.
+func renderOptions(ctx cli.Context, args renderArgs) (gatewaycommon.RenderOptions, error) {
+	opts := gatewaycommon.RenderOptions{Revision: args.revision, SystemNamespace: ctx.IstioNamespace()}
+	for _, f := range args.files {
+		objects, proxyConfigs, err := readObjects(f)
+		if err != nil {
+			return opts, err
+		}
+		opts.Objects = append(opts.Objects, objects...)
+		opts.ProxyConfigs = append(opts.ProxyConfigs, proxyConfigs...)
+	}
+
+	offline := args.injectConfigFile != "" && args.valuesFile != "" && args.meshConfigFile != ""
+	var rawConfig, values string
+	var meshConfig *meshconfig.MeshConfig
+	var err error
+	if offline {
+		if args.revision != "" {
+			return opts, fmt.Errorf("--revision cannot be used with --injectConfigFile")
+		}
+		if rawConfig, err = readFile(args.injectConfigFile); err != nil {
+			return opts, err
+		}
+		if values, err = readFile(args.valuesFile); err != nil {
+			return opts, err
+		}
+		if meshConfig, err = mesh.ReadMeshConfig(args.meshConfigFile); err != nil {
+			return opts, err
+		}
+		opts.KubeVersion = strconv.Itoa(k8sversion.MinK8SVersion)
+	} else {
+		if args.injectConfigFile != "" || args.valuesFile != "" || args.meshConfigFile != "" {
+			return opts, fmt.Errorf("--injectConfigFile, --valuesFile and --meshConfigFile must be used together")
+		}
+		client, err := ctx.CLIClientWithRevision(args.revision)
+		if err != nil {
+			return opts, err
+		}
+		if rawConfig, values, meshConfig, err = readRevisionConfig(client, ctx.IstioNamespace(), args.revision); err != nil {
+			return opts, err
+		}
+		if opts.Objects, err = addClusterObjects(client, ctx.IstioNamespace(), opts.Objects); err != nil {
+			return opts, err
+		}
+		v, err := client.GetKubernetesVersion()
+		if err != nil {
+			return opts, fmt.Errorf("failed to get the Kubernetes version: %v", err)
+		}
+		opts.KubeVersion = strings.TrimSuffix(v.Minor, "+")
+	}
+	if args.kubeVersion != "" {
+		major, minor, ok := strings.Cut(strings.TrimPrefix(args.kubeVersion, "v"), ".")
+		if _, err := strconv.Atoi(minor); !ok || major != "1" || err != nil {
+			return opts, fmt.Errorf("invalid Kubernetes version %q, expected a version such as 1.33", args.kubeVersion)
+		}
+		opts.KubeVersion = minor
+	}
+
+	injectConfig, err := inject.UnmarshalConfig([]byte(rawConfig))
+	if err != nil {
+		return opts, fmt.Errorf("invalid injection configuration: %v", err)
+	}
+	valuesConfig, err := inject.NewValuesConfig(values)
+	if err != nil {
+		return opts, fmt.Errorf("invalid injection values: %v", err)
+	}
+	opts.InjectConfig = inject.WebhookConfig{
+		Templates:  injectConfig.Templates,
+		Values:     valuesConfig,
+		MeshConfig: meshConfig,
+	}
+	// Istiod runs with the cluster name of the values as its cluster ID.
+	opts.ClusterID = cluster.ID(valuesConfig.Struct().GetGlobal().GetMultiCluster().GetClusterName())
+	if opts.ClusterID == "" {
+		opts.ClusterID = constants.DefaultClusterName
+	}
+	return opts, nil
+}
+
+func readFile(f string) (string, error) {
+	b, err := os.ReadFile(f)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// readObjects reads the objects affecting the rendering of Gateways from a file. Other objects are ignored.
+func readObjects(f string) ([]runtime.Object, []config.Config, error) {
+	b, err := readFile(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	var objects []runtime.Object
+	var proxyConfigs []config.Config
+	for _, doc := range yml.SplitString(b) {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		obj, _, err := kube.IstioCodec.UniversalDeserializer().Decode([]byte(doc), nil, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %v", f, err)
+		}
+		withNamespace := func(m *metav1.ObjectMeta) {
+			if m.Namespace == "" {
+				m.Namespace = metav1.NamespaceDefault
+			}
+		}
+		switch o := obj.(type) {
+		case *gatewayv1.Gateway:
+			withNamespace(&o.ObjectMeta)
+		case *gatewayv1.ListenerSet:
+			withNamespace(&o.ObjectMeta)
+		case *corev1.ConfigMap:
+			withNamespace(&o.ObjectMeta)
+		case *gatewayv1.GatewayClass, *corev1.Namespace:
+		case *clientnetworking.ProxyConfig:
+			withNamespace(&o.ObjectMeta)
+			proxyConfigs = append(proxyConfigs, crdclient.TranslateObject(o, gvk.ProxyConfig, constants.DefaultClusterLocalDomain))
+			continue
+		default:
+			continue
+		}
+		objects = append(objects, obj)
+	}
+	return objects, proxyConfigs, nil
+}
+
+// readRevisionConfig reads the injection templates, values and mesh config of a revision.
+func readRevisionConfig(client kube.CLIClient, istioNamespace, revision string) (string, string, *meshconfig.MeshConfig, error) {
+	injectName, meshName := injectConfigMapName, meshConfigMapName
+	if revision != "" {
+		injectName += "-" + revision
+		meshName += "-" + revision
+	}
+	configMaps := client.Kube().CoreV1().ConfigMaps(istioNamespace)
+	injectCM, err := configMaps.Get(context.Background(), injectName, metav1.GetOptions{})
+	if err != nil {
+		return "", "", nil, fmt.Errorf("could not read the injection configuration %s/%s: %v - "+
+			"use --injectConfigFile, --valuesFile and --meshConfigFile, or --revision to select the revision", istioNamespace, injectName, err)
+	}
+	meshCM, err := configMaps.Get(context.Background(), meshName, metav1.GetOptions{})
+	if err != nil {
+		return "", "", nil, fmt.Errorf("could not read the mesh configuration %s/%s: %v", istioNamespace, meshName, err)
+	}
+	meshConfig, err := mesh.ApplyMeshConfigDefaults(meshCM.Data["mesh"])
+	if err != nil {
+		return "", "", nil, fmt.Errorf("invalid mesh configuration in %s/%s: %v", istioNamespace, meshName, err)
+	}
+	return injectCM.Data["config"], injectCM.Data["values"], meshConfig, nil
+}
+
+// addClusterObjects adds the GatewayClasses, GatewayClass defaults, infrastructure parameters and Namespaces that
+// affect the Gateways, when they are not in the files.
+func addClusterObjects(client kube.CLIClient, istioNamespace string, objects []runtime.Object) ([]runtime.Object, error) {
+	ctx := context.Background()
+	have := map[string]bool{}
+	key := func(kind, namespace, name string) string {
+		return kind + "/" + namespace + "/" + name
+	}
+	var gateways []*gatewayv1.Gateway
+	for _, o := range objects {
+		switch o := o.(type) {
+		case *gatewayv1.Gateway:
+			gateways = append(gateways, o)
+		case *gatewayv1.GatewayClass:
+			have[key(gvk.GatewayClass.Kind, "", o.Name)] = true
+		case *corev1.ConfigMap:
+			have[key(gvk.ConfigMap.Kind, o.Namespace, o.Name)] = true
+		case *corev1.Namespace:
+			have[key(gvk.Namespace.Kind, "", o.Name)] = true
+		}
+	}
+	add := func(kind string, obj runtime.Object, namespace, name string) {
+		if !have[key(kind, namespace, name)] {
+			have[key(kind, namespace, name)] = true
+			objects = append(objects, obj)
+		}
+	}
+
+	classes, err := client.GatewayAPI().GatewayV1().GatewayClasses().List(ctx, metav1.ListOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to list GatewayClasses: %v", err)
+	}
+	if classes != nil {
+		for i := range classes.Items {
+			c := &classes.Items[i]
+			add(gvk.GatewayClass.Kind, c, "", c.Name)
+		}
+	}
+	defaults, err := client.Kube().CoreV1().ConfigMaps(istioNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: gatewaycommon.GatewayClassDefaults,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list GatewayClass defaults: %v", err)
+	}
+	for i := range defaults.Items {
+		cm := &defaults.Items[i]
+		add(gvk.ConfigMap.Kind, cm, cm.Namespace, cm.Name)
+	}
+	for _, gw := range gateways {
+		if !have[key(gvk.Namespace.Kind, "", gw.Namespace)] {
+			ns, err := client.Kube().CoreV1().Namespaces().Get(ctx, gw.Namespace, metav1.GetOptions{})
+			if err != nil && !kerrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get namespace %s: %v", gw.Namespace, err)
+			}
+			if err == nil {
+				add(gvk.Namespace.Kind, ns, "", ns.Name)
+			}
+		}
+		if gw.Spec.Infrastructure == nil || gw.Spec.Infrastructure.ParametersRef == nil {
+			continue
+		}
+		if p := gw.Spec.Infrastructure.ParametersRef; string(p.Kind) == gvk.ConfigMap.Kind &&
+			!have[key(gvk.ConfigMap.Kind, gw.Namespace, p.Name)] {
+			cm, err := client.Kube().CoreV1().ConfigMaps(gw.Namespace).Get(ctx, p.Name, metav1.GetOptions{})
+			if err != nil && !kerrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get ConfigMap %s/%s: %v", gw.Namespace, p.Name, err)
+			}
+			if err == nil {
+				add(gvk.ConfigMap.Kind, cm, cm.Namespace, cm.Name)
+			}
+		}
+	}
+	return objects, nil
+}
+
+func printRendered(w io.Writer, rendered []gatewaycommon.RenderedGateway) {
+	first := true
+	for _, gw := range rendered {
+		for _, r := range gw.Resources {
+			if !first {
+				fmt.Fprintln(w, "---")
+			}
+			first = false
+			fmt.Fprint(w, r)
+		}
+	}
+}
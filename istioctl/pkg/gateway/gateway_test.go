@@ -0,0 +1,104 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+
+	"istio.io/istio/istioctl/pkg/cli"
+	"istio.io/istio/pilot/pkg/config/kube/gatewaycommon"
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/test/util"
+	"istio.io/istio/pkg/test/env"
+	"istio.io/istio/pkg/test/util/assert"
+	"istio.io/istio/pkg/test/util/file"
+)
+
+func init() {
+	features.EnableAmbientWaypoints = true
+	// Recompute with waypoints enabled
+	gatewaycommon.ClassInfos = gatewaycommon.GetClassInfos()
+	gatewaycommon.BuiltinGatewayClasses = gatewaycommon.GetBuiltinGatewayClasses()
+}
+
+func TestRender(t *testing.T) {
+	dir := t.TempDir()
+	templates := map[string]string{}
+	for _, name := range []string{"kube-gateway", "waypoint"} {
+		templates[name] = file.AsStringOrFail(t, filepath.Join(env.IstioSrc, "manifests/charts/istio-control/istio-discovery/files", name+".yaml"))
+	}
+	injectConfig, err := yaml.Marshal(map[string]any{"templates": templates})
+	assert.NoError(t, err)
+	injectConfigFile := filepath.Join(dir, "inject-config.yaml")
+	assert.NoError(t, os.WriteFile(injectConfigFile, injectConfig, 0o644))
+	valuesFile := filepath.Join(dir, "values.json")
+	assert.NoError(t, os.WriteFile(valuesFile, []byte(`{"global":{"hub":"test","tag":"test"}}`), 0o644))
+	meshConfigFile := filepath.Join(dir, "mesh.yaml")
+	assert.NoError(t, os.WriteFile(meshConfigFile, []byte("trustDomain: cluster.local\n"), 0o644))
+
+	cases := []struct {
+		name   string
+		args   []string
+		golden string
+		err    string
+	}{
+		{
+			name:   "offline",
+			args:   []string{"-f", "testdata/gateway.yaml"},
+			golden: "testdata/gateway.yaml.golden",
+		},
+		{
+			name: "no files",
+			err:  "no files provided",
+		},
+		{
+			name: "partial configuration",
+			args: []string{"-f", "testdata/gateway.yaml", "--injectConfigFile", injectConfigFile},
+			err:  "must be used together",
+		},
+		{
+			name: "invalid kube version",
+			args: []string{"-f", "testdata/gateway.yaml", "--kube-version", "33"},
+			err:  "invalid Kubernetes version",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"render"}, tt.args...)
+			if tt.name != "partial configuration" {
+				args = append(args, "--injectConfigFile", injectConfigFile, "--valuesFile", valuesFile, "--meshConfigFile", meshConfigFile)
+			}
+			cmd := Cmd(cli.NewFakeContext(&cli.NewFakeContextOption{IstioNamespace: "istio-system"}))
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(args)
+			err := cmd.Execute()
+			if tt.err != "" {
+				assert.Error(t, err)
+				assert.Equal(t, true, strings.Contains(err.Error(), tt.err), err.Error())
+				return
+			}
+			assert.NoError(t, err)
+			util.CompareContent(t, out.Bytes(), tt.golden)
+		})
+	}
+}
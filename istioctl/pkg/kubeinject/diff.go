@@ -0,0 +1,565 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeinject
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	admitv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"istio.io/api/label"
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/istioctl/pkg/cli"
+	"istio.io/istio/istioctl/pkg/completion"
+	"istio.io/istio/istioctl/pkg/util"
+	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/inject"
+	"istio.io/istio/pkg/slices"
+)
+
+// serviceAccountVolumePrefix is the prefix of the token volume Kubernetes adds to every pod.
+const serviceAccountVolumePrefix = "kube-api-access-"
+
+// Cmd is a group of experimental commands related to sidecar injection of running workloads.
+func Cmd(ctx cli.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inject",
+		Short: "Commands related to sidecar injection of running workloads",
+	}
+	cmd.AddCommand(diffCmd(ctx))
+	return cmd
+}
+
+func diffCmd(ctx cli.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <namespace|pod>",
+		Short: "Show how the injected sidecars of running pods would change on restart",
+		Long: `Re-runs sidecar injection against the template of each running pod's owner, using the injection configuration
+of the webhook that currently selects the pod, and shows how the injected containers, volumes and annotations would
+change if the pod was restarted. Pods with identical changes are grouped together.
+
+If a namespace with the given name exists, all running pods in it are compared. Otherwise, the argument is a pod.`,
+		Example: `  # Show how the pods in namespace foo would change after moving it to a new revision
+  kubectl label namespace foo istio-injection- istio.io/rev=1-25
+  istioctl x inject diff foo
+
+  # Show how a single pod would change on restart
+  istioctl x inject diff productpage-v1-6b746f74dc-9stvs.default`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := ctx.CLIClient()
+			if err != nil {
+				return err
+			}
+			pods, err := targetPods(ctx, client, args[0])
+			if err != nil {
+				return err
+			}
+			d, err := newInjectDiffer(client, ctx.IstioNamespace())
+			if err != nil {
+				return err
+			}
+			results := make([]podDiff, 0, len(pods))
+			for _, pod := range pods {
+				results = append(results, d.diffPod(pod))
+			}
+			printInjectDiff(cmd.OutOrStdout(), results)
+			return nil
+		},
+		ValidArgsFunction: completion.ValidPodsNameArgs(ctx),
+	}
+}
+
+// targetPods returns the running pods in the namespace named arg, or else the pod named arg.
+func targetPods(ctx cli.Context, client kube.CLIClient, arg string) ([]*corev1.Pod, error) {
+	if !strings.ContainsAny(arg, "./") {
+		_, err := client.Kube().CoreV1().Namespaces().Get(context.TODO(), arg, metav1.GetOptions{})
+		if err == nil {
+			pods, err := client.Kube().CoreV1().Pods(arg).List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			var res []*corev1.Pod
+			for i := range pods.Items {
+				if pods.Items[i].Status.Phase == corev1.PodRunning {
+					res = append(res, &pods.Items[i])
+				}
+			}
+			return res, nil
+		}
+		if !kerrors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	podName, ns, err := ctx.InferPodInfoFromTypedResource(arg, ctx.NamespaceOrDefault(ctx.Namespace()))
+	if err != nil {
+		return nil, err
+	}
+	pod, err := client.Kube().CoreV1().Pods(ns).Get(context.TODO(), podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return []*corev1.Pod{pod}, nil
+}
+
+// injectConfig is the injection configuration of a revision.
+type injectConfig struct {
+	templates inject.Templates
+	values    inject.ValuesConfig
+	mesh      *meshconfig.MeshConfig
+}
+
+// injectDiffer re-runs injection for running pods.
+type injectDiffer struct {
+	client         kube.CLIClient
+	istioNamespace string
+	injector       inject.Injector
+	webhooks       []admitv1.MutatingWebhookConfiguration
+	namespaces     map[string]*corev1.Namespace
+	configs        map[string]*injectConfig
+}
+
+// localInjector runs injection in istioctl, rather than calling the webhook. It provides the client used to detect
+// native sidecar support.
+type localInjector struct {
+	client kube.Client
+}
+
+func (l localInjector) Inject(*corev1.Pod, string) ([]byte, error) {
+	return nil, nil
+}
+
+func (l localInjector) GetKubeClient() kube.Client {
+	return l.client
+}
+
+func newInjectDiffer(client kube.CLIClient, istioNamespace string) (*injectDiffer, error) {
+	whs, err := client.Kube().AdmissionregistrationV1().MutatingWebhookConfigurations().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &injectDiffer{
+		client:         client,
+		istioNamespace: istioNamespace,
+		injector:       localInjector{client: client},
+		webhooks:       whs.Items,
+		namespaces:     map[string]*corev1.Namespace{},
+		configs:        map[string]*injectConfig{},
+	}, nil
+}
+
+// podDiff is the result of comparing a running pod to its re-injected template.
+type podDiff struct {
+	// pod is the pod name, in the form name.namespace.
+	pod string
+	// diff is the unified diff of the injected fields, or empty if the pod would not change.
+	diff string
+	// skipped is the reason the pod was not compared, if any.
+	skipped string
+}
+
+func (d *injectDiffer) diffPod(pod *corev1.Pod) podDiff {
+	res := podDiff{pod: pod.Name + "." + pod.Namespace}
+	owner, err := d.owner(pod)
+	if err != nil {
+		res.skipped = err.Error()
+		return res
+	}
+	ns, err := d.namespace(pod.Namespace)
+	if err != nil {
+		res.skipped = err.Error()
+		return res
+	}
+	template := podTemplate(owner)
+	revision := injectionRevision(d.webhooks, ns, template.Labels)
+	desired := template
+	if revision != "" {
+		desired, err = d.inject(owner, revision)
+		if err != nil {
+			res.skipped = err.Error()
+			return res
+		}
+	}
+	res.diff, err = diffInjection(pod, desired)
+	if err != nil {
+		res.skipped = err.Error()
+	}
+	return res
+}
+
+// owner returns the workload that created the pod, with its type metadata set.
+func (d *injectDiffer) owner(pod *corev1.Pod) (runtime.Object, error) {
+	meta, typ := kube.GetDeployMetaFromPod(pod)
+	var obj runtime.Object
+	var err error
+	switch typ.Kind {
+	case "Deployment":
+		obj, err = d.client.Kube().AppsV1().Deployments(meta.Namespace).Get(context.TODO(), meta.Name, metav1.GetOptions{})
+	case "ReplicaSet":
+		obj, err = d.client.Kube().AppsV1().ReplicaSets(meta.Namespace).Get(context.TODO(), meta.Name, metav1.GetOptions{})
+	case "StatefulSet":
+		obj, err = d.client.Kube().AppsV1().StatefulSets(meta.Namespace).Get(context.TODO(), meta.Name, metav1.GetOptions{})
+	case "DaemonSet":
+		obj, err = d.client.Kube().AppsV1().DaemonSets(meta.Namespace).Get(context.TODO(), meta.Name, metav1.GetOptions{})
+	default:
+		return nil, fmt.Errorf("owner kind %q is not supported", typ.Kind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get owner %s %s: %v", typ.Kind, meta.Name, err)
+	}
+	obj.GetObjectKind().SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind(typ.Kind))
+	return obj, nil
+}
+
+func (d *injectDiffer) namespace(name string) (*corev1.Namespace, error) {
+	if ns, f := d.namespaces[name]; f {
+		return ns, nil
+	}
+	ns, err := d.client.Kube().CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	d.namespaces[name] = ns
+	return ns, nil
+}
+
+// inject re-runs injection for the owner's pod template, with the injection configuration of the revision.
+func (d *injectDiffer) inject(owner runtime.Object, revision string) (*corev1.PodTemplateSpec, error) {
+	cfg, err := d.config(revision)
+	if err != nil {
+		return nil, err
+	}
+	// Templates render the default revision as an empty one.
+	if revision == util.DefaultRevisionName {
+		revision = ""
+	}
+	out, err := inject.IntoObject(d.injector, cfg.templates, cfg.values, revision, cfg.mesh, owner, func(string) {})
+	if err != nil {
+		return nil, err
+	}
+	return podTemplate(out.(runtime.Object)), nil
+}
+
+// config returns the injection configuration of the revision, from the injector and mesh ConfigMaps.
+func (d *injectDiffer) config(revision string) (*injectConfig, error) {
+	if cfg, f := d.configs[revision]; f {
+		return cfg, nil
+	}
+	injectName, meshName := defaultInjectConfigMapName, defaultMeshConfigMapName
+	if revision != util.DefaultRevisionName {
+		injectName += "-" + revision
+		meshName += "-" + revision
+	}
+	cms := d.client.Kube().CoreV1().ConfigMaps(d.istioNamespace)
+	injectCM, err := cms.Get(context.TODO(), injectName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("could not read injection configmap %q for revision %q: %v", injectName, revision, err)
+	}
+	rawConfig, err := inject.UnmarshalConfig([]byte(injectCM.Data[injectConfigMapKey]))
+	if err != nil {
+		return nil, err
+	}
+	templates, err := inject.ParseTemplates(rawConfig.RawTemplates)
+	if err != nil {
+		return nil, err
+	}
+	values, err := inject.NewValuesConfig(injectCM.Data[valuesConfigMapKey])
+	if err != nil {
+		return nil, err
+	}
+	meshCM, err := cms.Get(context.TODO(), meshName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("could not read mesh configmap %q for revision %q: %v", meshName, revision, err)
+	}
+	mc, err := mesh.ApplyMeshConfigDefaults(meshCM.Data[configMapKey])
+	if err != nil {
+		return nil, err
+	}
+	cfg := &injectConfig{templates: templates, values: values, mesh: mc}
+	d.configs[revision] = cfg
+	return cfg, nil
+}
+
+// injectionRevision returns the revision of the sidecar injection webhook that selects a pod with the given labels in
+// the namespace, or an empty string if none does.
+func injectionRevision(webhooks []admitv1.MutatingWebhookConfiguration, ns *corev1.Namespace, podLabels map[string]string) string {
+	for _, whc := range webhooks {
+		for _, wh := range whc.Webhooks {
+			if !strings.HasSuffix(wh.Name, defaultWebhookName) {
+				continue
+			}
+			if selectorMatches(wh.NamespaceSelector, ns.Labels) && selectorMatches(wh.ObjectSelector, podLabels) {
+				return whc.Labels[label.IoIstioRev.Name]
+			}
+		}
+	}
+	return ""
+}
+
+func selectorMatches(selector *metav1.LabelSelector, labels map[string]string) bool {
+	if selector == nil {
+		return true
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return s.Matches(klabels.Set(labels))
+}
+
+func podTemplate(obj runtime.Object) *corev1.PodTemplateSpec {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return &o.Spec.Template
+	case *appsv1.ReplicaSet:
+		return &o.Spec.Template
+	case *appsv1.StatefulSet:
+		return &o.Spec.Template
+	case *appsv1.DaemonSet:
+		return &o.Spec.Template
+	}
+	return nil
+}
+
+// injectedFields are the parts of a pod that injection changes.
+type injectedFields struct {
+	Annotations    map[string]string  `json:"annotations"`
+	InitContainers []corev1.Container `json:"initContainers"`
+	Containers     []corev1.Container `json:"containers"`
+	Volumes        []corev1.Volume    `json:"volumes"`
+}
+
+// diffInjection returns a unified diff from the injected fields of the running pod to those of the desired template.
+func diffInjection(pod *corev1.Pod, desired *corev1.PodTemplateSpec) (string, error) {
+	running := pod.DeepCopy()
+	removeServiceAccountVolume(&running.Spec)
+	want, err := toMap(injectedFields{
+		Annotations:    desired.Annotations,
+		InitContainers: desired.Spec.InitContainers,
+		Containers:     desired.Spec.Containers,
+		Volumes:        desired.Spec.Volumes,
+	})
+	if err != nil {
+		return "", err
+	}
+	got, err := toMap(injectedFields{
+		Annotations:    running.Annotations,
+		InitContainers: running.Spec.InitContainers,
+		Containers:     running.Spec.Containers,
+		Volumes:        running.Spec.Volumes,
+	})
+	if err != nil {
+		return "", err
+	}
+	projected := project(got, want).(map[string]any)
+	// Keep the Istio annotations of the running pod, so removed ones are shown.
+	if annotations, ok := got["annotations"].(map[string]any); ok {
+		kept, _ := projected["annotations"].(map[string]any)
+		if kept == nil {
+			kept = map[string]any{}
+		}
+		for k, v := range annotations {
+			if strings.Contains(k, "istio.io") {
+				kept[k] = v
+			}
+		}
+		projected["annotations"] = kept
+	}
+
+	a, err := yaml.Marshal(projected)
+	if err != nil {
+		return "", err
+	}
+	b, err := yaml.Marshal(want)
+	if err != nil {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(a)),
+		B:        difflib.SplitLines(string(b)),
+		FromFile: "running",
+		ToFile:   "restarted",
+		Context:  3,
+	})
+}
+
+// removeServiceAccountVolume removes the token volume Kubernetes adds to every pod, which is not in the template.
+func removeServiceAccountVolume(spec *corev1.PodSpec) {
+	isToken := func(name string) bool {
+		return strings.HasPrefix(name, serviceAccountVolumePrefix)
+	}
+	spec.Volumes = slices.FilterInPlace(spec.Volumes, func(v corev1.Volume) bool {
+		return !isToken(v.Name)
+	})
+	if len(spec.Volumes) == 0 {
+		// Don't report a change if the token was the only volume.
+		spec.Volumes = nil
+	}
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			containers[i].VolumeMounts = slices.FilterInPlace(containers[i].VolumeMounts, func(m corev1.VolumeMount) bool {
+				return !isToken(m.Name)
+			})
+			if len(containers[i].VolumeMounts) == 0 {
+				containers[i].VolumeMounts = nil
+			}
+		}
+	}
+}
+
+func toMap(f injectedFields) (map[string]any, error) {
+	b, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	res := map[string]any{}
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// project returns the running value, keeping only the fields set in the desired value, so fields defaulted by the API
+// server are not shown as changed. Items of lists of named objects are matched by name, and items only in the running
+// value are kept, as they would be removed.
+func project(running, desired any) any {
+	switch want := desired.(type) {
+	case map[string]any:
+		got, ok := running.(map[string]any)
+		if !ok {
+			return running
+		}
+		res := map[string]any{}
+		for k, v := range want {
+			if g, f := got[k]; f {
+				res[k] = project(g, v)
+			}
+		}
+		return res
+	case []any:
+		got, ok := running.([]any)
+		if !ok {
+			return running
+		}
+		if wantByName, named := byName(want); named {
+			res := make([]any, 0, len(got))
+			for _, g := range got {
+				if w, f := wantByName[itemName(g)]; f {
+					res = append(res, project(g, w))
+				} else {
+					res = append(res, g)
+				}
+			}
+			return res
+		}
+		if len(got) != len(want) {
+			return running
+		}
+		res := make([]any, 0, len(got))
+		for i := range got {
+			res = append(res, project(got[i], want[i]))
+		}
+		return res
+	}
+	return running
+}
+
+func byName(items []any) (map[string]any, bool) {
+	if len(items) == 0 {
+		return nil, false
+	}
+	res := make(map[string]any, len(items))
+	for _, item := range items {
+		name := itemName(item)
+		if name == "" {
+			return nil, false
+		}
+		res[name] = item
+	}
+	return res, true
+}
+
+func itemName(item any) string {
+	m, ok := item.(map[string]any)
+	if !ok {
+		return ""
+	}
+	name, _ := m["name"].(string)
+	return name
+}
+
+func printInjectDiff(w io.Writer, results []podDiff) {
+	var unchanged, skipped []podDiff
+	groups := map[string][]string{}
+	for _, r := range results {
+		switch {
+		case r.skipped != "":
+			skipped = append(skipped, r)
+		case r.diff == "":
+			unchanged = append(unchanged, r)
+		default:
+			groups[r.diff] = append(groups[r.diff], r.pod)
+		}
+	}
+	if len(results) == 0 {
+		fmt.Fprintln(w, "No running pods found.")
+		return
+	}
+
+	// Show the largest groups first.
+	diffs := make([]string, 0, len(groups))
+	for diff := range groups {
+		slices.Sort(groups[diff])
+		diffs = append(diffs, diff)
+	}
+	slices.SortFunc(diffs, func(a, b string) int {
+		if r := cmp.Compare(len(groups[b]), len(groups[a])); r != 0 {
+			return r
+		}
+		return cmp.Compare(groups[a][0], groups[b][0])
+	})
+	for _, diff := range diffs {
+		pods := groups[diff]
+		fmt.Fprintf(w, "%d pod(s) would change on restart:\n", len(pods))
+		for _, p := range pods {
+			fmt.Fprintf(w, "  %s\n", p)
+		}
+		fmt.Fprintln(w, diff)
+	}
+	if len(unchanged) > 0 {
+		fmt.Fprintf(w, "%d pod(s) would not change on restart:\n", len(unchanged))
+		for _, r := range unchanged {
+			fmt.Fprintf(w, "  %s\n", r.pod)
+		}
+	}
+	for _, r := range skipped {
+		fmt.Fprintf(w, "Skipped %s: %s\n", r.pod, r.skipped)
+	}
+}
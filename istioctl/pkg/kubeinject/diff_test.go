@@ -0,0 +1,149 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeinject
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	admitv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"istio.io/api/label"
+	"istio.io/istio/istioctl/pkg/cli"
+	"istio.io/istio/pilot/test/util"
+	"istio.io/istio/pkg/ptr"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func injectorWebhook(revision, webhookName string, namespaceSelector map[string]string) *admitv1.MutatingWebhookConfiguration {
+	return &admitv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "istio-sidecar-injector-" + revision,
+			Labels: map[string]string{label.IoIstioRev.Name: revision},
+		},
+		Webhooks: []admitv1.MutatingWebhook{{
+			Name:              webhookName,
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: namespaceSelector},
+		}},
+	}
+}
+
+func injectorConfigMaps(t *testing.T, revision, suffix string) []runtime.Object {
+	injectName, meshName := defaultInjectConfigMapName, defaultMeshConfigMapName
+	if revision != "default" {
+		injectName += "-" + revision
+		meshName += "-" + revision
+	}
+	config, err := os.ReadFile("testdata/inject-config.yaml")
+	assert.NoError(t, err)
+	meshConfig, err := os.ReadFile("testdata/mesh-config.yaml")
+	assert.NoError(t, err)
+	return []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: injectName, Namespace: "istio-system"},
+			Data: map[string]string{
+				injectConfigMapKey: string(config),
+				valuesConfigMapKey: `{"global":{"suffix":"` + suffix + `"}}`,
+			},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: meshName, Namespace: "istio-system"},
+			Data:       map[string]string{configMapKey: string(meshConfig)},
+		},
+	}
+}
+
+func helloDeployment(t *testing.T, namespace string) *appsv1.Deployment {
+	b, err := os.ReadFile("testdata/deployment/hello.yaml")
+	assert.NoError(t, err)
+	d := &appsv1.Deployment{}
+	assert.NoError(t, yaml.Unmarshal(b, d))
+	d.Namespace = namespace
+	return d
+}
+
+func TestInjectDiff(t *testing.T) {
+	objects := []runtime.Object{
+		// Namespace a was moved to the canary revision, b still uses the default one.
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "a", Labels: map[string]string{label.IoIstioRev.Name: "canary"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "b", Labels: map[string]string{"istio-injection": "enabled"}}},
+		injectorWebhook("default", "namespace.sidecar-injector.istio.io", map[string]string{"istio-injection": "enabled"}),
+		injectorWebhook("canary", "rev.namespace.sidecar-injector.istio.io", map[string]string{label.IoIstioRev.Name: "canary"}),
+		helloDeployment(t, "a"),
+		helloDeployment(t, "b"),
+	}
+	objects = append(objects, injectorConfigMaps(t, "default", "test")...)
+	objects = append(objects, injectorConfigMaps(t, "canary", "canary")...)
+	ctx := cli.NewFakeContext(&cli.NewFakeContextOption{
+		IstioNamespace: "istio-system",
+		Objects:        objects,
+	})
+	client, err := ctx.CLIClient()
+	assert.NoError(t, err)
+
+	// All pods are running, injected by the default revision.
+	d, err := newInjectDiffer(client, "istio-system")
+	assert.NoError(t, err)
+	for _, p := range []struct{ name, namespace string }{{"hello-abc-1", "a"}, {"hello-abc-2", "a"}, {"hello-abc-3", "b"}} {
+		injected, err := d.inject(helloDeployment(t, p.namespace), "default")
+		assert.NoError(t, err)
+		pod := &corev1.Pod{
+			ObjectMeta: injected.ObjectMeta,
+			Spec:       injected.Spec,
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		pod.Name = p.name
+		pod.Namespace = p.namespace
+		pod.GenerateName = "hello-abc-"
+		pod.Labels["pod-template-hash"] = "abc"
+		pod.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "apps/v1",
+			Kind:       "ReplicaSet",
+			Name:       "hello-abc",
+			Controller: ptr.Of(true),
+		}}
+		// Kubernetes adds a token volume to every pod.
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{Name: "kube-api-access-1234"})
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts,
+			corev1.VolumeMount{Name: "kube-api-access-1234", MountPath: "/var/run/secrets/kubernetes.io/serviceaccount"})
+		_, err = client.Kube().CoreV1().Pods(p.namespace).Create(context.Background(), pod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+
+	for _, c := range []struct {
+		name   string
+		arg    string
+		golden string
+	}{
+		{name: "changed namespace", arg: "a", golden: "testdata/inject-diff/changed.golden"},
+		{name: "unchanged pod", arg: "hello-abc-3.b", golden: "testdata/inject-diff/unchanged.golden"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			cmd := Cmd(ctx)
+			cmd.SetArgs([]string{"diff", c.arg})
+			cmd.SetOut(out)
+			assert.NoError(t, cmd.Execute())
+			util.CompareContent(t, out.Bytes(), c.golden)
+		})
+	}
+}
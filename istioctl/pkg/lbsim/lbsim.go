@@ -0,0 +1,167 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbsim
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/istioctl/pkg/util"
+	"istio.io/istio/pilot/pkg/config/kube/crd"
+	"istio.io/istio/pkg/config/schema/gvk"
+)
+
+// Cmd simulates the load balancing of requests to a service.
+func Cmd() *cobra.Command {
+	var (
+		filename     string
+		topologyFile string
+		outputFormat string
+	)
+	cmd := &cobra.Command{
+		Use:   "lbsim",
+		Short: "Simulate the load balancing of a DestinationRule over a topology",
+		Long: `Lbsim simulates a client sending requests to the endpoints of a service, load balanced with the traffic policy
+of a DestinationRule, and reports how the requests were distributed across localities, the latency observed by the
+client, and how many requests failed.
+
+The priority and weight of each locality are computed in the same way as Istio, from the locality load balancer
+settings of the DestinationRule or the default mesh config. Failing endpoints respond to all requests with errors,
+and are ejected according to the outlier detection settings, moving traffic to the next priority level.
+
+The topology file describes the client, the endpoints and the network latencies between localities:
+
+  client:
+    locality: us-east/zone-a
+    rps: 1000
+    requests: 2000
+  endpoints:
+  - locality: us-east/zone-a
+    count: 3
+    failing: 3
+    serviceTime: 20ms
+  - locality: us-east/zone-b
+    count: 3
+  latencies:
+  - from: us-east/zone-a
+    to: us-east/zone-b
+    latency: 5ms
+
+The simulation runs in real time, so it takes about as long as sending all the requests at the given rate.`,
+		Example: `  # Simulate the default load balancing of Istio over a topology
+  istioctl x lbsim --topology topology.yaml
+
+  # Simulate the locality failover of a DestinationRule
+  istioctl x lbsim -f destinationrule.yaml --topology topology.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if topologyFile == "" {
+				return fmt.Errorf("a topology file is required")
+			}
+			topology, err := ReadTopology(topologyFile)
+			if err != nil {
+				return err
+			}
+			dr := &networking.DestinationRule{}
+			if filename != "" {
+				if dr, err = readDestinationRule(filename); err != nil {
+					return err
+				}
+			}
+			result, err := Simulate(dr, topology)
+			if err != nil {
+				return err
+			}
+			return printResult(cmd.OutOrStdout(), result, outputFormat)
+		},
+	}
+	cmd.Flags().StringVarP(&filename, "filename", "f", "",
+		"File with the DestinationRule to simulate. If not set, the default traffic policy is used")
+	cmd.Flags().StringVar(&topologyFile, "topology", "", "File describing the client, endpoints and network latencies")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", util.TableFormat, "Output format: one of table|json|yaml")
+	cmd.Long += "\n\n" + util.ExperimentalMsg
+	return cmd
+}
+
+func readDestinationRule(file string) (*networking.DestinationRule, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	configs, _, err := crd.ParseInputs(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", file, err)
+	}
+	var out *networking.DestinationRule
+	for _, c := range configs {
+		if c.GroupVersionKind != gvk.DestinationRule {
+			continue
+		}
+		if out != nil {
+			return nil, fmt.Errorf("%s must contain a single DestinationRule", file)
+		}
+		out = c.Spec.(*networking.DestinationRule)
+	}
+	if out == nil {
+		return nil, fmt.Errorf("no DestinationRule found in %s", file)
+	}
+	return out, nil
+}
+
+func printResult(w io.Writer, r *Result, format string) error {
+	switch format {
+	case util.JSONFormat:
+		b, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	case util.YamlFormat:
+		b, err := yaml.Marshal(r)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(w, string(b))
+		return err
+	case util.TableFormat:
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(tw, "LOCALITY\tPRIORITY\tENDPOINTS\tFAILING\tREQUESTS\tSHARE")
+	for _, l := range r.Localities {
+		priority := "-"
+		if l.Priority >= 0 {
+			priority = fmt.Sprint(l.Priority)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%.1f%%\n", l.Locality, priority, l.Endpoints, l.Failing, l.Requests, l.Percent)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nRequests: %d, errors: %d (%.1f%%), ejections: %d\n",
+		r.Requests, r.Errors, float64(r.Errors)*100/float64(r.Requests), r.Ejections)
+	fmt.Fprintf(w, "Latency: p50 %.1fms, p90 %.1fms, p99 %.1fms, max %.1fms\n", r.Latency.P50, r.Latency.P90, r.Latency.P99, r.Latency.Max)
+	return nil
+}
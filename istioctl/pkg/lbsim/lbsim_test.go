@@ -0,0 +1,122 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbsim
+
+import (
+	"bytes"
+	"testing"
+
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func simulate(t *testing.T, drFile string) *Result {
+	t.Helper()
+	topology, err := ReadTopology("testdata/topology.yaml")
+	assert.NoError(t, err)
+	dr := &networking.DestinationRule{}
+	if drFile != "" {
+		dr, err = readDestinationRule(drFile)
+		assert.NoError(t, err)
+	}
+	result, err := Simulate(dr, topology)
+	assert.NoError(t, err)
+
+	total := uint64(0)
+	for _, l := range result.Localities {
+		total += l.Requests
+	}
+	assert.Equal(t, total, uint64(result.Requests))
+	return result
+}
+
+func priorities(r *Result) []int {
+	var out []int
+	for _, l := range r.Localities {
+		out = append(out, l.Priority)
+	}
+	return out
+}
+
+func TestSimulateDefault(t *testing.T) {
+	// Without outlier detection, there is no failover and requests keep being sent to the failing endpoints
+	r := simulate(t, "")
+	assert.Equal(t, priorities(r), []int{0, 0, 0})
+	assert.Equal(t, r.Ejections, 0)
+	assert.Equal(t, r.Errors, r.Localities[0].Requests)
+	if r.Errors == 0 {
+		t.Fatalf("expected requests to the failing endpoints to fail: %+v", r)
+	}
+}
+
+func TestSimulateFailover(t *testing.T) {
+	r := simulate(t, "testdata/failover.yaml")
+	assert.Equal(t, priorities(r), []int{0, 1, 2})
+	assert.Equal(t, r.Ejections, 3)
+	// Once the local endpoints are ejected, all requests go to the other zone of the region
+	if r.Localities[1].Percent < 90 {
+		t.Fatalf("expected the requests to fail over to us-east/zone-b: %+v", r)
+	}
+	assert.Equal(t, r.Localities[2].Requests, uint64(0))
+}
+
+func TestSimulateDistribute(t *testing.T) {
+	r := simulate(t, "testdata/distribute.yaml")
+	assert.Equal(t, priorities(r), []int{-1, 0, 0})
+	assert.Equal(t, r.Errors, uint64(0))
+	if p := r.Localities[1].Percent; p < 70 || p > 90 {
+		t.Fatalf("expected about 80%% of the requests to go to us-east/zone-b, got %.1f%%", p)
+	}
+}
+
+func TestSimulateUnsupported(t *testing.T) {
+	topology, err := ReadTopology("testdata/topology.yaml")
+	assert.NoError(t, err)
+	_, err = Simulate(&networking.DestinationRule{
+		TrafficPolicy: &networking.TrafficPolicy{
+			LoadBalancer: &networking.LoadBalancerSettings{
+				LbPolicy: &networking.LoadBalancerSettings_Simple{Simple: networking.LoadBalancerSettings_RANDOM},
+			},
+		},
+	}, topology)
+	assert.Error(t, err)
+
+	topology.Endpoints[0].Failing = 4
+	_, err = Simulate(&networking.DestinationRule{}, topology)
+	assert.Error(t, err)
+}
+
+func TestPrintResult(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, printResult(&out, &Result{
+		Requests:  100,
+		Errors:    2,
+		Ejections: 1,
+		Latency:   Latency{P50: 5, P90: 7.25, P99: 12, Max: 20},
+		Localities: []LocalityResult{
+			{Locality: "us-east/zone-a", Priority: 0, Endpoints: 1, Failing: 1, Requests: 2, Percent: 2},
+			{Locality: "us-east/zone-b", Priority: 1, Endpoints: 2, Requests: 98, Percent: 98},
+			{Locality: "eu-west/zone-a", Priority: -1, Endpoints: 2},
+		},
+	}, "table"))
+	assert.Equal(t, out.String(), `LOCALITY         PRIORITY   ENDPOINTS   FAILING   REQUESTS   SHARE
+us-east/zone-a   0          1           1         2          2.0%
+us-east/zone-b   1          2           0         98         98.0%
+eu-west/zone-a   -          2           0         0          0.0%
+
+Requests: 100, errors: 2 (2.0%), ejections: 1
+Latency: p50 5.0ms, p90 7.2ms, p99 12.0ms, max 20.0ms
+`)
+}
@@ -0,0 +1,377 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbsim
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	networking "istio.io/api/networking/v1alpha3"
+	pilotlb "istio.io/istio/pilot/pkg/networking/core/loadbalancer"
+	"istio.io/istio/pilot/pkg/networking/util"
+	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/pkg/test/loadbalancersim/loadbalancer"
+	"istio.io/istio/pkg/test/loadbalancersim/locality"
+	simmesh "istio.io/istio/pkg/test/loadbalancersim/mesh"
+	"istio.io/istio/pkg/test/loadbalancersim/network"
+)
+
+const (
+	defaultRPS         = 1000
+	defaultRequests    = 1000
+	defaultServiceTime = 10 * time.Millisecond
+
+	// Envoy defaults for the outlier detection fields not set in the DestinationRule.
+	defaultConsecutiveErrors  = 5
+	defaultBaseEjectionTime   = 30 * time.Second
+	defaultMaxEjectionPercent = 10
+)
+
+// Topology describes the client and the endpoints of the simulated service.
+type Topology struct {
+	Client    ClientTopology   `json:"client"`
+	Endpoints []EndpointGroup  `json:"endpoints"`
+	Latencies []NetworkLatency `json:"latencies,omitempty"`
+	// QueueLatency adds a latency to requests growing with the number of requests queued on an endpoint.
+	QueueLatency bool `json:"queueLatency,omitempty"`
+}
+
+// ClientTopology describes the client sending requests to the service.
+type ClientTopology struct {
+	// Locality of the client, as <region>/<zone>.
+	Locality string `json:"locality"`
+	RPS      int    `json:"rps,omitempty"`
+	Requests int    `json:"requests,omitempty"`
+}
+
+// EndpointGroup is a set of identical endpoints in a locality.
+type EndpointGroup struct {
+	// Locality of the endpoints, as <region>/<zone>.
+	Locality string `json:"locality"`
+	Count    int    `json:"count"`
+	// Failing is the number of endpoints of the group responding to all requests with errors.
+	Failing     int             `json:"failing,omitempty"`
+	ServiceTime metav1.Duration `json:"serviceTime,omitempty"`
+}
+
+// NetworkLatency is the latency added to requests from a locality to another one.
+type NetworkLatency struct {
+	From    string          `json:"from"`
+	To      string          `json:"to"`
+	Latency metav1.Duration `json:"latency"`
+}
+
+// ReadTopology reads a topology from a YAML file.
+func ReadTopology(file string) (*Topology, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	t := &Topology{}
+	if err := yaml.UnmarshalStrict(b, t); err != nil {
+		return nil, fmt.Errorf("failed to read topology %s: %v", file, err)
+	}
+	return t, nil
+}
+
+// Result is the outcome of a simulation.
+type Result struct {
+	Requests   int              `json:"requests"`
+	Errors     uint64           `json:"errors"`
+	Ejections  int              `json:"ejections"`
+	Latency    Latency          `json:"latency"`
+	Localities []LocalityResult `json:"localities"`
+}
+
+// Latency summarizes the latency of the requests observed by the client, in milliseconds.
+type Latency struct {
+	P50 float64 `json:"p50Ms"`
+	P90 float64 `json:"p90Ms"`
+	P99 float64 `json:"p99Ms"`
+	Max float64 `json:"maxMs"`
+}
+
+// LocalityResult is the share of the requests received by an endpoint group.
+type LocalityResult struct {
+	Locality string `json:"locality"`
+	// Priority is the priority level of the endpoints for the client, or -1 if they are not sent any traffic.
+	Priority  int     `json:"priority"`
+	Endpoints int     `json:"endpoints"`
+	Failing   int     `json:"failing"`
+	Requests  uint64  `json:"requests"`
+	Percent   float64 `json:"percent"`
+}
+
+// policy is the part of a DestinationRule traffic policy used by the simulation.
+type policy struct {
+	newLB    func(conns []*loadbalancer.WeightedConnection) network.Connection
+	lb       *networking.LoadBalancerSettings
+	outlier  *loadbalancer.OutlierDetectionSettings
+	failover bool
+}
+
+func toPolicy(dr *networking.DestinationRule) (*policy, error) {
+	tp := dr.GetTrafficPolicy()
+	p := &policy{lb: tp.GetLoadBalancer()}
+	if p.lb.GetConsistentHash() != nil {
+		return nil, fmt.Errorf("consistent hash load balancing is not supported by the simulator")
+	}
+	switch p.lb.GetSimple() {
+	case networking.LoadBalancerSettings_UNSPECIFIED, networking.LoadBalancerSettings_LEAST_REQUEST, networking.LoadBalancerSettings_LEAST_CONN:
+		p.newLB = func(conns []*loadbalancer.WeightedConnection) network.Connection {
+			return loadbalancer.NewLeastRequest(loadbalancer.LeastRequestSettings{
+				Connections:       conns,
+				ActiveRequestBias: 1.0,
+			})
+		}
+	case networking.LoadBalancerSettings_ROUND_ROBIN:
+		p.newLB = loadbalancer.NewRoundRobin
+	default:
+		return nil, fmt.Errorf("load balancer %s is not supported by the simulator", p.lb.GetSimple())
+	}
+
+	if od := tp.GetOutlierDetection(); od != nil {
+		p.failover = true
+		s := &loadbalancer.OutlierDetectionSettings{
+			ConsecutiveErrors:  defaultConsecutiveErrors,
+			BaseEjectionTime:   defaultBaseEjectionTime,
+			MaxEjectionPercent: defaultMaxEjectionPercent,
+		}
+		// Failing endpoints respond with 503, which is both a 5xx and a gateway error.
+		if od.Consecutive_5XxErrors != nil || od.ConsecutiveGatewayErrors != nil {
+			s.ConsecutiveErrors = 0
+			for _, v := range []uint32{od.GetConsecutive_5XxErrors().GetValue(), od.GetConsecutiveGatewayErrors().GetValue()} {
+				if v > 0 && (s.ConsecutiveErrors == 0 || int(v) < s.ConsecutiveErrors) {
+					s.ConsecutiveErrors = int(v)
+				}
+			}
+		}
+		if od.BaseEjectionTime != nil {
+			s.BaseEjectionTime = od.BaseEjectionTime.AsDuration()
+		}
+		if od.MaxEjectionPercent > 0 {
+			s.MaxEjectionPercent = int(od.MaxEjectionPercent)
+		}
+		if s.ConsecutiveErrors > 0 {
+			p.outlier = s
+		}
+	}
+	return p, nil
+}
+
+// endpointGroup is the load assignment of an endpoint group computed by Istio for the client.
+type endpointGroup struct {
+	EndpointGroup
+	locality locality.Instance
+	priority int
+	weight   uint32
+	nodes    simmesh.Nodes
+}
+
+// assignPriorities computes the priority and weight of each endpoint group with the same locality load balancer
+// settings as Istio, resolved from the DestinationRule and the default mesh config.
+func assignPriorities(client *core.Locality, groups []*endpointGroup, p *policy) error {
+	m := mesh.DefaultMeshConfig()
+	settings := pilotlb.GetEffectiveLbSetting(m.GetLocalityLbSetting(), m.GetZoneAwareLbSetting(), p.lb, nil)
+	if settings == nil {
+		return nil
+	}
+	if settings.IsZoneAware() {
+		return fmt.Errorf("zone aware load balancing is not supported by the simulator")
+	}
+	if len(settings.FailoverPriorityLabels()) > 0 {
+		return fmt.Errorf("failover priority is not supported by the simulator")
+	}
+
+	la := &endpoint.ClusterLoadAssignment{}
+	for _, g := range groups {
+		lbEndpoints := make([]*endpoint.LbEndpoint, g.Count)
+		for i := range lbEndpoints {
+			lbEndpoints[i] = &endpoint.LbEndpoint{}
+		}
+		la.Endpoints = append(la.Endpoints, &endpoint.LocalityLbEndpoints{
+			Locality:    util.ConvertLocality(g.Locality),
+			LbEndpoints: lbEndpoints,
+		})
+	}
+	settings.ApplyToLoadAssignment(la, nil, client, nil, p.failover)
+
+	for i, g := range groups {
+		le := la.Endpoints[i]
+		if len(le.LbEndpoints) == 0 {
+			// Not matched by the distribute settings
+			g.priority = -1
+			continue
+		}
+		g.priority = int(le.Priority)
+		if w := le.GetLoadBalancingWeight(); w != nil {
+			g.weight = w.Value
+		}
+	}
+	return nil
+}
+
+// Simulate sends the requests of the client of the topology to its endpoints, load balanced with the traffic policy
+// of the DestinationRule, and reports how they were distributed. The simulation runs in real time.
+func Simulate(dr *networking.DestinationRule, t *Topology) (*Result, error) {
+	p, err := toPolicy(dr)
+	if err != nil {
+		return nil, err
+	}
+	clientLocality, err := parseLocality(t.Client.Locality)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client: %v", err)
+	}
+	rps := t.Client.RPS
+	if rps <= 0 {
+		rps = defaultRPS
+	}
+	requests := t.Client.Requests
+	if requests <= 0 {
+		requests = defaultRequests
+	}
+
+	latencies := make(map[simmesh.RouteKey]time.Duration, len(t.Latencies))
+	for _, l := range t.Latencies {
+		from, err := parseLocality(l.From)
+		if err != nil {
+			return nil, fmt.Errorf("invalid latency: %v", err)
+		}
+		to, err := parseLocality(l.To)
+		if err != nil {
+			return nil, fmt.Errorf("invalid latency: %v", err)
+		}
+		latencies[simmesh.RouteKey{Src: from, Dest: to}] = l.Latency.Duration
+	}
+
+	groups := make([]*endpointGroup, 0, len(t.Endpoints))
+	for _, e := range t.Endpoints {
+		l, err := parseLocality(e.Locality)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoints: %v", err)
+		}
+		if e.Count <= 0 || e.Failing < 0 || e.Failing > e.Count {
+			return nil, fmt.Errorf("invalid endpoints in %s: count must be positive, and failing between 0 and count", e.Locality)
+		}
+		if e.ServiceTime.Duration <= 0 {
+			e.ServiceTime.Duration = defaultServiceTime
+		}
+		groups = append(groups, &endpointGroup{EndpointGroup: e, locality: l})
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("the topology has no endpoints")
+	}
+	if err := assignPriorities(util.ConvertLocality(t.Client.Locality), groups, p); err != nil {
+		return nil, err
+	}
+
+	m := simmesh.New(simmesh.Settings{NetworkLatencies: latencies})
+	defer m.ShutDown()
+	client := m.NewClient(simmesh.ClientSettings{RPS: rps, Locality: clientLocality})
+
+	var od *loadbalancer.OutlierDetector
+	var healthy func(c *loadbalancer.WeightedConnection) bool
+	if p.outlier != nil {
+		od = loadbalancer.NewOutlierDetector(*p.outlier)
+		healthy = od.Healthy
+	}
+	var byPriority [][]*loadbalancer.WeightedConnection
+	for _, g := range groups {
+		g.nodes = m.NewNodes(g.Count, g.ServiceTime.Duration, t.QueueLatency, g.locality)
+		for i, n := range g.nodes {
+			n.SetFailing(i < g.Failing)
+		}
+		if g.priority < 0 {
+			continue
+		}
+		for len(byPriority) <= g.priority {
+			byPriority = append(byPriority, nil)
+		}
+		for _, n := range g.nodes {
+			wc := &loadbalancer.WeightedConnection{
+				Connection: m.NewConnection(client, n),
+				Weight:     hostWeight(g),
+			}
+			if od != nil {
+				wc = od.Track(wc, n.Failing)
+			}
+			byPriority[g.priority] = append(byPriority[g.priority], wc)
+		}
+	}
+	if len(byPriority) == 0 {
+		return nil, fmt.Errorf("no endpoints are selected by the locality load balancer settings")
+	}
+
+	lb := loadbalancer.NewFailover(loadbalancer.FailoverSettings{
+		Connections: byPriority,
+		Healthy:     healthy,
+		NewLB:       p.newLB,
+	})
+	done := make(chan struct{})
+	client.SendRequests(lb, requests, func() {
+		close(done)
+	})
+	<-done
+
+	out := &Result{Requests: requests}
+	if od != nil {
+		out.Ejections = od.Ejections()
+	}
+	latency := lb.Latency().Data()
+	q := latency.Quantiles(0.5, 0.9, 0.99)
+	out.Latency = Latency{
+		P50: q[0] * 1000,
+		P90: q[1] * 1000,
+		P99: q[2] * 1000,
+		Max: latency.Max() * 1000,
+	}
+	for _, g := range groups {
+		r := LocalityResult{
+			Locality:  g.Locality,
+			Priority:  g.priority,
+			Endpoints: g.Count,
+			Failing:   g.Failing,
+			Requests:  g.nodes.TotalRequests(),
+		}
+		r.Percent = float64(r.Requests) * 100 / float64(requests)
+		out.Errors += g.nodes[:g.Failing].TotalRequests()
+		out.Localities = append(out.Localities, r)
+	}
+	return out, nil
+}
+
+// hostWeight returns the weight of each endpoint of the group. Like Envoy, the locality weight set by the
+// distribute settings is shared by the endpoints of the locality.
+func hostWeight(g *endpointGroup) uint32 {
+	if g.weight == 0 {
+		return 1
+	}
+	return max(1, g.weight*100/uint32(g.Count))
+}
+
+func parseLocality(s string) (locality.Instance, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return locality.Instance{}, fmt.Errorf("locality %q must be <region>/<zone>", s)
+	}
+	return locality.Instance{Region: parts[0], Zone: parts[1]}, nil
+}
@@ -0,0 +1,1463 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/istioctl/pkg/util/configgen"
+	"istio.io/istio/pilot/pkg/model/credentials"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/protocol"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/kube/controllers"
+	"istio.io/istio/pkg/maps"
+	"istio.io/istio/pkg/ptr"
+	"istio.io/istio/pkg/slices"
+	"istio.io/istio/pkg/util/sets"
+)
+
+// Options configures the conversion of Istio configuration to the Gateway API.
+type Options struct {
+	// GatewayClass is the class of the converted Gateways.
+	GatewayClass string
+	// DomainSuffix is the domain suffix of the cluster, used to resolve Service hostnames.
+	DomainSuffix string
+}
+
+// Issue is a setting of an Istio resource that could not be expressed with the Gateway API.
+type Issue struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Field     string `json:"field,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+// Result holds the Gateway API resources converted from Istio configuration, and the settings that could not be
+// converted.
+type Result struct {
+	Resources []controllers.Object
+	Issues    []Issue
+}
+
+const (
+	// maxRules is the maximum number of rules of an HTTPRoute or GRPCRoute.
+	maxRules = 16
+	// redirectStatusCode is the status code of Istio redirects, which defaults to 302 in the Gateway API.
+	redirectStatusCode = 301
+)
+
+// gatewayRetryOn are the retry conditions Istio sets for Gateway API retries, in addition to status codes.
+var gatewayRetryOn = sets.New("connect-failure", "refused-stream", "unavailable", "cancelled", "retriable-status-codes")
+
+var sectionNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// listener describes a listener of a converted Gateway.
+type listener struct {
+	name     gatewayv1.SectionName
+	port     uint32
+	protocol gatewayv1.ProtocolType
+	// grpc is set if the Istio server only served gRPC.
+	grpc bool
+	// redirect is set if all requests to the listener are redirected to HTTPS.
+	redirect bool
+}
+
+type converter struct {
+	opts           Options
+	services       map[types.NamespacedName]*corev1.Service
+	serviceEntries map[string]config.Config
+	gateways       map[types.NamespacedName][]listener
+	// grants are the namespaces of the routes referencing Services in another namespace, by namespace of the Services.
+	grants map[string]sets.String
+	result *Result
+}
+
+// Convert translates the Gateways, VirtualServices and DestinationRules of the inputs into Gateways, HTTPRoutes,
+// GRPCRoutes, TLSRoutes, TCPRoutes and BackendTLSPolicies. Settings that cannot be expressed with the Gateway API
+// are reported as issues. Routing rules are only converted if their matches and backends can be fully expressed;
+// other unsupported settings are dropped from the converted rules.
+func Convert(in *configgen.Inputs, opts Options) *Result {
+	c := &converter{
+		opts:           opts,
+		services:       map[types.NamespacedName]*corev1.Service{},
+		serviceEntries: map[string]config.Config{},
+		gateways:       map[types.NamespacedName][]listener{},
+		grants:         map[string]sets.String{},
+		result:         &Result{},
+	}
+	for _, s := range in.Services {
+		c.services[types.NamespacedName{Namespace: s.Namespace, Name: s.Name}] = s
+	}
+	configs := slices.Clone(in.Configs)
+	sort.SliceStable(configs, func(i, j int) bool {
+		if configs[i].Namespace != configs[j].Namespace {
+			return configs[i].Namespace < configs[j].Namespace
+		}
+		return configs[i].Name < configs[j].Name
+	})
+	for _, cfg := range configs {
+		if cfg.GroupVersionKind == gvk.ServiceEntry {
+			for _, h := range cfg.Spec.(*networking.ServiceEntry).Hosts {
+				c.serviceEntries[h] = cfg
+			}
+		}
+	}
+	// Gateways are converted first, as the routes depend on their listeners.
+	for _, k := range []config.GroupVersionKind{gvk.Gateway, gvk.VirtualService, gvk.DestinationRule} {
+		for _, cfg := range configs {
+			if cfg.GroupVersionKind != k {
+				continue
+			}
+			switch k {
+			case gvk.Gateway:
+				c.convertGateway(cfg)
+			case gvk.VirtualService:
+				c.convertVirtualService(cfg)
+			case gvk.DestinationRule:
+				c.convertDestinationRule(cfg)
+			}
+		}
+	}
+	c.buildReferenceGrants()
+	return c.result
+}
+
+func (c *converter) report(cfg config.Config, field string, format string, args ...any) {
+	c.result.Issues = append(c.result.Issues, Issue{
+		Kind:      cfg.GroupVersionKind.Kind,
+		Name:      cfg.Name,
+		Namespace: cfg.Namespace,
+		Field:     field,
+		Reason:    fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *converter) add(obj controllers.Object) {
+	c.result.Resources = append(c.result.Resources, obj)
+}
+
+func objectMeta(k config.GroupVersionKind, name, namespace string) (metav1.TypeMeta, metav1.ObjectMeta) {
+	return metav1.TypeMeta{APIVersion: k.GroupVersion(), Kind: k.Kind}, metav1.ObjectMeta{Name: name, Namespace: namespace}
+}
+
+func (c *converter) convertGateway(cfg config.Config) {
+	gw := cfg.Spec.(*networking.Gateway)
+	if len(gw.Selector) > 0 {
+		c.report(cfg, "spec.selector", "the Gateway API deploys a new gateway instead of selecting existing gateway pods; "+
+			"TLS credentials must be in namespace %q", cfg.Namespace)
+	}
+	var listeners []gatewayv1.Listener
+	var infos []listener
+	var redirects []gatewayv1.SectionName
+	names := sets.New[gatewayv1.SectionName]()
+	for i, s := range gw.Servers {
+		field := fmt.Sprintf("spec.servers[%d]", i)
+		if s.Bind != "" {
+			c.report(cfg, field+".bind", "listeners cannot bind to a specific address; the server was converted without it")
+		}
+		if s.DefaultEndpoint != "" {
+			c.report(cfg, field+".defaultEndpoint", "default endpoints are not supported; the server was converted without it")
+		}
+		if s.Port.GetTargetPort() != 0 {
+			c.report(cfg, field+".port.targetPort", "target ports are not supported; the server was converted without it")
+		}
+		proto, tls, ok := c.convertServerProtocol(cfg, field, s)
+		if !ok {
+			continue
+		}
+		hosts := s.Hosts
+		for j, h := range hosts {
+			hostField := fmt.Sprintf("%s.hosts[%d]", field, j)
+			ns, hostname, _ := strings.Cut(h, "/")
+			if !strings.Contains(h, "/") {
+				ns, hostname = "*", h
+			}
+			if ns == "~" {
+				// No routes may bind to this host.
+				continue
+			}
+			name := listenerName(s, proto)
+			if len(hosts) > 1 {
+				name += "-" + strconv.Itoa(j)
+			}
+			for base, k := name, 1; names.Contains(gatewayv1.SectionName(name)); k++ {
+				name = base + "-" + strconv.Itoa(k)
+			}
+			l := gatewayv1.Listener{
+				Name:     gatewayv1.SectionName(name),
+				Port:     gatewayv1.PortNumber(s.Port.Number),
+				Protocol: proto,
+				TLS:      tls,
+			}
+			names.Insert(l.Name)
+			if hostname != "*" {
+				if strings.Contains(hostname, "*") && !strings.HasPrefix(hostname, "*.") {
+					c.report(cfg, hostField, "host %q is not a valid hostname; the host was not migrated", hostname)
+					continue
+				}
+				l.Hostname = ptr.Of(gatewayv1.Hostname(hostname))
+			}
+			switch ns {
+			case "*":
+				l.AllowedRoutes = &gatewayv1.AllowedRoutes{Namespaces: &gatewayv1.RouteNamespaces{From: ptr.Of(gatewayv1.NamespacesFromAll)}}
+			case ".":
+				l.AllowedRoutes = &gatewayv1.AllowedRoutes{Namespaces: &gatewayv1.RouteNamespaces{From: ptr.Of(gatewayv1.NamespacesFromSame)}}
+			default:
+				l.AllowedRoutes = &gatewayv1.AllowedRoutes{Namespaces: &gatewayv1.RouteNamespaces{
+					From: ptr.Of(gatewayv1.NamespacesFromSelector),
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{corev1.LabelMetadataName: ns},
+					},
+				}}
+			}
+			redirect := s.GetTls().GetHttpsRedirect() && proto == gatewayv1.HTTPProtocolType
+			if redirect {
+				redirects = append(redirects, l.Name)
+			}
+			listeners = append(listeners, l)
+			infos = append(infos, listener{
+				name:     l.Name,
+				port:     s.Port.Number,
+				protocol: proto,
+				grpc:     protocol.Parse(s.Port.Protocol) == protocol.GRPC,
+				redirect: redirect,
+			})
+		}
+	}
+	if len(listeners) == 0 {
+		c.report(cfg, "spec.servers", "no server could be converted; the Gateway was not migrated")
+		return
+	}
+	c.gateways[types.NamespacedName{Namespace: cfg.Namespace, Name: cfg.Name}] = infos
+
+	tm, om := objectMeta(gvk.KubernetesGateway, cfg.Name, cfg.Namespace)
+	c.add(&gatewayv1.Gateway{
+		TypeMeta:   tm,
+		ObjectMeta: om,
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: gatewayv1.ObjectName(c.opts.GatewayClass),
+			Listeners:        listeners,
+		},
+	})
+	if len(redirects) > 0 {
+		c.addHTTPSRedirect(cfg, redirects)
+	}
+}
+
+// convertServerProtocol returns the protocol and TLS settings of the listeners of a server.
+func (c *converter) convertServerProtocol(cfg config.Config, field string, s *networking.Server) (gatewayv1.ProtocolType, *gatewayv1.ListenerTLSConfig, bool) {
+	p := protocol.Parse(s.Port.Protocol)
+	switch {
+	case p == protocol.HTTPS || p == protocol.TLS:
+		mode := s.GetTls().GetMode()
+		switch mode {
+		case networking.ServerTLSSettings_PASSTHROUGH:
+			return gatewayv1.TLSProtocolType, &gatewayv1.ListenerTLSConfig{Mode: ptr.Of(gatewayv1.TLSModePassthrough)}, true
+		case networking.ServerTLSSettings_SIMPLE:
+		case networking.ServerTLSSettings_MUTUAL:
+			c.report(cfg, field+".tls.mode", "client certificate validation is configured on the whole Gateway in the Gateway API; "+
+				"the server was not migrated")
+			return "", nil, false
+		default:
+			c.report(cfg, field+".tls.mode", "TLS mode %v is not supported; the server was not migrated", mode)
+			return "", nil, false
+		}
+		tls := s.GetTls()
+		if tls.ServerCertificate != "" || len(tls.TlsCertificates) > 0 {
+			c.report(cfg, field+".tls", "certificates must be Secrets; the server was not migrated")
+			return "", nil, false
+		}
+		cns := tls.CredentialNames
+		if len(cns) == 0 {
+			cns = []string{tls.CredentialName}
+		}
+		listenerTLS := &gatewayv1.ListenerTLSConfig{Mode: ptr.Of(gatewayv1.TLSModeTerminate)}
+		for _, cn := range cns {
+			if cn == "" || strings.Contains(cn, "/") {
+				c.report(cfg, field+".tls.credentialName", "credential %q is not a Secret name; the server was not migrated", cn)
+				return "", nil, false
+			}
+			listenerTLS.CertificateRefs = append(listenerTLS.CertificateRefs, gatewayv1.SecretObjectReference{Name: gatewayv1.ObjectName(cn)})
+		}
+		if tls.MinProtocolVersion != networking.ServerTLSSettings_TLS_AUTO || tls.MaxProtocolVersion != networking.ServerTLSSettings_TLS_AUTO ||
+			len(tls.CipherSuites) > 0 {
+			c.report(cfg, field+".tls", "TLS versions and cipher suites are not supported; the server was converted without them")
+		}
+		if p == protocol.HTTPS {
+			return gatewayv1.HTTPSProtocolType, listenerTLS, true
+		}
+		return gatewayv1.TLSProtocolType, listenerTLS, true
+	case p.IsHTTP():
+		return gatewayv1.HTTPProtocolType, nil, true
+	case p == protocol.UDP:
+		return gatewayv1.UDPProtocolType, nil, true
+	case p.IsTCP():
+		return gatewayv1.TCPProtocolType, nil, true
+	}
+	c.report(cfg, field+".port.protocol", "protocol %q is not supported; the server was not migrated", s.Port.Protocol)
+	return "", nil, false
+}
+
+// listenerName returns a name for the listener of a server, derived from the name of its port.
+func listenerName(s *networking.Server, proto gatewayv1.ProtocolType) string {
+	name := strings.Trim(strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		}
+		return '-'
+	}, s.Port.Name), "-")
+	if name == "" {
+		name = strings.ToLower(string(proto)) + "-" + strconv.Itoa(int(s.Port.Number))
+	}
+	return name
+}
+
+// addHTTPSRedirect adds an HTTPRoute redirecting all requests to the listeners of servers with httpsRedirect set.
+func (c *converter) addHTTPSRedirect(cfg config.Config, listeners []gatewayv1.SectionName) {
+	tm, om := objectMeta(gvk.HTTPRoute, cfg.Name+"-https-redirect", cfg.Namespace)
+	route := &gatewayv1.HTTPRoute{TypeMeta: tm, ObjectMeta: om}
+	for _, l := range listeners {
+		route.Spec.ParentRefs = append(route.Spec.ParentRefs, gatewayv1.ParentReference{
+			Name:        gatewayv1.ObjectName(cfg.Name),
+			SectionName: ptr.Of(l),
+		})
+	}
+	route.Spec.Rules = []gatewayv1.HTTPRouteRule{{
+		Filters: []gatewayv1.HTTPRouteFilter{{
+			Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+			RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+				Scheme:     ptr.Of("https"),
+				StatusCode: ptr.Of(redirectStatusCode),
+			},
+		}},
+	}}
+	c.add(route)
+}
+
+// routeParents are the parents of the routes converted from a VirtualService.
+type routeParents struct {
+	gateways []types.NamespacedName
+	mesh     bool
+}
+
+func (c *converter) virtualServiceParents(cfg config.Config) routeParents {
+	vs := cfg.Spec.(*networking.VirtualService)
+	gateways := vs.Gateways
+	if len(gateways) == 0 {
+		gateways = []string{constants.IstioMeshGateway}
+	}
+	var parents routeParents
+	for _, g := range gateways {
+		if g == constants.IstioMeshGateway {
+			parents.mesh = true
+			continue
+		}
+		ns, name, ok := strings.Cut(g, "/")
+		if !ok {
+			ns, name = cfg.Namespace, g
+		}
+		parents.gateways = append(parents.gateways, types.NamespacedName{Namespace: ns, Name: name})
+	}
+	return parents
+}
+
+// gatewayParentRefs returns the references to the listeners of the parent gateways a route of the given kind can
+// attach to. Listeners redirecting to HTTPS are skipped, as Istio does not apply routes to them.
+func (c *converter) gatewayParentRefs(cfg config.Config, parents routeParents, kind config.GroupVersionKind, port uint32) []gatewayv1.ParentReference {
+	var refs []gatewayv1.ParentReference
+	for _, gw := range parents.gateways {
+		ref := gatewayv1.ParentReference{Name: gatewayv1.ObjectName(gw.Name)}
+		if gw.Namespace != cfg.Namespace {
+			ref.Namespace = ptr.Of(gatewayv1.Namespace(gw.Namespace))
+		}
+		listeners, known := c.gateways[gw]
+		if !known {
+			// The Gateway is not part of the inputs, so its listeners are unknown.
+			if port != 0 {
+				ref.Port = ptr.Of(gatewayv1.PortNumber(port))
+			}
+			refs = append(refs, ref)
+			continue
+		}
+		var compatible []listener
+		redirects := false
+		for _, l := range listeners {
+			if !listenerAccepts(l, kind) || (port != 0 && l.port != port) {
+				continue
+			}
+			if l.redirect {
+				redirects = true
+				continue
+			}
+			compatible = append(compatible, l)
+		}
+		if len(compatible) == 0 {
+			c.report(cfg, "spec.gateways", "Gateway %s has no listener for %s; the reference was not migrated", gw, kind.Kind)
+			continue
+		}
+		if !redirects {
+			if port != 0 {
+				ref.Port = ptr.Of(gatewayv1.PortNumber(port))
+			}
+			refs = append(refs, ref)
+			continue
+		}
+		for _, l := range compatible {
+			lref := ref
+			lref.SectionName = ptr.Of(l.name)
+			refs = append(refs, lref)
+		}
+	}
+	return refs
+}
+
+func listenerAccepts(l listener, kind config.GroupVersionKind) bool {
+	switch kind {
+	case gvk.HTTPRoute, gvk.GRPCRoute:
+		return l.protocol == gatewayv1.HTTPProtocolType || l.protocol == gatewayv1.HTTPSProtocolType
+	case gvk.TLSRoute:
+		return l.protocol == gatewayv1.TLSProtocolType
+	case gvk.TCPRoute:
+		return l.protocol == gatewayv1.TCPProtocolType
+	}
+	return false
+}
+
+// meshParentRefs returns the Services routes for mesh traffic attach to, from the hosts of a VirtualService.
+func (c *converter) meshParentRefs(cfg config.Config) []gatewayv1.ParentReference {
+	var refs []gatewayv1.ParentReference
+	for i, h := range cfg.Spec.(*networking.VirtualService).Hosts {
+		svc, ok := c.serviceForHost(h, cfg.Namespace)
+		if !ok {
+			c.report(cfg, fmt.Sprintf("spec.hosts[%d]", i), "host %q is not a Kubernetes Service; mesh routing for the host was not migrated", h)
+			continue
+		}
+		ref := gatewayv1.ParentReference{
+			Group: ptr.Of(gatewayv1.Group("")),
+			Kind:  ptr.Of(gatewayv1.Kind(gvk.Service.Kind)),
+			Name:  gatewayv1.ObjectName(svc.Name),
+		}
+		if svc.Namespace != cfg.Namespace {
+			ref.Namespace = ptr.Of(gatewayv1.Namespace(svc.Namespace))
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// serviceForHost returns the Kubernetes Service of a hostname, which is either a short name or the fully qualified
+// name of the Service.
+func (c *converter) serviceForHost(h, namespace string) (types.NamespacedName, bool) {
+	if !strings.Contains(h, ".") {
+		if h == "*" {
+			return types.NamespacedName{}, false
+		}
+		return types.NamespacedName{Namespace: namespace, Name: h}, true
+	}
+	nameNs, ok := strings.CutSuffix(h, ".svc."+c.opts.DomainSuffix)
+	if !ok {
+		return types.NamespacedName{}, false
+	}
+	name, ns, ok := strings.Cut(nameNs, ".")
+	if !ok || strings.Contains(ns, ".") || strings.Contains(name, "*") {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: ns, Name: name}, true
+}
+
+// fqdn expands short hostnames to the fully qualified hostname of the Service in the namespace.
+func (c *converter) fqdn(h, namespace string) string {
+	if strings.Contains(h, ".") || h == "*" {
+		return h
+	}
+	return h + "." + namespace + ".svc." + c.opts.DomainSuffix
+}
+
+func (c *converter) routeHostnames(cfg config.Config) ([]gatewayv1.Hostname, bool) {
+	var hostnames []gatewayv1.Hostname
+	for i, h := range cfg.Spec.(*networking.VirtualService).Hosts {
+		if h == "*" {
+			// All hosts; routes without hostnames match all hosts of the listeners.
+			return nil, true
+		}
+		h = c.fqdn(h, cfg.Namespace)
+		if (strings.Contains(h, "*") && !strings.HasPrefix(h, "*.")) || isIP(h) {
+			c.report(cfg, fmt.Sprintf("spec.hosts[%d]", i), "host %q is not a valid hostname; the VirtualService was not migrated", h)
+			return nil, false
+		}
+		hostnames = append(hostnames, gatewayv1.Hostname(h))
+	}
+	return hostnames, true
+}
+
+func isIP(h string) bool {
+	return strings.Contains(h, ":") || strings.Trim(h, "0123456789.") == ""
+}
+
+func (c *converter) convertVirtualService(cfg config.Config) {
+	vs := cfg.Spec.(*networking.VirtualService)
+	if len(vs.ExportTo) > 0 {
+		c.report(cfg, "spec.exportTo", "routes are visible to all namespaces; exportTo was dropped")
+	}
+	parents := c.virtualServiceParents(cfg)
+	if len(vs.Http) > 0 {
+		c.convertHTTPRoutes(cfg, parents)
+	}
+	if len(vs.Tcp) > 0 || len(vs.Tls) > 0 {
+		if parents.mesh {
+			c.report(cfg, "spec.gateways", "TCP and TLS routing for mesh traffic is not supported; only gateway routes were migrated")
+		}
+		for i, r := range vs.Tcp {
+			c.convertTCPRoute(cfg, parents, i, r)
+		}
+		for i, r := range vs.Tls {
+			c.convertTLSRoute(cfg, parents, i, r)
+		}
+	}
+}
+
+func (c *converter) convertHTTPRoutes(cfg config.Config, parents routeParents) {
+	vs := cfg.Spec.(*networking.VirtualService)
+	hostnames, ok := c.routeHostnames(cfg)
+	if !ok {
+		return
+	}
+	var rules []gatewayv1.HTTPRouteRule
+	var converted []*networking.HTTPRoute
+	for i, r := range vs.Http {
+		if rule, ok := c.convertHTTPRule(cfg, fmt.Sprintf("spec.http[%d]", i), r); ok {
+			rules = append(rules, rule)
+			converted = append(converted, r)
+		}
+	}
+	if len(rules) == 0 {
+		return
+	}
+	if i, j, reordered := reorderedRules(converted); reordered {
+		c.report(cfg, "spec.http", "rules are ordered by the precedence of their matches in the Gateway API; "+
+			"rule %q may now be evaluated before rule %q", ruleName(converted[j], j), ruleName(converted[i], i))
+	}
+
+	routeKind := gvk.HTTPRoute
+	var grpcRules []gatewayv1.GRPCRouteRule
+	if c.grpcParents(parents) {
+		grpcRules, ok = toGRPCRules(rules)
+		if ok {
+			routeKind = gvk.GRPCRoute
+		}
+	}
+	parentRefs := c.gatewayParentRefs(cfg, parents, routeKind, 0)
+	if parents.mesh {
+		parentRefs = append(parentRefs, c.meshParentRefs(cfg)...)
+	}
+	if len(parentRefs) == 0 {
+		return
+	}
+	if len(hostnames) > 16 {
+		c.report(cfg, "spec.hosts", "routes may have at most 16 hostnames; the converted routes must be split by host")
+	}
+
+	// Routes have a limited number of rules, so large VirtualServices are split into multiple routes.
+	for chunk := 0; chunk*maxRules < len(rules); chunk++ {
+		name := cfg.Name
+		if chunk > 0 {
+			name += "-" + strconv.Itoa(chunk)
+		}
+		end := min((chunk+1)*maxRules, len(rules))
+		tm, om := objectMeta(routeKind, name, cfg.Namespace)
+		if routeKind == gvk.GRPCRoute {
+			c.add(&gatewayv1.GRPCRoute{
+				TypeMeta:   tm,
+				ObjectMeta: om,
+				Spec: gatewayv1.GRPCRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+					Hostnames:       hostnames,
+					Rules:           grpcRules[chunk*maxRules : end],
+				},
+			})
+			continue
+		}
+		c.add(&gatewayv1.HTTPRoute{
+			TypeMeta:   tm,
+			ObjectMeta: om,
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+				Hostnames:       hostnames,
+				Rules:           rules[chunk*maxRules : end],
+			},
+		})
+	}
+}
+
+// grpcParents returns true if a route only attaches to listeners of gateway servers for gRPC.
+func (c *converter) grpcParents(parents routeParents) bool {
+	if parents.mesh || len(parents.gateways) == 0 {
+		return false
+	}
+	for _, gw := range parents.gateways {
+		listeners, known := c.gateways[gw]
+		if !known {
+			return false
+		}
+		for _, l := range listeners {
+			if listenerAccepts(l, gvk.HTTPRoute) && !l.grpc {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func ruleName(r *networking.HTTPRoute, i int) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return strconv.Itoa(i)
+}
+
+// reorderedRules checks if the Gateway API would order a rule before a previous rule of a VirtualService, following
+// the precedence of the matches of the rules, and returns the indexes of the first such rules.
+func reorderedRules(rules []*networking.HTTPRoute) (int, int, bool) {
+	for i := range rules {
+		for j := i + 1; j < len(rules); j++ {
+			for _, mi := range matchesOrCatchAll(rules[i]) {
+				for _, mj := range matchesOrCatchAll(rules[j]) {
+					if matchPrecedes(mj, mi) {
+						return i, j, true
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+func matchesOrCatchAll(r *networking.HTTPRoute) []*networking.HTTPMatchRequest {
+	if len(r.Match) == 0 {
+		return []*networking.HTTPMatchRequest{nil}
+	}
+	return r.Match
+}
+
+// matchPrecedes returns true if the Gateway API gives a match precedence over another. Rules without matches
+// match all requests, and come last.
+func matchPrecedes(m1, m2 *networking.HTTPMatchRequest) bool {
+	if m1 == nil {
+		return false
+	}
+	if m2 == nil {
+		return true
+	}
+	r1, r2 := uriRank(m1.Uri), uriRank(m2.Uri)
+	l1, l2 := uriLength(m1.Uri), uriLength(m2.Uri)
+	switch {
+	case r1 != r2:
+		return r1 > r2
+	case l1 != l2:
+		return l1 > l2
+	case (m1.Method == nil) != (m2.Method == nil):
+		return m1.Method != nil
+	case len(m1.Headers) != len(m2.Headers):
+		return len(m1.Headers) > len(m2.Headers)
+	default:
+		return len(m1.QueryParams) > len(m2.QueryParams)
+	}
+}
+
+func uriRank(m *networking.StringMatch) int {
+	switch m.GetMatchType().(type) {
+	case *networking.StringMatch_Exact:
+		return 3
+	case *networking.StringMatch_Prefix:
+		return 2
+	case *networking.StringMatch_Regex:
+		return 1
+	}
+	return -1
+}
+
+func uriLength(m *networking.StringMatch) int {
+	return len(m.GetExact()) + len(m.GetPrefix()) + len(m.GetRegex())
+}
+
+// convertHTTPRule converts an HTTP rule of a VirtualService. Rules with matches or destinations that cannot be
+// expressed are not converted, as dropping them would change which requests the rule applies to or where they go.
+func (c *converter) convertHTTPRule(cfg config.Config, field string, r *networking.HTTPRoute) (gatewayv1.HTTPRouteRule, bool) {
+	rule := gatewayv1.HTTPRouteRule{}
+	if sectionNameRegex.MatchString(r.Name) {
+		rule.Name = ptr.Of(gatewayv1.SectionName(r.Name))
+	}
+	switch {
+	case r.Delegate != nil:
+		c.report(cfg, field+".delegate", "delegation is not supported; the rule was not migrated")
+		return rule, false
+	case r.DirectResponse != nil:
+		c.report(cfg, field+".directResponse", "direct responses are not supported; the rule was not migrated")
+		return rule, false
+	}
+	for i, m := range r.Match {
+		match, ok := c.convertHTTPMatch(cfg, fmt.Sprintf("%s.match[%d]", field, i), m)
+		if !ok {
+			return rule, false
+		}
+		rule.Matches = append(rule.Matches, match)
+	}
+
+	if r.Redirect != nil {
+		redirect, ok := c.convertRedirect(cfg, field+".redirect", r.Redirect)
+		if !ok {
+			return rule, false
+		}
+		rule.Filters = append(rule.Filters, gatewayv1.HTTPRouteFilter{Type: gatewayv1.HTTPRouteFilterRequestRedirect, RequestRedirect: redirect})
+	}
+	if r.Rewrite != nil {
+		rewrite, ok := c.convertRewrite(cfg, field+".rewrite", r)
+		if !ok {
+			return rule, false
+		}
+		rule.Filters = append(rule.Filters, gatewayv1.HTTPRouteFilter{Type: gatewayv1.HTTPRouteFilterURLRewrite, URLRewrite: rewrite})
+	}
+	rule.Filters = append(rule.Filters, convertHeaders(r.Headers)...)
+	for _, m := range c.convertMirrors(cfg, field, r) {
+		rule.Filters = append(rule.Filters, gatewayv1.HTTPRouteFilter{Type: gatewayv1.HTTPRouteFilterRequestMirror, RequestMirror: m})
+	}
+	if r.CorsPolicy != nil {
+		rule.Filters = append(rule.Filters, gatewayv1.HTTPRouteFilter{
+			Type: gatewayv1.HTTPRouteFilterCORS,
+			CORS: c.convertCors(cfg, field+".corsPolicy", r.CorsPolicy),
+		})
+	}
+	if r.Fault != nil {
+		c.report(cfg, field+".fault", "fault injection is not supported; the rule was converted without it")
+	}
+
+	if r.Timeout != nil {
+		if d, ok := c.convertDuration(cfg, field+".timeout", r.Timeout); ok {
+			rule.Timeouts = &gatewayv1.HTTPRouteTimeouts{Request: &d}
+		}
+	}
+	if r.Retries != nil {
+		c.convertRetries(cfg, field+".retries", r.Retries, &rule)
+	}
+
+	for i, d := range r.Route {
+		destField := fmt.Sprintf("%s.route[%d]", field, i)
+		ref, ok := c.backendRef(cfg, destField+".destination", d.Destination)
+		if !ok {
+			return rule, false
+		}
+		backend := gatewayv1.HTTPBackendRef{BackendRef: gatewayv1.BackendRef{BackendObjectReference: ref}}
+		if len(r.Route) > 1 {
+			backend.Weight = ptr.Of(d.Weight)
+		}
+		backend.Filters = convertHeaders(d.Headers)
+		rule.BackendRefs = append(rule.BackendRefs, backend)
+	}
+	return rule, true
+}
+
+func (c *converter) convertHTTPMatch(cfg config.Config, field string, m *networking.HTTPMatchRequest) (gatewayv1.HTTPRouteMatch, bool) {
+	match := gatewayv1.HTTPRouteMatch{}
+	unsupported := func(name string) (gatewayv1.HTTPRouteMatch, bool) {
+		c.report(cfg, field+"."+name, "matching on %s is not supported; the rule was not migrated", name)
+		return match, false
+	}
+	switch {
+	case m.Scheme != nil:
+		return unsupported("scheme")
+	case m.Authority != nil:
+		return unsupported("authority")
+	case m.Port != 0:
+		return unsupported("port")
+	case len(m.SourceLabels) > 0:
+		return unsupported("sourceLabels")
+	case len(m.Gateways) > 0:
+		return unsupported("gateways")
+	case m.IgnoreUriCase:
+		return unsupported("ignoreUriCase")
+	case len(m.WithoutHeaders) > 0:
+		return unsupported("withoutHeaders")
+	case m.SourceNamespace != "":
+		return unsupported("sourceNamespace")
+	}
+
+	if m.Uri != nil {
+		pm := &gatewayv1.HTTPPathMatch{}
+		switch u := m.Uri.MatchType.(type) {
+		case *networking.StringMatch_Exact:
+			pm.Type, pm.Value = ptr.Of(gatewayv1.PathMatchExact), ptr.Of(u.Exact)
+		case *networking.StringMatch_Prefix:
+			if u.Prefix != "/" && !strings.HasSuffix(u.Prefix, "/") {
+				c.report(cfg, field+".uri", "prefix %q now only matches whole path segments", u.Prefix)
+			}
+			pm.Type, pm.Value = ptr.Of(gatewayv1.PathMatchPathPrefix), ptr.Of(u.Prefix)
+		case *networking.StringMatch_Regex:
+			pm.Type, pm.Value = ptr.Of(gatewayv1.PathMatchRegularExpression), ptr.Of(u.Regex)
+		}
+		if !strings.HasPrefix(*pm.Value, "/") && *pm.Type != gatewayv1.PathMatchRegularExpression {
+			c.report(cfg, field+".uri", "path %q must start with '/'; the rule was not migrated", *pm.Value)
+			return match, false
+		}
+		match.Path = pm
+	}
+	if m.Method != nil {
+		method := m.Method.GetExact()
+		if method == "" || !validMethods.Contains(method) {
+			c.report(cfg, field+".method", "only exact matches of standard methods are supported; the rule was not migrated")
+			return match, false
+		}
+		match.Method = ptr.Of(gatewayv1.HTTPMethod(method))
+	}
+	for _, name := range slices.Sort(maps.Keys(m.Headers)) {
+		if strings.HasPrefix(name, ":") {
+			c.report(cfg, field+".headers", "matching on pseudo-header %q is not supported; the rule was not migrated", name)
+			return match, false
+		}
+		tp, value := convertStringMatch(m.Headers[name])
+		match.Headers = append(match.Headers, gatewayv1.HTTPHeaderMatch{
+			Type:  ptr.Of(gatewayv1.HeaderMatchType(tp)),
+			Name:  gatewayv1.HTTPHeaderName(name),
+			Value: value,
+		})
+	}
+	for _, name := range slices.Sort(maps.Keys(m.QueryParams)) {
+		tp, value := convertStringMatch(m.QueryParams[name])
+		match.QueryParams = append(match.QueryParams, gatewayv1.HTTPQueryParamMatch{
+			Type:  ptr.Of(gatewayv1.QueryParamMatchType(tp)),
+			Name:  gatewayv1.HTTPHeaderName(name),
+			Value: value,
+		})
+	}
+	return match, true
+}
+
+var validMethods = sets.New("GET", "HEAD", "POST", "PUT", "DELETE", "CONNECT", "OPTIONS", "TRACE", "PATCH")
+
+// convertStringMatch converts a string match to an exact or regular expression match. An empty match, which only
+// requires a value to be present, matches any value.
+func convertStringMatch(m *networking.StringMatch) (string, string) {
+	switch v := m.GetMatchType().(type) {
+	case *networking.StringMatch_Exact:
+		return "Exact", v.Exact
+	case *networking.StringMatch_Prefix:
+		return "RegularExpression", regexp.QuoteMeta(v.Prefix) + ".*"
+	case *networking.StringMatch_Regex:
+		return "RegularExpression", v.Regex
+	}
+	return "RegularExpression", ".*"
+}
+
+func (c *converter) convertRedirect(cfg config.Config, field string, r *networking.HTTPRedirect) (*gatewayv1.HTTPRequestRedirectFilter, bool) {
+	redirect := &gatewayv1.HTTPRequestRedirectFilter{StatusCode: ptr.Of(redirectStatusCode)}
+	if r.RedirectCode != 0 {
+		redirect.StatusCode = ptr.Of(int(r.RedirectCode))
+	}
+	if r.Scheme != "" {
+		redirect.Scheme = ptr.Of(r.Scheme)
+	}
+	if r.Authority != "" {
+		redirect.Hostname = ptr.Of(gatewayv1.PreciseHostname(r.Authority))
+	}
+	switch p := r.RedirectPort.(type) {
+	case *networking.HTTPRedirect_Port:
+		redirect.Port = ptr.Of(gatewayv1.PortNumber(p.Port))
+	case *networking.HTTPRedirect_DerivePort:
+		// Without a port, the port is derived from the scheme if set, and kept otherwise.
+		if p.DerivePort == networking.HTTPRedirect_FROM_PROTOCOL_DEFAULT && r.Scheme == "" {
+			c.report(cfg, field+".derivePort", "deriving the port from the protocol requires a scheme; the rule was not migrated")
+			return nil, false
+		}
+	}
+	switch {
+	case r.Uri != "":
+		redirect.Path = &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.Of(r.Uri)}
+	case r.PrefixRewrite != "":
+		redirect.Path = &gatewayv1.HTTPPathModifier{Type: gatewayv1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: ptr.Of(r.PrefixRewrite)}
+	}
+	return redirect, true
+}
+
+// convertRewrite converts a rewrite. The path of requests matched by prefix is rewritten by replacing the prefix,
+// as in Istio, and the full path of requests matched exactly is replaced.
+func (c *converter) convertRewrite(cfg config.Config, field string, r *networking.HTTPRoute) (*gatewayv1.HTTPURLRewriteFilter, bool) {
+	rewrite := &gatewayv1.HTTPURLRewriteFilter{}
+	if r.Rewrite.UriRegexRewrite != nil {
+		c.report(cfg, field+".uriRegexRewrite", "regular expression rewrites are not supported; the rule was not migrated")
+		return nil, false
+	}
+	if r.Rewrite.Authority != "" {
+		rewrite.Hostname = ptr.Of(gatewayv1.PreciseHostname(r.Rewrite.Authority))
+	}
+	if r.Rewrite.Uri != "" {
+		prefix, exact := true, true
+		for _, m := range r.Match {
+			switch m.GetUri().GetMatchType().(type) {
+			case *networking.StringMatch_Exact:
+				prefix = false
+			case *networking.StringMatch_Regex:
+				prefix, exact = false, false
+			default:
+				exact = false
+			}
+		}
+		switch {
+		case prefix:
+			rewrite.Path = &gatewayv1.HTTPPathModifier{Type: gatewayv1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: ptr.Of(r.Rewrite.Uri)}
+		case exact:
+			rewrite.Path = &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.Of(r.Rewrite.Uri)}
+		default:
+			c.report(cfg, field+".uri", "rewrites are only supported for rules matching paths by prefix or exactly; the rule was not migrated")
+			return nil, false
+		}
+	}
+	return rewrite, true
+}
+
+func convertHeaders(h *networking.Headers) []gatewayv1.HTTPRouteFilter {
+	var filters []gatewayv1.HTTPRouteFilter
+	if f := convertHeaderOperations(h.GetRequest()); f != nil {
+		filters = append(filters, gatewayv1.HTTPRouteFilter{Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier, RequestHeaderModifier: f})
+	}
+	if f := convertHeaderOperations(h.GetResponse()); f != nil {
+		filters = append(filters, gatewayv1.HTTPRouteFilter{Type: gatewayv1.HTTPRouteFilterResponseHeaderModifier, ResponseHeaderModifier: f})
+	}
+	return filters
+}
+
+func convertHeaderOperations(o *networking.Headers_HeaderOperations) *gatewayv1.HTTPHeaderFilter {
+	if o == nil {
+		return nil
+	}
+	toHeaders := func(m map[string]string) []gatewayv1.HTTPHeader {
+		var out []gatewayv1.HTTPHeader
+		for _, k := range slices.Sort(maps.Keys(m)) {
+			out = append(out, gatewayv1.HTTPHeader{Name: gatewayv1.HTTPHeaderName(k), Value: m[k]})
+		}
+		return out
+	}
+	f := &gatewayv1.HTTPHeaderFilter{Set: toHeaders(o.Set), Add: toHeaders(o.Add), Remove: o.Remove}
+	if len(f.Set) == 0 && len(f.Add) == 0 && len(f.Remove) == 0 {
+		return nil
+	}
+	return f
+}
+
+func (c *converter) convertMirrors(cfg config.Config, field string, r *networking.HTTPRoute) []*gatewayv1.HTTPRequestMirrorFilter {
+	var mirrors []*gatewayv1.HTTPRequestMirrorFilter
+	add := func(field string, d *networking.Destination, percentage *networking.Percent) {
+		ref, ok := c.backendRef(cfg, field+".destination", d)
+		if !ok {
+			c.report(cfg, field, "the mirror was not migrated")
+			return
+		}
+		m := &gatewayv1.HTTPRequestMirrorFilter{BackendRef: ref}
+		if percentage != nil {
+			if v := percentage.Value; v == math.Trunc(v) {
+				m.Percent = ptr.Of(int32(v))
+			} else {
+				m.Fraction = &gatewayv1.Fraction{Numerator: int32(math.Round(v * 1000)), Denominator: ptr.Of(int32(100000))}
+			}
+		}
+		mirrors = append(mirrors, m)
+	}
+	if r.Mirror != nil {
+		percentage := r.MirrorPercentage
+		if percentage == nil && r.MirrorPercent != nil {
+			percentage = &networking.Percent{Value: float64(r.MirrorPercent.GetValue())}
+		}
+		add(field+".mirror", r.Mirror, percentage)
+	}
+	for i, m := range r.Mirrors {
+		add(fmt.Sprintf("%s.mirrors[%d]", field, i), m.Destination, m.Percentage)
+	}
+	return mirrors
+}
+
+func (c *converter) convertCors(cfg config.Config, field string, p *networking.CorsPolicy) *gatewayv1.HTTPCORSFilter {
+	cors := &gatewayv1.HTTPCORSFilter{}
+	for _, o := range p.AllowOrigin {
+		cors.AllowOrigins = append(cors.AllowOrigins, gatewayv1.CORSOrigin(o))
+	}
+	for _, o := range p.AllowOrigins {
+		switch v := o.MatchType.(type) {
+		case *networking.StringMatch_Exact:
+			cors.AllowOrigins = append(cors.AllowOrigins, gatewayv1.CORSOrigin(v.Exact))
+		case *networking.StringMatch_Prefix:
+			cors.AllowOrigins = append(cors.AllowOrigins, gatewayv1.CORSOrigin(v.Prefix+"*"))
+		default:
+			c.report(cfg, field+".allowOrigins", "origins can only be matched exactly or by prefix; origin regex %q was dropped", o.GetRegex())
+		}
+	}
+	for _, m := range p.AllowMethods {
+		cors.AllowMethods = append(cors.AllowMethods, gatewayv1.HTTPMethodWithWildcard(m))
+	}
+	for _, h := range p.AllowHeaders {
+		cors.AllowHeaders = append(cors.AllowHeaders, gatewayv1.HTTPHeaderName(h))
+	}
+	for _, h := range p.ExposeHeaders {
+		cors.ExposeHeaders = append(cors.ExposeHeaders, gatewayv1.HTTPHeaderName(h))
+	}
+	if p.MaxAge != nil {
+		cors.MaxAge = int32(p.MaxAge.AsDuration() / time.Second)
+	}
+	if p.AllowCredentials.GetValue() {
+		cors.AllowCredentials = ptr.Of(true)
+	}
+	return cors
+}
+
+func (c *converter) convertRetries(cfg config.Config, field string, r *networking.HTTPRetry, rule *gatewayv1.HTTPRouteRule) {
+	retry := &gatewayv1.HTTPRouteRetry{Attempts: ptr.Of(int(r.Attempts))}
+	var dropped []string
+	for _, on := range strings.Split(r.RetryOn, ",") {
+		on = strings.TrimSpace(on)
+		if on == "" || gatewayRetryOn.Contains(on) {
+			continue
+		}
+		if code, err := strconv.Atoi(on); err == nil && code >= 400 && code < 600 {
+			retry.Codes = append(retry.Codes, gatewayv1.HTTPRouteRetryStatusCode(code))
+			continue
+		}
+		dropped = append(dropped, on)
+	}
+	if len(dropped) > 0 {
+		c.report(cfg, field+".retryOn", "only connection failures and status codes can be retried; conditions %v were dropped", dropped)
+	}
+	if r.Backoff != nil {
+		if d, ok := c.convertDuration(cfg, field+".backoff", r.Backoff); ok {
+			retry.Backoff = &d
+		}
+	}
+	if r.PerTryTimeout != nil {
+		if d, ok := c.convertDuration(cfg, field+".perTryTimeout", r.PerTryTimeout); ok {
+			if rule.Timeouts == nil {
+				rule.Timeouts = &gatewayv1.HTTPRouteTimeouts{}
+			}
+			rule.Timeouts.BackendRequest = &d
+		}
+	}
+	if r.RetryRemoteLocalities != nil || r.RetryIgnorePreviousHosts != nil {
+		c.report(cfg, field, "retry host selection is not supported; the retries were converted without it")
+	}
+	rule.Retry = retry
+}
+
+// convertDuration converts a duration to the Gateway API format, which is limited to milliseconds.
+func (c *converter) convertDuration(cfg config.Config, field string, d *durationpb.Duration) (gatewayv1.Duration, bool) {
+	dur := d.AsDuration()
+	if dur%time.Millisecond != 0 || dur < 0 || dur >= 100000*time.Hour {
+		c.report(cfg, field, "duration %v cannot be expressed in milliseconds; it was dropped", dur)
+		return "", false
+	}
+	if dur == 0 {
+		return "0s", true
+	}
+	var b strings.Builder
+	for _, unit := range []struct {
+		d    time.Duration
+		name string
+	}{{time.Hour, "h"}, {time.Minute, "m"}, {time.Second, "s"}, {time.Millisecond, "ms"}} {
+		if n := dur / unit.d; n > 0 {
+			b.WriteString(strconv.FormatInt(int64(n), 10) + unit.name)
+			dur -= n * unit.d
+		}
+	}
+	return gatewayv1.Duration(b.String()), true
+}
+
+// backendRef converts a destination to a reference to a Kubernetes Service, or to the hostname of a ServiceEntry.
+func (c *converter) backendRef(cfg config.Config, field string, d *networking.Destination) (gatewayv1.BackendObjectReference, bool) {
+	ref := gatewayv1.BackendObjectReference{}
+	if d.Subset != "" {
+		c.report(cfg, field+".subset", "subsets are not supported, a Service must be created for each subset; the rule was not migrated")
+		return ref, false
+	}
+	if d.Port != nil {
+		ref.Port = ptr.Of(gatewayv1.PortNumber(d.Port.Number))
+	}
+	if svc, ok := c.serviceForHost(d.Host, cfg.Namespace); ok {
+		ref.Name = gatewayv1.ObjectName(svc.Name)
+		if svc.Namespace != cfg.Namespace {
+			ref.Namespace = ptr.Of(gatewayv1.Namespace(svc.Namespace))
+			c.grantAccess(svc.Namespace, cfg.Namespace)
+		}
+		if ref.Port == nil {
+			// Service references require a port, which Istio allows to omit for Services with a single port.
+			s := c.services[svc]
+			if s == nil || len(s.Spec.Ports) != 1 {
+				c.report(cfg, field+".port", "a port is required for Service %s; the rule was not migrated", svc)
+				return ref, false
+			}
+			ref.Port = ptr.Of(gatewayv1.PortNumber(s.Spec.Ports[0].Port))
+		}
+		return ref, true
+	}
+	if _, ok := c.serviceEntries[d.Host]; !ok || strings.Contains(d.Host, "*") {
+		c.report(cfg, field+".host", "host %q is neither a Kubernetes Service nor a ServiceEntry host; the rule was not migrated", d.Host)
+		return ref, false
+	}
+	ref.Group = ptr.Of(gatewayv1.Group(gvk.ServiceEntry.Group))
+	ref.Kind = ptr.Of(gatewayv1.Kind("Hostname"))
+	ref.Name = gatewayv1.ObjectName(d.Host)
+	return ref, true
+}
+
+// grantAccess records that routes in a namespace reference Services in another namespace, which requires a
+// ReferenceGrant in the namespace of the Services.
+func (c *converter) grantAccess(to, from string) {
+	if c.grants[to] == nil {
+		c.grants[to] = sets.New[string]()
+	}
+	c.grants[to].Insert(from)
+}
+
+func (c *converter) buildReferenceGrants() {
+	for _, to := range slices.Sort(maps.Keys(c.grants)) {
+		for _, from := range sets.SortedList(c.grants[to]) {
+			tm, om := objectMeta(gvk.ReferenceGrant, "allow-routes-from-"+from, to)
+			grant := &gatewayv1.ReferenceGrant{TypeMeta: tm, ObjectMeta: om}
+			for _, k := range []config.GroupVersionKind{gvk.HTTPRoute, gvk.GRPCRoute, gvk.TLSRoute, gvk.TCPRoute} {
+				grant.Spec.From = append(grant.Spec.From, gatewayv1.ReferenceGrantFrom{
+					Group:     gatewayv1.Group(k.Group),
+					Kind:      gatewayv1.Kind(k.Kind),
+					Namespace: gatewayv1.Namespace(from),
+				})
+			}
+			grant.Spec.To = []gatewayv1.ReferenceGrantTo{{Group: "", Kind: gatewayv1.Kind(gvk.Service.Kind)}}
+			c.add(grant)
+		}
+	}
+}
+
+// toGRPCRules converts HTTP rules to gRPC rules, if they only use features supported by GRPCRoutes.
+func toGRPCRules(rules []gatewayv1.HTTPRouteRule) ([]gatewayv1.GRPCRouteRule, bool) {
+	var out []gatewayv1.GRPCRouteRule
+	for _, r := range rules {
+		if r.Timeouts != nil || r.Retry != nil {
+			return nil, false
+		}
+		rule := gatewayv1.GRPCRouteRule{Name: r.Name}
+		for _, m := range r.Matches {
+			match, ok := toGRPCMatch(m)
+			if !ok {
+				return nil, false
+			}
+			rule.Matches = append(rule.Matches, match)
+		}
+		filters, ok := toGRPCFilters(r.Filters)
+		if !ok {
+			return nil, false
+		}
+		rule.Filters = filters
+		for _, b := range r.BackendRefs {
+			filters, ok := toGRPCFilters(b.Filters)
+			if !ok {
+				return nil, false
+			}
+			rule.BackendRefs = append(rule.BackendRefs, gatewayv1.GRPCBackendRef{BackendRef: b.BackendRef, Filters: filters})
+		}
+		out = append(out, rule)
+	}
+	return out, true
+}
+
+// toGRPCMatch converts an HTTP match to a gRPC match. Exact paths match a method of a service, and prefixes ending
+// with a '/' match all methods of a service.
+func toGRPCMatch(m gatewayv1.HTTPRouteMatch) (gatewayv1.GRPCRouteMatch, bool) {
+	match := gatewayv1.GRPCRouteMatch{}
+	if m.Method != nil || len(m.QueryParams) > 0 {
+		return match, false
+	}
+	if m.Path != nil {
+		service, method, ok := strings.Cut(strings.TrimPrefix(*m.Path.Value, "/"), "/")
+		switch {
+		case !ok || service == "" || strings.Contains(method, "/"):
+			return match, false
+		case *m.Path.Type == gatewayv1.PathMatchExact && method != "":
+			match.Method = &gatewayv1.GRPCMethodMatch{Type: ptr.Of(gatewayv1.GRPCMethodMatchExact), Service: &service, Method: &method}
+		case *m.Path.Type == gatewayv1.PathMatchPathPrefix && method == "":
+			match.Method = &gatewayv1.GRPCMethodMatch{Type: ptr.Of(gatewayv1.GRPCMethodMatchExact), Service: &service}
+		default:
+			return match, false
+		}
+	}
+	for _, h := range m.Headers {
+		match.Headers = append(match.Headers, gatewayv1.GRPCHeaderMatch{
+			Type:  ptr.Of(gatewayv1.GRPCHeaderMatchType(*h.Type)),
+			Name:  gatewayv1.GRPCHeaderName(h.Name),
+			Value: h.Value,
+		})
+	}
+	return match, true
+}
+
+func toGRPCFilters(filters []gatewayv1.HTTPRouteFilter) ([]gatewayv1.GRPCRouteFilter, bool) {
+	var out []gatewayv1.GRPCRouteFilter
+	for _, f := range filters {
+		switch f.Type {
+		case gatewayv1.HTTPRouteFilterRequestHeaderModifier, gatewayv1.HTTPRouteFilterResponseHeaderModifier, gatewayv1.HTTPRouteFilterRequestMirror:
+			out = append(out, gatewayv1.GRPCRouteFilter{
+				Type:                   gatewayv1.GRPCRouteFilterType(f.Type),
+				RequestHeaderModifier:  f.RequestHeaderModifier,
+				ResponseHeaderModifier: f.ResponseHeaderModifier,
+				RequestMirror:          f.RequestMirror,
+			})
+		default:
+			return nil, false
+		}
+	}
+	return out, true
+}
+
+// convertTCPRoute converts a TCP rule of a VirtualService to a TCPRoute. TCPRoutes have a single rule, so each rule
+// is converted to its own route.
+func (c *converter) convertTCPRoute(cfg config.Config, parents routeParents, i int, r *networking.TCPRoute) {
+	field := fmt.Sprintf("spec.tcp[%d]", i)
+	ports, ok := c.convertL4Match(cfg, field, slices.Map(r.Match, func(m *networking.L4MatchAttributes) l4Match {
+		return l4Match{port: m.Port, unsupported: len(m.DestinationSubnets) > 0 || m.SourceSubnet != "" ||
+			len(m.SourceLabels) > 0 || len(m.Gateways) > 0 || m.SourceNamespace != ""}
+	}))
+	if !ok {
+		return
+	}
+	backends, ok := c.routeBackends(cfg, field, r.Route)
+	if !ok {
+		return
+	}
+	parentRefs := c.portParentRefs(cfg, parents, gvk.TCPRoute, ports)
+	if len(parentRefs) == 0 {
+		return
+	}
+	tm, om := objectMeta(gvk.TCPRoute, l4RouteName(cfg, i, len(cfg.Spec.(*networking.VirtualService).Tcp)), cfg.Namespace)
+	c.add(&gatewayv1.TCPRoute{
+		TypeMeta:   tm,
+		ObjectMeta: om,
+		Spec: gatewayv1.TCPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+			Rules:           []gatewayv1.TCPRouteRule{{BackendRefs: backends}},
+		},
+	})
+}
+
+// convertTLSRoute converts a TLS rule of a VirtualService to a TLSRoute, which has a single rule.
+func (c *converter) convertTLSRoute(cfg config.Config, parents routeParents, i int, r *networking.TLSRoute) {
+	field := fmt.Sprintf("spec.tls[%d]", i)
+	ports, ok := c.convertL4Match(cfg, field, slices.Map(r.Match, func(m *networking.TLSMatchAttributes) l4Match {
+		return l4Match{port: m.Port, unsupported: len(m.DestinationSubnets) > 0 || len(m.SourceLabels) > 0 ||
+			len(m.Gateways) > 0 || m.SourceNamespace != ""}
+	}))
+	if !ok {
+		return
+	}
+	hostnames := sets.New[string]()
+	for _, m := range r.Match {
+		for _, h := range m.SniHosts {
+			hostnames.Insert(c.fqdn(h, cfg.Namespace))
+		}
+	}
+	if hostnames.Contains("*") || hostnames.IsEmpty() {
+		c.report(cfg, field+".match", "TLS routes must match specific SNI hosts; the rule was not migrated")
+		return
+	}
+	backends, ok := c.routeBackends(cfg, field, r.Route)
+	if !ok {
+		return
+	}
+	parentRefs := c.portParentRefs(cfg, parents, gvk.TLSRoute, ports)
+	if len(parentRefs) == 0 {
+		return
+	}
+	tm, om := objectMeta(gvk.TLSRoute, l4RouteName(cfg, i, len(cfg.Spec.(*networking.VirtualService).Tls)), cfg.Namespace)
+	c.add(&gatewayv1.TLSRoute{
+		TypeMeta:   tm,
+		ObjectMeta: om,
+		Spec: gatewayv1.TLSRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+			Hostnames:       slices.Map(sets.SortedList(hostnames), func(h string) gatewayv1.Hostname { return gatewayv1.Hostname(h) }),
+			Rules:           []gatewayv1.TLSRouteRule{{BackendRefs: backends}},
+		},
+	})
+}
+
+func l4RouteName(cfg config.Config, i, rules int) string {
+	if rules == 1 {
+		return cfg.Name
+	}
+	return cfg.Name + "-" + strconv.Itoa(i)
+}
+
+type l4Match struct {
+	port        uint32
+	unsupported bool
+}
+
+// convertL4Match returns the ports matched by a TCP or TLS rule. A rule without matches, or with a match without
+// port, matches all ports.
+func (c *converter) convertL4Match(cfg config.Config, field string, matches []l4Match) ([]uint32, bool) {
+	ports := sets.New[uint32]()
+	for i, m := range matches {
+		if m.unsupported {
+			c.report(cfg, fmt.Sprintf("%s.match[%d]", field, i), "only ports and SNI hosts can be matched; the rule was not migrated")
+			return nil, false
+		}
+		if m.port == 0 {
+			return nil, true
+		}
+		ports.Insert(m.port)
+	}
+	return sets.SortedList(ports), true
+}
+
+func (c *converter) portParentRefs(cfg config.Config, parents routeParents, kind config.GroupVersionKind, ports []uint32) []gatewayv1.ParentReference {
+	if len(ports) == 0 {
+		return c.gatewayParentRefs(cfg, parents, kind, 0)
+	}
+	var refs []gatewayv1.ParentReference
+	for _, p := range ports {
+		refs = append(refs, c.gatewayParentRefs(cfg, parents, kind, p)...)
+	}
+	return refs
+}
+
+func (c *converter) routeBackends(cfg config.Config, field string, route []*networking.RouteDestination) ([]gatewayv1.BackendRef, bool) {
+	var backends []gatewayv1.BackendRef
+	for i, d := range route {
+		ref, ok := c.backendRef(cfg, fmt.Sprintf("%s.route[%d].destination", field, i), d.Destination)
+		if !ok {
+			return nil, false
+		}
+		backend := gatewayv1.BackendRef{BackendObjectReference: ref}
+		if len(route) > 1 {
+			backend.Weight = ptr.Of(d.Weight)
+		}
+		backends = append(backends, backend)
+	}
+	return backends, true
+}
+
+// convertDestinationRule converts the TLS settings of a DestinationRule to a BackendTLSPolicy. Other settings
+// have no Gateway API equivalent, and are reported.
+func (c *converter) convertDestinationRule(cfg config.Config) {
+	dr := cfg.Spec.(*networking.DestinationRule)
+	tp := dr.TrafficPolicy
+	unsupported := map[string]bool{
+		"spec.trafficPolicy.loadBalancer":      tp.GetLoadBalancer() != nil,
+		"spec.trafficPolicy.connectionPool":    tp.GetConnectionPool() != nil,
+		"spec.trafficPolicy.outlierDetection":  tp.GetOutlierDetection() != nil,
+		"spec.trafficPolicy.portLevelSettings": len(tp.GetPortLevelSettings()) > 0,
+		"spec.trafficPolicy.tunnel":            tp.GetTunnel() != nil,
+		"spec.trafficPolicy.proxyProtocol":     tp.GetProxyProtocol() != nil,
+		"spec.trafficPolicy.retryBudget":       tp.GetRetryBudget() != nil,
+		"spec.subsets":                         len(dr.Subsets) > 0,
+		"spec.workloadSelector":                dr.WorkloadSelector != nil,
+		"spec.exportTo":                        len(dr.ExportTo) > 0,
+	}
+	for _, field := range slices.Sort(maps.Keys(unsupported)) {
+		if unsupported[field] {
+			c.report(cfg, field, "there is no Gateway API equivalent; the DestinationRule must be kept for this setting")
+		}
+	}
+
+	tls := tp.GetTls()
+	if tls == nil {
+		return
+	}
+	field := "spec.trafficPolicy.tls"
+	switch tls.Mode {
+	case networking.ClientTLSSettings_SIMPLE:
+	case networking.ClientTLSSettings_MUTUAL:
+		c.report(cfg, field+".mode", "client certificates are configured on the Gateway with spec.tls.backend.clientCertificateRef; "+
+			"the TLS settings were not migrated")
+		return
+	default:
+		// Mesh mTLS and plaintext need no policy.
+		return
+	}
+	if tls.InsecureSkipVerify.GetValue() {
+		c.report(cfg, field+".insecureSkipVerify", "certificate verification cannot be disabled; the TLS settings were not migrated")
+		return
+	}
+	if tls.CaCertificates != "" {
+		c.report(cfg, field+".caCertificates", "CA certificates must be in a ConfigMap; the TLS settings were not migrated")
+		return
+	}
+
+	policy := &gatewayv1.BackendTLSPolicy{}
+	policy.TypeMeta, policy.ObjectMeta = objectMeta(gvk.BackendTLSPolicy, cfg.Name, cfg.Namespace)
+	host := c.fqdn(dr.Host, cfg.Namespace)
+	target := gatewayv1.LocalPolicyTargetReferenceWithSectionName{}
+	if svc, ok := c.serviceForHost(dr.Host, cfg.Namespace); ok && svc.Namespace == cfg.Namespace {
+		target.Group, target.Kind, target.Name = "", gatewayv1.Kind(gvk.Service.Kind), gatewayv1.ObjectName(svc.Name)
+	} else if se, ok := c.serviceEntries[dr.Host]; ok && se.Namespace == cfg.Namespace {
+		target.Group, target.Kind, target.Name = gatewayv1.Group(gvk.ServiceEntry.Group), gatewayv1.Kind(gvk.ServiceEntry.Kind), gatewayv1.ObjectName(se.Name)
+	} else {
+		c.report(cfg, "spec.host", "host %q is not a Service or ServiceEntry in namespace %q; the TLS settings were not migrated", dr.Host, cfg.Namespace)
+		return
+	}
+	policy.Spec.TargetRefs = []gatewayv1.LocalPolicyTargetReferenceWithSectionName{target}
+
+	switch {
+	case strings.HasPrefix(tls.CredentialName, credentials.KubernetesConfigMapTypeURI):
+		name := strings.TrimPrefix(tls.CredentialName, credentials.KubernetesConfigMapTypeURI)
+		if ns, n, ok := strings.Cut(name, "/"); ok {
+			if ns != cfg.Namespace {
+				c.report(cfg, field+".credentialName", "the CA ConfigMap must be in namespace %q; the TLS settings were not migrated", cfg.Namespace)
+				return
+			}
+			name = n
+		}
+		policy.Spec.Validation.CACertificateRefs = []gatewayv1.LocalObjectReference{{Kind: "ConfigMap", Name: gatewayv1.ObjectName(name)}}
+	case tls.CredentialName != "":
+		c.report(cfg, field+".credentialName", "CA certificates must be in a ConfigMap; the TLS settings were not migrated")
+		return
+	default:
+		policy.Spec.Validation.WellKnownCACertificates = ptr.Of(gatewayv1.WellKnownCACertificatesSystem)
+	}
+	sni := tls.Sni
+	if sni == "" {
+		c.report(cfg, field+".sni", "the SNI is no longer derived from requests, and is set to the host %q", host)
+		sni = host
+	}
+	policy.Spec.Validation.Hostname = gatewayv1.PreciseHostname(sni)
+	for _, san := range tls.SubjectAltNames {
+		if strings.Contains(san, "://") {
+			policy.Spec.Validation.SubjectAltNames = append(policy.Spec.Validation.SubjectAltNames,
+				gatewayv1.SubjectAltName{Type: gatewayv1.URISubjectAltNameType, URI: gatewayv1.AbsoluteURI(san)})
+		} else {
+			policy.Spec.Validation.SubjectAltNames = append(policy.Spec.Validation.SubjectAltNames,
+				gatewayv1.SubjectAltName{Type: gatewayv1.HostnameSubjectAltNameType, Hostname: gatewayv1.Hostname(san)})
+		}
+	}
+	c.add(policy)
+}
@@ -0,0 +1,815 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"istio.io/api/label"
+	"istio.io/istio/istioctl/pkg/util/configgen"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/simulation"
+	"istio.io/istio/pilot/test/util"
+	"istio.io/istio/pilot/test/xds"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/slices"
+	"istio.io/istio/pkg/test/util/assert"
+	"istio.io/istio/pkg/test/util/file"
+	"istio.io/istio/pkg/test/util/tmpl"
+)
+
+func TestConvert(t *testing.T) {
+	in, err := configgen.ReadInputs([]string{"testdata/istio.yaml"})
+	assert.NoError(t, err)
+	result := Convert(in, Options{GatewayClass: "istio", DomainSuffix: constants.DefaultClusterLocalDomain})
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printResources(out, result.Resources))
+	printIssues(out, result.Issues)
+	util.CompareContent(t, out.Bytes(), "testdata/gateway-api.yaml.golden")
+}
+
+// baseInputs are the Services and Gateways the configuration of the table tests refers to.
+const baseInputs = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  ports:
+  - name: http
+    port: 9080
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: ratings
+  namespace: ratings
+spec:
+  ports:
+  - name: grpc
+    port: 9090
+---
+apiVersion: networking.istio.io/v1
+kind: Gateway
+metadata:
+  name: http
+  namespace: bookinfo
+spec:
+  selector:
+    istio: ingressgateway
+  servers:
+  - port:
+      number: 80
+      name: http
+      protocol: HTTP
+    hosts:
+    - "*"
+---
+apiVersion: networking.istio.io/v1
+kind: Gateway
+metadata:
+  name: grpc
+  namespace: bookinfo
+spec:
+  selector:
+    istio: ingressgateway
+  servers:
+  - port:
+      number: 9090
+      name: grpc
+      protocol: GRPC
+    hosts:
+    - "*"
+`
+
+func convertYAML(t *testing.T, yml string) *Result {
+	t.Helper()
+	f := filepath.Join(t.TempDir(), "istio.yaml")
+	assert.NoError(t, os.WriteFile(f, []byte(baseInputs+"---\n"+yml), 0o644))
+	in, err := configgen.ReadInputs([]string{f})
+	assert.NoError(t, err)
+	return Convert(in, Options{GatewayClass: "istio", DomainSuffix: constants.DefaultClusterLocalDomain})
+}
+
+// routes returns the kind, namespace and name of the converted resources, excluding the Gateways.
+func routes(r *Result) []string {
+	var out []string
+	for _, o := range r.Resources {
+		if _, ok := o.(*gatewayv1.Gateway); ok {
+			continue
+		}
+		out = append(out, fmt.Sprintf("%s/%s/%s", o.GetObjectKind().GroupVersionKind().Kind, o.GetNamespace(), o.GetName()))
+	}
+	return out
+}
+
+// issueFields returns the fields of the reported issues, excluding the issues of the Gateways.
+func issueFields(r *Result) []string {
+	var out []string
+	for _, i := range r.Issues {
+		if i.Kind != gvk.Gateway.Kind {
+			out = append(out, i.Kind+"/"+i.Name+": "+i.Field)
+		}
+	}
+	return out
+}
+
+func TestConvertHTTPRoutes(t *testing.T) {
+	cases := []struct {
+		name       string
+		in         string
+		wantRoutes []string
+		wantIssues []string
+	}{
+		{
+			name: "rules in precedence order",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  hosts: ["reviews.example.com"]
+  gateways: ["http"]
+  http:
+  - match:
+    - uri:
+        exact: /reviews/1
+    route:
+    - destination:
+        host: reviews
+  - match:
+    - uri:
+        prefix: /reviews/
+    route:
+    - destination:
+        host: reviews
+  - route:
+    - destination:
+        host: reviews
+`,
+			wantRoutes: []string{"HTTPRoute/bookinfo/reviews"},
+		},
+		{
+			name: "reordered by prefix length",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  hosts: ["reviews.example.com"]
+  gateways: ["http"]
+  http:
+  - name: catch-all
+    match:
+    - uri:
+        prefix: /
+    route:
+    - destination:
+        host: reviews
+  - name: reviews
+    match:
+    - uri:
+        prefix: /reviews/
+    route:
+    - destination:
+        host: reviews
+`,
+			wantRoutes: []string{"HTTPRoute/bookinfo/reviews"},
+			wantIssues: []string{"VirtualService/reviews: spec.http"},
+		},
+		{
+			name: "reordered by header matches",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  hosts: ["reviews.example.com"]
+  gateways: ["http"]
+  http:
+  - match:
+    - uri:
+        prefix: /reviews/
+    route:
+    - destination:
+        host: reviews
+  - match:
+    - uri:
+        prefix: /reviews/
+      headers:
+        end-user:
+          exact: jason
+    route:
+    - destination:
+        host: reviews
+`,
+			wantRoutes: []string{"HTTPRoute/bookinfo/reviews"},
+			wantIssues: []string{"VirtualService/reviews: spec.http"},
+		},
+		{
+			name: "catch-all before match",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  hosts: ["reviews.example.com"]
+  gateways: ["http"]
+  http:
+  - route:
+    - destination:
+        host: reviews
+  - match:
+    - uri:
+        exact: /reviews
+    route:
+    - destination:
+        host: reviews
+`,
+			wantRoutes: []string{"HTTPRoute/bookinfo/reviews"},
+			wantIssues: []string{"VirtualService/reviews: spec.http"},
+		},
+		{
+			name: "unsupported match is not migrated",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  hosts: ["reviews.example.com"]
+  gateways: ["http"]
+  http:
+  - match:
+    - authority:
+        exact: reviews.example.com
+    route:
+    - destination:
+        host: reviews
+`,
+			wantIssues: []string{"VirtualService/reviews: spec.http[0].match[0].authority"},
+		},
+		{
+			name: "subset is not migrated",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  hosts: ["reviews.example.com"]
+  gateways: ["http"]
+  http:
+  - route:
+    - destination:
+        host: reviews
+        subset: v1
+`,
+			wantIssues: []string{"VirtualService/reviews: spec.http[0].route[0].destination.subset"},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			result := convertYAML(t, tt.in)
+			assert.Equal(t, routes(result), tt.wantRoutes)
+			assert.Equal(t, issueFields(result), tt.wantIssues)
+		})
+	}
+}
+
+func TestConvertGRPCRoutes(t *testing.T) {
+	cases := []struct {
+		name       string
+		in         string
+		wantRoutes []string
+		wantIssues []string
+	}{
+		{
+			name: "methods",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: ratings
+  namespace: bookinfo
+spec:
+  hosts: ["ratings.example.com"]
+  gateways: ["grpc"]
+  http:
+  - match:
+    - uri:
+        exact: /ratings.Ratings/Get
+    route:
+    - destination:
+        host: ratings.ratings.svc.cluster.local
+  - match:
+    - uri:
+        prefix: /ratings.Ratings/
+    route:
+    - destination:
+        host: ratings.ratings.svc.cluster.local
+`,
+			wantRoutes: []string{"GRPCRoute/bookinfo/ratings", "ReferenceGrant/ratings/allow-routes-from-bookinfo"},
+		},
+		{
+			name: "timeout falls back to HTTPRoute",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: ratings
+  namespace: bookinfo
+spec:
+  hosts: ["ratings.example.com"]
+  gateways: ["grpc"]
+  http:
+  - timeout: 5s
+    route:
+    - destination:
+        host: ratings.ratings.svc.cluster.local
+`,
+			wantRoutes: []string{"HTTPRoute/bookinfo/ratings", "ReferenceGrant/ratings/allow-routes-from-bookinfo"},
+		},
+		{
+			name: "prefix not matching a service falls back to HTTPRoute",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: ratings
+  namespace: bookinfo
+spec:
+  hosts: ["ratings.example.com"]
+  gateways: ["grpc"]
+  http:
+  - match:
+    - uri:
+        prefix: /ratings
+    route:
+    - destination:
+        host: ratings.ratings.svc.cluster.local
+`,
+			wantRoutes: []string{"HTTPRoute/bookinfo/ratings", "ReferenceGrant/ratings/allow-routes-from-bookinfo"},
+			wantIssues: []string{"VirtualService/ratings: spec.http[0].match[0].uri"},
+		},
+		{
+			name: "HTTP gateway uses HTTPRoute",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: ratings
+  namespace: bookinfo
+spec:
+  hosts: ["ratings.example.com"]
+  gateways: ["http", "grpc"]
+  http:
+  - match:
+    - uri:
+        exact: /ratings.Ratings/Get
+    route:
+    - destination:
+        host: ratings.ratings.svc.cluster.local
+`,
+			wantRoutes: []string{"HTTPRoute/bookinfo/ratings", "ReferenceGrant/ratings/allow-routes-from-bookinfo"},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			result := convertYAML(t, tt.in)
+			assert.Equal(t, routes(result), tt.wantRoutes)
+			assert.Equal(t, issueFields(result), tt.wantIssues)
+		})
+	}
+}
+
+func TestConvertReferenceGrants(t *testing.T) {
+	cases := []struct {
+		name       string
+		in         string
+		wantRoutes []string
+	}{
+		{
+			name: "same namespace",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  hosts: ["reviews.example.com"]
+  gateways: ["http"]
+  http:
+  - route:
+    - destination:
+        host: reviews
+`,
+			wantRoutes: []string{"HTTPRoute/bookinfo/reviews"},
+		},
+		{
+			name: "cross namespace",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: ratings
+  namespace: bookinfo
+spec:
+  hosts: ["ratings.example.com"]
+  gateways: ["http"]
+  http:
+  - route:
+    - destination:
+        host: ratings.ratings.svc.cluster.local
+---
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: ratings-v2
+  namespace: bookinfo
+spec:
+  hosts: ["ratings-v2.example.com"]
+  gateways: ["http"]
+  http:
+  - route:
+    - destination:
+        host: ratings.ratings.svc.cluster.local
+`,
+			// Routes from the same namespace share a ReferenceGrant
+			wantRoutes: []string{"HTTPRoute/bookinfo/ratings", "HTTPRoute/bookinfo/ratings-v2", "ReferenceGrant/ratings/allow-routes-from-bookinfo"},
+		},
+		{
+			name: "route not migrated",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: ratings
+  namespace: bookinfo
+spec:
+  hosts: ["ratings.example.com"]
+  gateways: ["http"]
+  http:
+  - route:
+    - destination:
+        host: ratings.ratings.svc.cluster.local
+        subset: v1
+`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			result := convertYAML(t, tt.in)
+			assert.Equal(t, routes(result), tt.wantRoutes)
+		})
+	}
+
+	t.Run("grant", func(t *testing.T) {
+		result := convertYAML(t, cases[1].in)
+		grant := result.Resources[len(result.Resources)-1].(*gatewayv1.ReferenceGrant)
+		assert.Equal(t, grant.Spec.To, []gatewayv1.ReferenceGrantTo{{Kind: "Service"}})
+		assert.Equal(t, slices.Map(grant.Spec.From, func(f gatewayv1.ReferenceGrantFrom) string {
+			return string(f.Kind) + "/" + string(f.Namespace)
+		}), []string{"HTTPRoute/bookinfo", "GRPCRoute/bookinfo", "TLSRoute/bookinfo", "TCPRoute/bookinfo"})
+	})
+}
+
+func TestConvertDestinationRule(t *testing.T) {
+	cases := []struct {
+		name       string
+		in         string
+		wantRoutes []string
+		wantIssues []string
+	}{
+		{
+			name: "unsupported traffic policy",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: DestinationRule
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  host: reviews
+  exportTo: ["."]
+  trafficPolicy:
+    loadBalancer:
+      simple: LEAST_REQUEST
+    connectionPool:
+      tcp:
+        maxConnections: 10
+    outlierDetection:
+      consecutive5xxErrors: 5
+    portLevelSettings:
+    - port:
+        number: 9080
+      loadBalancer:
+        simple: ROUND_ROBIN
+  subsets:
+  - name: v1
+    labels:
+      version: v1
+`,
+			wantIssues: []string{
+				"DestinationRule/reviews: spec.exportTo",
+				"DestinationRule/reviews: spec.subsets",
+				"DestinationRule/reviews: spec.trafficPolicy.connectionPool",
+				"DestinationRule/reviews: spec.trafficPolicy.loadBalancer",
+				"DestinationRule/reviews: spec.trafficPolicy.outlierDetection",
+				"DestinationRule/reviews: spec.trafficPolicy.portLevelSettings",
+			},
+		},
+		{
+			name: "istio mutual",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: DestinationRule
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  host: reviews
+  trafficPolicy:
+    tls:
+      mode: ISTIO_MUTUAL
+`,
+		},
+		{
+			name: "simple",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: DestinationRule
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  host: reviews
+  trafficPolicy:
+    tls:
+      mode: SIMPLE
+      sni: reviews.example.com
+`,
+			wantRoutes: []string{"BackendTLSPolicy/bookinfo/reviews"},
+		},
+		{
+			name: "simple without sni",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: DestinationRule
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  host: reviews
+  trafficPolicy:
+    tls:
+      mode: SIMPLE
+`,
+			wantRoutes: []string{"BackendTLSPolicy/bookinfo/reviews"},
+			wantIssues: []string{"DestinationRule/reviews: spec.trafficPolicy.tls.sni"},
+		},
+		{
+			name: "mutual",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: DestinationRule
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  host: reviews
+  trafficPolicy:
+    tls:
+      mode: MUTUAL
+      credentialName: reviews-client
+`,
+			wantIssues: []string{"DestinationRule/reviews: spec.trafficPolicy.tls.mode"},
+		},
+		{
+			name: "insecure skip verify",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: DestinationRule
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  host: reviews
+  trafficPolicy:
+    tls:
+      mode: SIMPLE
+      insecureSkipVerify: true
+`,
+			wantIssues: []string{"DestinationRule/reviews: spec.trafficPolicy.tls.insecureSkipVerify"},
+		},
+		{
+			name: "CA certificate in a Secret",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: DestinationRule
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  host: reviews
+  trafficPolicy:
+    tls:
+      mode: SIMPLE
+      credentialName: reviews-ca
+      sni: reviews.example.com
+`,
+			wantIssues: []string{"DestinationRule/reviews: spec.trafficPolicy.tls.credentialName"},
+		},
+		{
+			name: "host in another namespace",
+			in: `
+apiVersion: networking.istio.io/v1
+kind: DestinationRule
+metadata:
+  name: ratings
+  namespace: bookinfo
+spec:
+  host: ratings.ratings.svc.cluster.local
+  trafficPolicy:
+    tls:
+      mode: SIMPLE
+      sni: ratings.example.com
+`,
+			wantIssues: []string{"DestinationRule/ratings: spec.host"},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			result := convertYAML(t, tt.in)
+			assert.Equal(t, routes(result), tt.wantRoutes)
+			assert.Equal(t, issueFields(result), tt.wantIssues)
+		})
+	}
+}
+
+// gatewayAPIObjects are the GatewayClass of the converted Gateways, and the Services and Pods the deployment
+// controller creates for each of them.
+const gatewayAPIObjects = `
+apiVersion: gateway.networking.k8s.io/v1
+kind: GatewayClass
+metadata:
+  name: istio
+spec:
+  controllerName: istio.io/gateway-controller
+{{- range . }}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .Name }}-istio
+  namespace: {{ .Namespace }}
+spec:
+  selector:
+    gateway.networking.k8s.io/gateway-name: {{ .Name }}
+  ports:
+{{- range .Ports }}
+  - name: port-{{ . }}
+    port: {{ . }}
+    targetPort: {{ . }}
+{{- end }}
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: {{ .Name }}-istio
+  namespace: {{ .Namespace }}
+  labels:
+    gateway.networking.k8s.io/gateway-name: {{ .Name }}
+spec: {}
+status:
+  conditions:
+  - status: "True"
+    type: Ready
+  podIP: {{ .IP }}
+{{- end }}
+`
+
+// TestConvertEquivalence checks that the gateways configured by the converted resources route requests to the same
+// clusters as the gateways configured by the Istio configuration they were converted from.
+func TestConvertEquivalence(t *testing.T) {
+	const input = "testdata/equivalence.yaml"
+	in, err := configgen.ReadInputs([]string{input})
+	assert.NoError(t, err)
+	result := Convert(in, Options{GatewayClass: "istio", DomainSuffix: constants.DefaultClusterLocalDomain})
+	assert.Equal(t, issueFields(result), nil)
+	converted := &bytes.Buffer{}
+	assert.NoError(t, printResources(converted, result.Resources))
+	kubeObjects := file.AsStringOrFail(t, input)
+
+	type gateway struct {
+		Name      string
+		Namespace string
+		Ports     []int
+		IP        string
+		// istioLabels select the gateway pods of the Istio Gateway.
+		istioLabels map[string]string
+	}
+	gateways := []gateway{
+		{Name: "bookinfo", Namespace: "bookinfo", Ports: []int{80, 5432}, IP: "10.0.0.1", istioLabels: map[string]string{"istio": "bookinfo"}},
+		{Name: "grpc", Namespace: "ratings", Ports: []int{9090}, IP: "10.0.0.2", istioLabels: map[string]string{"istio": "grpc"}},
+	}
+	// The simulation only matches the host and path of requests.
+	calls := []struct {
+		gateway string
+		call    simulation.Call
+	}{
+		{"bookinfo", simulation.Call{Port: 80, Protocol: simulation.HTTP, HostHeader: "bookinfo.example.com", Path: "/details"}},
+		{"bookinfo", simulation.Call{Port: 80, Protocol: simulation.HTTP, HostHeader: "bookinfo.example.com", Path: "/details/1"}},
+		{"bookinfo", simulation.Call{Port: 80, Protocol: simulation.HTTP, HostHeader: "bookinfo.example.com", Path: "/api/reviews"}},
+		{"bookinfo", simulation.Call{Port: 80, Protocol: simulation.HTTP, HostHeader: "bookinfo.example.com", Path: "/api/ratings/1"}},
+		{"bookinfo", simulation.Call{Port: 80, Protocol: simulation.HTTP, HostHeader: "ratings.example.com", Path: "/"}},
+		{"bookinfo", simulation.Call{Port: 80, Protocol: simulation.HTTP, HostHeader: "unknown.example.com", Path: "/"}},
+		{"bookinfo", simulation.Call{Port: 5432, Protocol: simulation.TCP}},
+		{"grpc", simulation.Call{Port: 9090, Protocol: simulation.HTTP2, HostHeader: "ratings", Path: "/ratings.Ratings/Get"}},
+	}
+
+	run := func(opts xds.FakeOptions, proxy func(gateway) *model.Proxy) []string {
+		s := xds.NewFakeDiscoveryServer(t, opts)
+		sims := map[string]*simulation.Simulation{}
+		for _, gw := range gateways {
+			sims[gw.Name] = simulation.NewSimulation(t, s, s.SetupProxy(proxy(gw)))
+		}
+		var clusters []string
+		for _, c := range calls {
+			c.call.CallMode = simulation.CallModeGateway
+			clusters = append(clusters, sims[c.gateway].Run(c.call).ClusterMatched)
+		}
+		return clusters
+	}
+
+	// Short hostnames are resolved against the domain of the config, which is set by the Kubernetes config store.
+	configs := slices.Map(in.Configs, func(c config.Config) config.Config {
+		c.Domain = constants.DefaultClusterLocalDomain
+		return c
+	})
+	istio := run(xds.FakeOptions{Configs: configs, KubernetesObjectString: kubeObjects}, func(gw gateway) *model.Proxy {
+		return &model.Proxy{
+			Type:            model.Router,
+			ConfigNamespace: gw.Namespace,
+			Labels:          gw.istioLabels,
+			Metadata:        &model.NodeMetadata{Namespace: gw.Namespace, Labels: gw.istioLabels},
+		}
+	})
+	assert.Equal(t, istio, []string{
+		"outbound|9080||details.bookinfo.svc.cluster.local",
+		"outbound|9080||details.bookinfo.svc.cluster.local",
+		"outbound|9080||reviews.bookinfo.svc.cluster.local",
+		"outbound|9080||ratings.ratings.svc.cluster.local",
+		"outbound|9080||ratings.ratings.svc.cluster.local",
+		"",
+		"outbound|5432||db.bookinfo.svc.cluster.local",
+		"outbound|9090||ratings.ratings.svc.cluster.local",
+	})
+
+	gatewayAPI := run(xds.FakeOptions{
+		KubernetesObjectString: kubeObjects + "\n---\n" + tmpl.MustEvaluate(gatewayAPIObjects, gateways) + "\n---\n" + converted.String(),
+	}, func(gw gateway) *model.Proxy {
+		labels := map[string]string{label.IoK8sNetworkingGatewayGatewayName.Name: gw.Name}
+		return &model.Proxy{
+			Type:            model.Router,
+			ConfigNamespace: gw.Namespace,
+			IPAddresses:     []string{gw.IP},
+			Labels:          labels,
+			Metadata:        &model.NodeMetadata{Namespace: gw.Namespace, Labels: labels},
+		}
+	})
+	assert.Equal(t, gatewayAPI, istio)
+}
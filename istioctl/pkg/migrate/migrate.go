@@ -0,0 +1,198 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"istio.io/istio/istioctl/pkg/cli"
+	"istio.io/istio/istioctl/pkg/util"
+	"istio.io/istio/istioctl/pkg/util/configgen"
+	"istio.io/istio/pilot/pkg/config/kube/crdclient"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/controllers"
+)
+
+// Cmd migrates Istio configuration to other APIs.
+func Cmd(ctx cli.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate Istio configuration to other APIs",
+		Long:  `Migrate translates Istio configuration to equivalent resources of other APIs.`,
+	}
+	cmd.AddCommand(gatewayAPICmd(ctx))
+	cmd.Long += "\n\n" + util.ExperimentalMsg
+	return cmd
+}
+
+func gatewayAPICmd(ctx cli.Context) *cobra.Command {
+	var (
+		files         []string
+		gatewayClass  string
+		allNamespaces bool
+	)
+	cmd := &cobra.Command{
+		Use:   "gateway-api",
+		Short: "Translate Istio networking configuration to the Kubernetes Gateway API",
+		Long: `Gateway-api translates Gateways, VirtualServices and DestinationRules to Gateways, HTTPRoutes, GRPCRoutes,
+TLSRoutes, TCPRoutes and BackendTLSPolicies of the Kubernetes Gateway API, and prints them as YAML.
+
+Settings that cannot be expressed with the Gateway API are reported, with the reason, after the converted resources.
+Routing rules are only converted if their matches and destinations can be fully expressed, so that no request is
+routed differently; other settings, such as fault injection, are dropped from the converted rules. The Gateway API
+orders rules by the precedence of their matches rather than the order they are listed in, which is reported when
+it may change which rule applies to a request.
+
+The converted Gateways deploy new gateways instead of configuring the existing ones, so both can run side by side
+while traffic is moved over. The resources are read from the cluster, or from files with --filename.`,
+		Example: `  # Translate the Istio configuration of the default namespace
+  istioctl x migrate gateway-api -n default
+
+  # Translate the Istio configuration of all namespaces
+  istioctl x migrate gateway-api -A > gateway-api.yaml
+
+  # Translate the Istio configuration and Services in files
+  istioctl x migrate gateway-api -f gateway.yaml -f virtualservices.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			var in *configgen.Inputs
+			var err error
+			if len(files) > 0 {
+				in, err = configgen.ReadInputs(files)
+			} else {
+				client, cerr := ctx.CLIClient()
+				if cerr != nil {
+					return cerr
+				}
+				namespace := ctx.NamespaceOrDefault(ctx.Namespace())
+				if allNamespaces {
+					namespace = metav1.NamespaceAll
+				}
+				in, err = readClusterInputs(context.Background(), client, namespace)
+			}
+			if err != nil {
+				return err
+			}
+			result := Convert(in, Options{GatewayClass: gatewayClass, DomainSuffix: constants.DefaultClusterLocalDomain})
+			if err := printResources(c.OutOrStdout(), result.Resources); err != nil {
+				return err
+			}
+			printIssues(c.ErrOrStderr(), result.Issues)
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVarP(&files, "filename", "f", nil,
+		"Files with the Istio configuration and Services to translate. If not set, the configuration is read from the cluster")
+	cmd.Flags().StringVar(&gatewayClass, "gateway-class", "istio", "GatewayClass of the translated Gateways")
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Translate the configuration of all namespaces")
+	return cmd
+}
+
+// readClusterInputs reads the Istio networking configuration of a namespace from the cluster, and the Services and
+// ServiceEntries of all namespaces, which routes may refer to.
+func readClusterInputs(ctx context.Context, client kube.CLIClient, namespace string) (*configgen.Inputs, error) {
+	in := &configgen.Inputs{}
+	services, err := client.Kube().CoreV1().Services(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Services: %v", err)
+	}
+	for i := range services.Items {
+		in.Services = append(in.Services, &services.Items[i])
+	}
+
+	domain := constants.DefaultClusterLocalDomain
+	networkingClient := client.Istio().NetworkingV1()
+	serviceEntries, err := networkingClient.ServiceEntries(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ServiceEntries: %v", err)
+	}
+	for _, obj := range serviceEntries.Items {
+		in.Configs = append(in.Configs, crdclient.TranslateObject(obj, gvk.ServiceEntry, domain))
+	}
+	gateways, err := networkingClient.Gateways(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Gateways: %v", err)
+	}
+	for _, obj := range gateways.Items {
+		in.Configs = append(in.Configs, crdclient.TranslateObject(obj, gvk.Gateway, domain))
+	}
+	virtualServices, err := networkingClient.VirtualServices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list VirtualServices: %v", err)
+	}
+	for _, obj := range virtualServices.Items {
+		in.Configs = append(in.Configs, crdclient.TranslateObject(obj, gvk.VirtualService, domain))
+	}
+	destinationRules, err := networkingClient.DestinationRules(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list DestinationRules: %v", err)
+	}
+	for _, obj := range destinationRules.Items {
+		in.Configs = append(in.Configs, crdclient.TranslateObject(obj, gvk.DestinationRule, domain))
+	}
+	return in, nil
+}
+
+// printResources prints resources as a YAML stream, without the status and creation timestamp that are set by the
+// API server.
+func printResources(w io.Writer, resources []controllers.Object) error {
+	for i, r := range resources {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		obj := map[string]any{}
+		if err := json.Unmarshal(b, &obj); err != nil {
+			return err
+		}
+		delete(obj, "status")
+		if meta, ok := obj["metadata"].(map[string]any); ok {
+			delete(meta, "creationTimestamp")
+		}
+		out, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(w, "---")
+		}
+		fmt.Fprint(w, string(out))
+	}
+	return nil
+}
+
+func printIssues(w io.Writer, issues []Issue) {
+	if len(issues) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%d settings could not be translated:\n\n", len(issues))
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "RESOURCE\tFIELD\tREASON")
+	for _, i := range issues {
+		fmt.Fprintf(tw, "%s/%s.%s\t%s\t%s\n", i.Kind, i.Name, i.Namespace, i.Field, strings.ReplaceAll(i.Reason, "\t", " "))
+	}
+	_ = tw.Flush()
+}
@@ -70,7 +70,7 @@ func Cmd(ctx cli.Context) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			msgs := diag.Messages{}
 			if !skipControlPlane {
-				msgs, err = checkControlPlane(ctx)
+				msgs, err = CheckControlPlane(ctx)
 				if err != nil {
 					return err
 				}
@@ -188,7 +188,8 @@ func ObjectToInstance(c controllers.Object) *resource.Instance {
 	}
 }
 
-func checkControlPlane(ctx cli.Context) (diag.Messages, error) {
+// CheckControlPlane runs the control plane checks of precheck against the cluster.
+func CheckControlPlane(ctx cli.Context) (diag.Messages, error) {
 	cli, err := ctx.CLIClient()
 	if err != nil {
 		return nil, err
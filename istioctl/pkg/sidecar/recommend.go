@@ -0,0 +1,307 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sidecar
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/netip"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	prommodel "github.com/prometheus/common/model"
+	"google.golang.org/protobuf/proto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/istioctl/pkg/util/configgen"
+	"istio.io/istio/pilot/pkg/config/kube/crdclient"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/networking/core"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/log"
+	"istio.io/istio/pkg/maps"
+	"istio.io/istio/pkg/util/sets"
+)
+
+const (
+	reqTot    = "istio_requests_total"
+	tcpOpened = "istio_tcp_connections_opened_total"
+
+	sourceNamespaceLabel = "source_workload_namespace"
+	destServiceLabel     = "destination_service"
+	destNamespaceLabel   = "destination_service_namespace"
+
+	unknown = "unknown"
+)
+
+// Edge is a service-to-service dependency observed from traffic.
+type Edge struct {
+	// SourceNamespace is the namespace of the client workloads.
+	SourceNamespace string
+	// Destination is the hostname of the destination Service or ServiceEntry.
+	Destination host.Name
+	// DestinationNamespace is the namespace of the destination, or empty if it is not known.
+	DestinationNamespace string
+}
+
+// EdgesFromPrometheus returns the edges observed by client sidecars over the window, from the
+// istio_requests_total and istio_tcp_connections_opened_total metrics.
+func EdgesFromPrometheus(ctx context.Context, api promv1.API, window time.Duration) ([]Edge, error) {
+	var edges []Edge
+	for _, metric := range []string{reqTot, tcpOpened} {
+		query := fmt.Sprintf(`sum by (%s, %s, %s) (increase(%s{reporter="source"}[%s]))`,
+			sourceNamespaceLabel, destServiceLabel, destNamespaceLabel, metric, prommodel.Duration(window))
+		val, warn, err := api.Query(ctx, query, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s: %v", metric, err)
+		}
+		if len(warn) > 0 {
+			log.Warnf("query %q returned warnings: %v", query, warn)
+		}
+		vec, ok := val.(prommodel.Vector)
+		if !ok {
+			return nil, fmt.Errorf("unexpected result type %s for query %q", val.Type(), query)
+		}
+		for _, s := range vec {
+			if s.Value <= 0 {
+				continue
+			}
+			src := string(s.Metric[sourceNamespaceLabel])
+			dst := string(s.Metric[destServiceLabel])
+			if !knownLabel(src) || !knownHost(dst) {
+				continue
+			}
+			dstNamespace := string(s.Metric[destNamespaceLabel])
+			if !knownLabel(dstNamespace) {
+				dstNamespace = ""
+			}
+			edges = append(edges, Edge{SourceNamespace: src, Destination: host.Name(dst), DestinationNamespace: dstNamespace})
+		}
+	}
+	return edges, nil
+}
+
+// upstreamClusterRegex matches outbound cluster names, such as outbound|9080||reviews.default.svc.cluster.local, in
+// both the default text and the JSON access log formats.
+var upstreamClusterRegex = regexp.MustCompile(`outbound\|\d+\|[^|]*\|([^\s"|,]+)`)
+
+// EdgesFromAccessLog returns the edges found in the access log of sidecars in the given namespace.
+// Only the upstream cluster of each line is used, so any access log format including it is supported.
+func EdgesFromAccessLog(r io.Reader, namespace, domainSuffix string) ([]Edge, error) {
+	var edges []Edge
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		m := upstreamClusterRegex.FindStringSubmatch(scanner.Text())
+		if m == nil || !knownHost(m[1]) {
+			continue
+		}
+		edges = append(edges, Edge{
+			SourceNamespace:      namespace,
+			Destination:          host.Name(m[1]),
+			DestinationNamespace: serviceNamespace(m[1], domainSuffix),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return edges, nil
+}
+
+func knownLabel(v string) bool {
+	return v != "" && v != unknown
+}
+
+// knownHost returns false for the hosts of passthrough traffic, which can not be imported by a Sidecar.
+func knownHost(h string) bool {
+	if !knownLabel(h) {
+		return false
+	}
+	_, err := netip.ParseAddr(h)
+	return err != nil
+}
+
+// serviceNamespace returns the namespace of a Kubernetes Service hostname, or empty for other hosts.
+func serviceNamespace(h, domainSuffix string) string {
+	name, ok := strings.CutSuffix(h, ".svc."+domainSuffix)
+	if !ok {
+		return ""
+	}
+	parts := strings.Split(name, ".")
+	if len(parts) != 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+// Recommend returns a Sidecar for each source namespace of the edges, which only imports the observed destinations
+// and the Istio system namespace. Edges from the Istio system namespace are ignored: it is usually the root
+// namespace, where a Sidecar without a workload selector would apply to the whole mesh.
+func Recommend(edges []Edge, istioNamespace string) []config.Config {
+	hostsByNamespace := map[string]sets.String{}
+	for _, e := range edges {
+		if e.SourceNamespace == istioNamespace {
+			continue
+		}
+		hosts, f := hostsByNamespace[e.SourceNamespace]
+		if !f {
+			hosts = sets.New(istioNamespace + "/*")
+			hostsByNamespace[e.SourceNamespace] = hosts
+		}
+		dstNamespace := e.DestinationNamespace
+		if dstNamespace == "" {
+			dstNamespace = "*"
+		}
+		if hosts.Contains(dstNamespace + "/*") {
+			continue
+		}
+		hosts.Insert(dstNamespace + "/" + string(e.Destination))
+	}
+
+	out := make([]config.Config, 0, len(hostsByNamespace))
+	for ns, hosts := range maps.SeqStable(hostsByNamespace) {
+		out = append(out, config.Config{
+			Meta: config.Meta{
+				GroupVersionKind: gvk.Sidecar,
+				Name:             "default",
+				Namespace:        ns,
+			},
+			Spec: &networking.Sidecar{
+				Egress: []*networking.IstioEgressListener{{
+					Hosts: sets.SortedList(hosts),
+				}},
+			},
+		})
+	}
+	return out
+}
+
+// ConfigSize is the size of the configuration generated for a sidecar, excluding endpoints and secrets.
+type ConfigSize struct {
+	Services int `json:"services"`
+	Clusters int `json:"clusters"`
+	Bytes    int `json:"bytes"`
+}
+
+// Savings is the estimated effect of a recommended Sidecar on the configuration of the sidecars in its namespace.
+type Savings struct {
+	Namespace string     `json:"namespace"`
+	Current   ConfigSize `json:"current"`
+	Proposed  ConfigSize `json:"proposed"`
+}
+
+// EstimateSavings computes the SidecarScope of a sidecar in the namespace of the recommended Sidecar, with the
+// current configuration and with the recommended Sidecar replacing any namespace-wide Sidecar, and compares the
+// size of the configuration generated from them.
+func EstimateSavings(in *configgen.Inputs, recommended config.Config) (*Savings, error) {
+	proposed := []config.Config{recommended}
+	for _, c := range in.Configs {
+		if c.GroupVersionKind == gvk.Sidecar && c.Namespace == recommended.Namespace &&
+			c.Spec.(*networking.Sidecar).GetWorkloadSelector() == nil {
+			continue
+		}
+		proposed = append(proposed, c)
+	}
+	s := &Savings{Namespace: recommended.Namespace}
+	var err error
+	if s.Current, err = configSize(in, in.Configs, recommended.Namespace); err != nil {
+		return nil, err
+	}
+	if s.Proposed, err = configSize(in, proposed, recommended.Namespace); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func configSize(in *configgen.Inputs, configs []config.Config, namespace string) (ConfigSize, error) {
+	var size ConfigSize
+	err := configgen.Generate(in, configs, configgen.ProxyOptions{
+		Type:      model.SidecarProxy,
+		Namespace: namespace,
+		IP:        "10.0.0.1",
+	}, func(cg *core.ConfigGenTest, p *model.Proxy) {
+		size.Services = len(p.SidecarScope.Services())
+		listeners := cg.Listeners(p)
+		for _, l := range listeners {
+			size.Bytes += proto.Size(l)
+		}
+		clusters := cg.Clusters(p)
+		size.Clusters = len(clusters)
+		for _, c := range clusters {
+			size.Bytes += proto.Size(c)
+		}
+		for _, r := range cg.RoutesFromListeners(p, listeners) {
+			size.Bytes += proto.Size(r)
+		}
+	})
+	return size, err
+}
+
+// readClusterInputs reads the Services and the Istio configuration affecting the SidecarScope from the cluster.
+func readClusterInputs(ctx context.Context, client kube.CLIClient) (*configgen.Inputs, error) {
+	in := &configgen.Inputs{}
+	services, err := client.Kube().CoreV1().Services(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Services: %v", err)
+	}
+	for i := range services.Items {
+		in.Services = append(in.Services, &services.Items[i])
+	}
+
+	domain := constants.DefaultClusterLocalDomain
+	networkingClient := client.Istio().NetworkingV1()
+	serviceEntries, err := networkingClient.ServiceEntries(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ServiceEntries: %v", err)
+	}
+	for _, obj := range serviceEntries.Items {
+		in.Configs = append(in.Configs, crdclient.TranslateObject(obj, gvk.ServiceEntry, domain))
+	}
+	virtualServices, err := networkingClient.VirtualServices(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list VirtualServices: %v", err)
+	}
+	for _, obj := range virtualServices.Items {
+		in.Configs = append(in.Configs, crdclient.TranslateObject(obj, gvk.VirtualService, domain))
+	}
+	destinationRules, err := networkingClient.DestinationRules(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list DestinationRules: %v", err)
+	}
+	for _, obj := range destinationRules.Items {
+		in.Configs = append(in.Configs, crdclient.TranslateObject(obj, gvk.DestinationRule, domain))
+	}
+	sidecars, err := networkingClient.Sidecars(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Sidecars: %v", err)
+	}
+	for _, obj := range sidecars.Items {
+		in.Configs = append(in.Configs, crdclient.TranslateObject(obj, gvk.Sidecar, domain))
+	}
+	sort.SliceStable(in.Configs, func(i, j int) bool {
+		return in.Configs[i].CreationTimestamp.Before(in.Configs[j].CreationTimestamp)
+	})
+	return in, nil
+}
@@ -0,0 +1,179 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sidecar
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	prommodel "github.com/prometheus/common/model"
+
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/istioctl/pkg/util/configgen"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+// mockPromAPI returns canned vectors for queries containing a metric name.
+type mockPromAPI struct {
+	promv1.API
+	cannedResponse map[string]prommodel.Vector
+}
+
+func (client mockPromAPI) Query(_ context.Context, query string, _ time.Time, _ ...promv1.Option) (prommodel.Value, promv1.Warnings, error) {
+	for metric, v := range client.cannedResponse {
+		if strings.Contains(query, metric+"{") {
+			return v, nil, nil
+		}
+	}
+	return prommodel.Vector{}, nil, nil
+}
+
+func sample(src, dst, dstNamespace string, value float64) *prommodel.Sample {
+	return &prommodel.Sample{
+		Metric: prommodel.Metric{
+			sourceNamespaceLabel: prommodel.LabelValue(src),
+			destServiceLabel:     prommodel.LabelValue(dst),
+			destNamespaceLabel:   prommodel.LabelValue(dstNamespace),
+		},
+		Value: prommodel.SampleValue(value),
+	}
+}
+
+func TestEdgesFromPrometheus(t *testing.T) {
+	api := mockPromAPI{cannedResponse: map[string]prommodel.Vector{
+		reqTot: {
+			sample("bookinfo", "reviews.bookinfo.svc.cluster.local", "bookinfo", 100),
+			sample("bookinfo", "ratings.bookinfo.svc.cluster.local", "bookinfo", 0),
+			sample("unknown", "details.bookinfo.svc.cluster.local", "bookinfo", 5),
+			sample("bookinfo", "unknown", "unknown", 5),
+		},
+		tcpOpened: {
+			sample("bookinfo", "mysql.db.svc.cluster.local", "db", 3),
+			sample("bookinfo", "api.example.com", "unknown", 1),
+			sample("bookinfo", "10.0.0.5", "unknown", 1),
+		},
+	}}
+	edges, err := EdgesFromPrometheus(context.Background(), api, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, edges, []Edge{
+		{SourceNamespace: "bookinfo", Destination: "reviews.bookinfo.svc.cluster.local", DestinationNamespace: "bookinfo"},
+		{SourceNamespace: "bookinfo", Destination: "mysql.db.svc.cluster.local", DestinationNamespace: "db"},
+		{SourceNamespace: "bookinfo", Destination: "api.example.com"},
+	})
+}
+
+func TestEdgesFromAccessLog(t *testing.T) {
+	f, err := os.Open("testdata/access.log")
+	assert.NoError(t, err)
+	defer f.Close()
+	edges, err := EdgesFromAccessLog(f, "bookinfo", "cluster.local")
+	assert.NoError(t, err)
+	assert.Equal(t, edges, []Edge{
+		{SourceNamespace: "bookinfo", Destination: "reviews.bookinfo.svc.cluster.local", DestinationNamespace: "bookinfo"},
+		{SourceNamespace: "bookinfo", Destination: "details.bookinfo.svc.cluster.local", DestinationNamespace: "bookinfo"},
+		{SourceNamespace: "bookinfo", Destination: "api.example.com"},
+	})
+}
+
+func TestRecommend(t *testing.T) {
+	sidecars := Recommend([]Edge{
+		{SourceNamespace: "bookinfo", Destination: "reviews.bookinfo.svc.cluster.local", DestinationNamespace: "bookinfo"},
+		{SourceNamespace: "bookinfo", Destination: "reviews.bookinfo.svc.cluster.local", DestinationNamespace: "bookinfo"},
+		{SourceNamespace: "bookinfo", Destination: "api.example.com"},
+		{SourceNamespace: "other", Destination: "istiod.istio-system.svc.cluster.local", DestinationNamespace: "istio-system"},
+		{SourceNamespace: "istio-system", Destination: "reviews.bookinfo.svc.cluster.local", DestinationNamespace: "bookinfo"},
+	}, "istio-system")
+	assert.Equal(t, len(sidecars), 2)
+	assert.Equal(t, sidecars[0].Namespace, "bookinfo")
+	assert.Equal(t, sidecars[0].Spec.(*networking.Sidecar).Egress[0].Hosts,
+		[]string{"*/api.example.com", "bookinfo/reviews.bookinfo.svc.cluster.local", "istio-system/*"})
+	assert.Equal(t, sidecars[1].Namespace, "other")
+	assert.Equal(t, sidecars[1].Spec.(*networking.Sidecar).Egress[0].Hosts, []string{"istio-system/*"})
+}
+
+func TestEstimateSavings(t *testing.T) {
+	in, err := configgen.ReadInputs([]string{"testdata/services.yaml"})
+	assert.NoError(t, err)
+	sidecars := Recommend([]Edge{
+		{SourceNamespace: "bookinfo", Destination: "reviews.bookinfo.svc.cluster.local", DestinationNamespace: "bookinfo"},
+	}, "istio-system")
+	savings, err := EstimateSavings(in, sidecars[0])
+	assert.NoError(t, err)
+	assert.Equal(t, savings.Namespace, "bookinfo")
+	assert.Equal(t, savings.Current.Services, 7)
+	assert.Equal(t, savings.Proposed.Services, 2)
+	if savings.Proposed.Clusters >= savings.Current.Clusters || savings.Proposed.Bytes >= savings.Current.Bytes {
+		t.Fatalf("expected the recommended Sidecar to reduce the configuration size: %+v", savings)
+	}
+
+	// A namespace-wide Sidecar importing everything is replaced by the recommendation
+	in.Configs = append(in.Configs, config.Config{
+		Meta: config.Meta{GroupVersionKind: gvk.Sidecar, Name: "all", Namespace: "bookinfo", CreationTimestamp: time.Now()},
+		Spec: &networking.Sidecar{Egress: []*networking.IstioEgressListener{{Hosts: []string{"bookinfo/*"}}}},
+	})
+	savings, err = EstimateSavings(in, sidecars[0])
+	assert.NoError(t, err)
+	assert.Equal(t, savings.Current.Services, 4)
+	assert.Equal(t, savings.Proposed.Services, 2)
+}
+
+func TestPrintRecommendations(t *testing.T) {
+	sidecars := Recommend([]Edge{
+		{SourceNamespace: "bookinfo", Destination: "reviews.bookinfo.svc.cluster.local", DestinationNamespace: "bookinfo"},
+		{SourceNamespace: "other", Destination: "api.example.com"},
+	}, "istio-system")
+	var out bytes.Buffer
+	assert.NoError(t, printRecommendations(&out, sidecars, []*Savings{
+		{Namespace: "bookinfo", Current: ConfigSize{10, 12, 5000}, Proposed: ConfigSize{2, 4, 1000}},
+		{Namespace: "other", Current: ConfigSize{10, 12, 5000}, Proposed: ConfigSize{1, 3, 800}},
+	}))
+	assert.Equal(t, out.String(), `# Estimated sidecar configuration size in namespace bookinfo:
+#   services: 10 -> 2
+#   clusters: 12 -> 4
+#   bytes:    5000 -> 1000
+apiVersion: networking.istio.io/v1
+kind: Sidecar
+metadata:
+  name: default
+  namespace: bookinfo
+spec:
+  egress:
+  - hosts:
+    - bookinfo/reviews.bookinfo.svc.cluster.local
+    - istio-system/*
+---
+# Estimated sidecar configuration size in namespace other:
+#   services: 10 -> 1
+#   clusters: 12 -> 3
+#   bytes:    5000 -> 800
+apiVersion: networking.istio.io/v1
+kind: Sidecar
+metadata:
+  name: default
+  namespace: other
+spec:
+  egress:
+  - hosts:
+    - '*/api.example.com'
+    - istio-system/*
+`)
+}
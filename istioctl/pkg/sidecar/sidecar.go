@@ -0,0 +1,239 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"istio.io/istio/istioctl/pkg/cli"
+	"istio.io/istio/istioctl/pkg/dashboard"
+	"istio.io/istio/istioctl/pkg/util"
+	"istio.io/istio/istioctl/pkg/util/configgen"
+	"istio.io/istio/pilot/pkg/config/kube/crd"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/log"
+)
+
+// Cmd groups commands used for managing Sidecar resources.
+func Cmd(ctx cli.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sidecar",
+		Short: "Manage Istio Sidecar resources",
+	}
+
+	cmd.AddCommand(recommendCmd(ctx))
+	cmd.Long += "\n\n" + util.ExperimentalMsg
+	return cmd
+}
+
+func recommendCmd(ctx cli.Context) *cobra.Command {
+	var (
+		promAddress string
+		accessLogs  []string
+		duration    time.Duration
+		filenames   []string
+		estimate    bool
+	)
+	cmd := &cobra.Command{
+		Use:   "recommend",
+		Short: "Recommend Sidecar resources from the observed traffic",
+		Long: `Recommend generates a Sidecar resource for each namespace, importing only the services its workloads were
+observed sending traffic to, and the Istio system namespace.
+
+The traffic is read from the istio_requests_total and istio_tcp_connections_opened_total metrics reported by client
+sidecars, from Prometheus, or from sidecar access logs. By default, the Prometheus pod in the Istio system namespace
+is used.
+
+For each recommended Sidecar, the size of the configuration of a sidecar in the namespace is estimated with and without
+it, from the Services and Istio configuration in the cluster, or in the given input files.
+
+Only the traffic observed during the query duration, or in the access logs, is taken into account. Review the
+recommended Sidecars before applying them: destinations that were not called during that time will no longer be
+reachable through the mesh.`,
+		Example: `  # Recommend Sidecars for all namespaces, from the traffic of the last week
+  istioctl x sidecar recommend
+
+  # Recommend a Sidecar for namespace bookinfo, from a Prometheus endpoint
+  istioctl x sidecar recommend -n bookinfo --prometheus-address http://prometheus.example.com:9090 -d 24h
+
+  # Recommend a Sidecar for namespace bookinfo from exported access logs, and estimate the savings offline
+  istioctl x sidecar recommend --access-log bookinfo=productpage.log --access-log bookinfo=reviews.log -f cluster.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var edges []Edge
+			var err error
+			if len(accessLogs) > 0 {
+				edges, err = readAccessLogs(accessLogs)
+			} else {
+				edges, err = queryPrometheus(ctx, promAddress, duration)
+			}
+			if err != nil {
+				return err
+			}
+
+			sidecars := Recommend(edges, ctx.IstioNamespace())
+			if ns := ctx.Namespace(); ns != "" {
+				sidecars = filterNamespace(sidecars, ns)
+			}
+			if len(sidecars) == 0 {
+				return errors.New("no traffic was observed, no Sidecar can be recommended")
+			}
+
+			var savings []*Savings
+			if estimate {
+				if savings, err = estimateSavings(ctx, filenames, sidecars); err != nil {
+					return err
+				}
+			}
+			return printRecommendations(cmd.OutOrStdout(), sidecars, savings)
+		},
+	}
+	cmd.Flags().StringVar(&promAddress, "prometheus-address", "",
+		"Address of a Prometheus compatible query endpoint. If not set, the Prometheus pod in the Istio system namespace is used")
+	cmd.Flags().StringArrayVar(&accessLogs, "access-log", nil,
+		"Sidecar access log to read the traffic from instead of Prometheus, as <namespace>=<file>. May be repeated")
+	cmd.Flags().DurationVarP(&duration, "duration", "d", 7*24*time.Hour, "Duration of the traffic to query from Prometheus")
+	cmd.Flags().StringSliceVarP(&filenames, "filename", "f", nil,
+		"Input files with the Istio configuration and Kubernetes Services used to estimate the savings, instead of the cluster")
+	cmd.Flags().BoolVar(&estimate, "estimate", true, "Estimate the configuration size savings of each recommended Sidecar")
+	return cmd
+}
+
+func readAccessLogs(accessLogs []string) ([]Edge, error) {
+	var edges []Edge
+	for _, a := range accessLogs {
+		ns, file, ok := strings.Cut(a, "=")
+		if !ok || ns == "" || file == "" {
+			return nil, fmt.Errorf("invalid access log %q, must be <namespace>=<file>", a)
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		e, err := EdgesFromAccessLog(f, ns, constants.DefaultClusterLocalDomain)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read access log %s: %v", file, err)
+		}
+		edges = append(edges, e...)
+	}
+	return edges, nil
+}
+
+func queryPrometheus(ctx cli.Context, address string, duration time.Duration) ([]Edge, error) {
+	if address == "" {
+		client, err := ctx.CLIClient()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create k8s client: %v", err)
+		}
+		pl, err := client.PodsForSelector(context.TODO(), ctx.IstioNamespace(), "app.kubernetes.io/name=prometheus")
+		if err != nil {
+			return nil, fmt.Errorf("not able to locate Prometheus pod: %v", err)
+		}
+		if len(pl.Items) < 1 {
+			return nil, errors.New("no Prometheus pods found, use --prometheus-address or --access-log")
+		}
+		fw, err := client.NewPortForwarder(pl.Items[0].Name, ctx.IstioNamespace(), "", 0, 9090)
+		if err != nil {
+			return nil, fmt.Errorf("could not build port forwarder for prometheus: %v", err)
+		}
+		if err = fw.Start(); err != nil {
+			return nil, fmt.Errorf("failure running port forward process: %v", err)
+		}
+		defer fw.Close()
+		go dashboard.ClosePortForwarderOnInterrupt(fw)
+		address = "http://" + fw.Address()
+	}
+
+	client, err := api.NewClient(api.Config{Address: address})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Prometheus client: %v", err)
+	}
+	return EdgesFromPrometheus(context.Background(), promv1.NewAPI(client), duration)
+}
+
+func filterNamespace(sidecars []config.Config, namespace string) []config.Config {
+	for _, s := range sidecars {
+		if s.Namespace == namespace {
+			return []config.Config{s}
+		}
+	}
+	return nil
+}
+
+func estimateSavings(ctx cli.Context, filenames []string, sidecars []config.Config) ([]*Savings, error) {
+	var in *configgen.Inputs
+	var err error
+	if len(filenames) > 0 {
+		in, err = configgen.ReadInputs(filenames)
+	} else {
+		client, cerr := ctx.CLIClient()
+		if cerr != nil {
+			return nil, fmt.Errorf("failed to create k8s client: %v", cerr)
+		}
+		in, err = readClusterInputs(context.Background(), client)
+	}
+	if err != nil {
+		return nil, err
+	}
+	savings := make([]*Savings, 0, len(sidecars))
+	for _, s := range sidecars {
+		log.Debugf("estimating the savings of the Sidecar in namespace %s", s.Namespace)
+		sv, err := EstimateSavings(in, s)
+		if err != nil {
+			return nil, err
+		}
+		savings = append(savings, sv)
+	}
+	return savings, nil
+}
+
+// printRecommendations writes the Sidecars as a YAML stream, with the estimated savings as comments.
+func printRecommendations(w io.Writer, sidecars []config.Config, savings []*Savings) error {
+	for i, s := range sidecars {
+		obj, err := crd.ConvertConfig(s)
+		if err != nil {
+			return err
+		}
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(w, "---")
+		}
+		if savings != nil {
+			sv := savings[i]
+			fmt.Fprintf(w, "# Estimated sidecar configuration size in namespace %s:\n", sv.Namespace)
+			fmt.Fprintf(w, "#   services: %d -> %d\n", sv.Current.Services, sv.Proposed.Services)
+			fmt.Fprintf(w, "#   clusters: %d -> %d\n", sv.Current.Clusters, sv.Proposed.Clusters)
+			fmt.Fprintf(w, "#   bytes:    %d -> %d\n", sv.Current.Bytes, sv.Proposed.Bytes)
+		}
+		fmt.Fprint(w, string(b))
+	}
+	return nil
+}
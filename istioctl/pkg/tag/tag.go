@@ -324,6 +324,35 @@ func setTag(ctx context.Context, kubeClient kube.CLIClient, tagName, revision, i
 	return nil
 }
 
+// MoveTag points an existing revision tag at another revision, keeping the name of its webhook. Unlike setTag, it
+// never prompts, as it is used to automate upgrades.
+func MoveTag(ctx context.Context, kubeClient kube.CLIClient, tagName, revision, istioNS string, w io.Writer) error {
+	whs, err := GetWebhooksWithTag(ctx, kubeClient.Kube(), tagName)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve tag with name %s: %v", tagName, err)
+	}
+	if len(whs) == 0 {
+		return fmt.Errorf("revision tag %q does not exist", tagName)
+	}
+	opts := &GenerateOptions{
+		Tag:            tagName,
+		Revision:       revision,
+		WebhookName:    whs[0].Name,
+		Overwrite:      true,
+		UserManaged:    true,
+		IstioNamespace: istioNS,
+	}
+	tagYAML, err := Generate(ctx, kubeClient, opts)
+	if err != nil {
+		return err
+	}
+	if err := Create(kubeClient, tagYAML, istioNS); err != nil {
+		return fmt.Errorf("failed to apply tag webhook MutatingWebhookConfiguration to cluster: %v", err)
+	}
+	fmt.Fprintf(w, "Revision tag %q now references control plane revision %q\n", tagName, revision)
+	return nil
+}
+
 func analyzeWebhook(name, istioNamespace, wh, revision string, config *rest.Config) error {
 	sa := local.NewSourceAnalyzer(analysis.Combine("webhook", &webhook.Analyzer{}), "", resource.Namespace(istioNamespace), nil)
 	if err := sa.AddReaderKubeSource([]local.ReaderSource{{Name: "", Reader: strings.NewReader(wh)}}); err != nil {
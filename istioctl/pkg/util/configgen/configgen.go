@@ -0,0 +1,165 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package configgen generates the configuration of a proxy offline, from Istio configuration and Kubernetes
+// Services, using the same config generator as the pilot tests.
+package configgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pilot/pkg/config/kube/crd"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/networking/core"
+	"istio.io/istio/pilot/pkg/serviceregistry/kube"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/test"
+)
+
+// Inputs is the offline environment configuration is generated in.
+type Inputs struct {
+	Configs  []config.Config
+	Services []*corev1.Service
+	Mesh     *meshconfig.MeshConfig
+}
+
+// ReadInputs reads Istio configuration and Kubernetes Services from the given YAML files.
+// Other Kubernetes resources are ignored.
+func ReadInputs(files []string) (*Inputs, error) {
+	in := &Inputs{}
+	now := time.Now()
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		configs, others, err := crd.ParseInputs(string(b))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", f, err)
+		}
+		for _, c := range configs {
+			if c.Namespace == "" {
+				c.Namespace = "default"
+			}
+			if c.CreationTimestamp.IsZero() {
+				c.CreationTimestamp = now
+			}
+			in.Configs = append(in.Configs, c)
+		}
+		for _, o := range others {
+			if o.Kind != gvk.Service.Kind || o.APIVersion != gvk.Service.GroupVersion() {
+				continue
+			}
+			svc := &corev1.Service{TypeMeta: o.TypeMeta, ObjectMeta: o.ObjectMeta}
+			if err := json.Unmarshal(o.Spec, &svc.Spec); err != nil {
+				return nil, fmt.Errorf("failed to read Service %s in %s: %v", o.Name, f, err)
+			}
+			if svc.Namespace == "" {
+				svc.Namespace = "default"
+			}
+			in.Services = append(in.Services, svc)
+		}
+	}
+	return in, nil
+}
+
+// ProxyOptions describes the proxy to generate configuration for.
+type ProxyOptions struct {
+	Type         model.NodeType
+	Namespace    string
+	Labels       map[string]string
+	IP           string
+	IstioVersion string
+}
+
+func (o ProxyOptions) String() string {
+	return fmt.Sprintf("%s in namespace %s", o.Type, o.Namespace)
+}
+
+// Generate builds a config generator from the Services of the inputs and the given configs, sets up the proxy
+// described by opts, and calls fn with them. The proxy is a member of the Services in its namespace which select
+// its labels.
+func Generate(in *Inputs, configs []config.Config, opts ProxyOptions, fn func(cg *core.ConfigGenTest, p *model.Proxy)) error {
+	err := test.Wrap(func(t test.Failer) {
+		services := make([]*model.Service, 0, len(in.Services))
+		var instances []*model.ServiceInstance
+		for _, s := range in.Services {
+			svc := kube.ConvertService(*s, nil, constants.DefaultClusterLocalDomain, constants.DefaultClusterName, constants.DefaultClusterLocalDomain)
+			services = append(services, svc)
+			instances = append(instances, proxyInstances(s, svc, opts)...)
+		}
+		cg := core.NewConfigGenTest(t, core.TestOptions{
+			Configs:    configs,
+			Services:   services,
+			Instances:  instances,
+			MeshConfig: in.Mesh,
+		})
+		p := cg.SetupProxy(&model.Proxy{
+			Type:            opts.Type,
+			ConfigNamespace: opts.Namespace,
+			Labels:          opts.Labels,
+			IPAddresses:     []string{opts.IP},
+			Metadata: &model.NodeMetadata{
+				Namespace:    opts.Namespace,
+				Labels:       opts.Labels,
+				IstioVersion: opts.IstioVersion,
+			},
+		})
+		fn(cg, p)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate proxy configuration: %v", err)
+	}
+	return nil
+}
+
+// proxyInstances returns the instances of the Service running on the proxy, if the Service selects it.
+func proxyInstances(s *corev1.Service, svc *model.Service, opts ProxyOptions) []*model.ServiceInstance {
+	if s.Namespace != opts.Namespace || len(s.Spec.Selector) == 0 ||
+		!klabels.SelectorFromSet(s.Spec.Selector).Matches(klabels.Set(opts.Labels)) {
+		return nil
+	}
+	var out []*model.ServiceInstance
+	for _, kp := range s.Spec.Ports {
+		port, f := svc.Ports.GetByPort(int(kp.Port))
+		if !f {
+			continue
+		}
+		targetPort := kp.Port
+		if kp.TargetPort.IntVal != 0 {
+			targetPort = kp.TargetPort.IntVal
+		}
+		out = append(out, &model.ServiceInstance{
+			Service:     svc,
+			ServicePort: port,
+			Endpoint: &model.IstioEndpoint{
+				Addresses:       []string{opts.IP},
+				EndpointPort:    uint32(targetPort),
+				ServicePortName: port.Name,
+				Labels:          opts.Labels,
+				Namespace:       opts.Namespace,
+			},
+		})
+	}
+	return out
+}
@@ -28,14 +28,18 @@ import (
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	gateway "sigs.k8s.io/gateway-api/apis/v1"
 	"sigs.k8s.io/yaml"
 
+	"istio.io/api/annotation"
 	"istio.io/api/label"
 	"istio.io/istio/istioctl/pkg/cli"
+	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pilot/pkg/model/kstatus"
+	"istio.io/istio/pilot/pkg/serviceregistry/ambient"
 	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/protocol"
 	"istio.io/istio/pkg/config/schema/gvk"
@@ -174,6 +178,10 @@ func Cmd(ctx cli.Context) *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("failed to print waypoint status: %v", err)
 			}
+			err = printWaypointShards(w, kubeClient, filteredGws, ns)
+			if err != nil {
+				return fmt.Errorf("failed to print waypoint shards: %v", err)
+			}
 			return w.Flush()
 		},
 	}
@@ -649,3 +657,80 @@ func printWaypointStatus(w *tabwriter.Writer, kubeClient kube.CLIClient, gws []g
 	}
 	return w.Flush()
 }
+
+// printWaypointShards prints the Services assigned to each shard of the sharded waypoints.
+func printWaypointShards(w *tabwriter.Writer, kubeClient kube.CLIClient, gws []gateway.Gateway, ns string) error {
+	sharded := slices.FilterInPlace(slices.Clone(gws), func(gw gateway.Gateway) bool {
+		return model.WaypointShards(gw.Annotations) > 0
+	})
+	if len(sharded) == 0 {
+		return nil
+	}
+	// Services may use a waypoint of another namespace, so look them up in all namespaces.
+	services, err := kubeClient.Kube().CoreV1().Services(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	namespaces, err := kubeClient.Kube().CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	nsMeta := map[string]metav1.ObjectMeta{}
+	for _, n := range namespaces.Items {
+		nsMeta[n.Name] = n.ObjectMeta
+	}
+
+	fmt.Fprintln(w)
+	if ns == "" {
+		fmt.Fprintln(w, "NAMESPACE\tWAYPOINT\tSHARD\tSERVICES")
+	} else {
+		fmt.Fprintln(w, "WAYPOINT\tSHARD\tSERVICES")
+	}
+	for _, gw := range sharded {
+		shards := model.WaypointShards(gw.Annotations)
+		assigned := make([][]string, shards)
+		for _, svc := range services.Items {
+			if !usesWaypoint(svc, nsMeta[svc.Namespace], gw) {
+				continue
+			}
+			shard := model.WaypointShardFor(svc.Namespace, svc.Name, svc.Labels, shards)
+			assigned[shard] = append(assigned[shard], svc.Namespace+"/"+svc.Name)
+		}
+		name := gw.Name
+		if override, f := gw.Annotations[annotation.GatewayNameOverride.Name]; f {
+			name = override
+		}
+		for i, svcs := range assigned {
+			list := "<none>"
+			if len(svcs) > 0 {
+				list = strings.Join(slices.Sort(svcs), ",")
+			}
+			if ns == "" {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", gw.Namespace, gw.Name, model.WaypointShardName(name, i), list)
+			} else {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", gw.Name, model.WaypointShardName(name, i), list)
+			}
+		}
+	}
+	return w.Flush()
+}
+
+// usesWaypoint returns whether a Service is bound to a waypoint, from its use-waypoint label or the one of its
+// namespace.
+func usesWaypoint(svc corev1.Service, ns metav1.ObjectMeta, gw gateway.Gateway) bool {
+	// Waypoints do not use waypoints
+	if svc.Labels[label.GatewayManaged.Name] == constants.ManagedGatewayMeshControllerLabel {
+		return false
+	}
+	if c := meta.FindStatusCondition(svc.Status.Conditions, string(model.WaypointBound)); c != nil && c.Status != metav1.ConditionTrue {
+		return false
+	}
+	wp, isNone := ambient.GetUseWaypoint(svc.ObjectMeta, svc.Namespace)
+	if isNone {
+		return false
+	}
+	if wp == nil {
+		wp, _ = ambient.GetUseWaypoint(ns, svc.Namespace)
+	}
+	return wp != nil && wp.Name == gw.Name && wp.Namespace == gw.Namespace
+}
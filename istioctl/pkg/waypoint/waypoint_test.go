@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	gateway "sigs.k8s.io/gateway-api/apis/v1"
 
@@ -123,6 +124,7 @@ func TestWaypointStatus(t *testing.T) {
 		name            string
 		args            []string
 		gateways        []*gateway.Gateway
+		services        []*corev1.Service
 		expectedOutFile string
 	}{
 		{
@@ -159,6 +161,30 @@ func TestWaypointStatus(t *testing.T) {
 			},
 			expectedOutFile: "waypoint-status-notready",
 		},
+		{
+			name: "sharded waypoint",
+			args: strings.Split("status", " "),
+			gateways: []*gateway.Gateway{
+				makeShardedGateway(constants.DefaultNamespaceWaypoint, "default", "3"),
+			},
+			services: []*corev1.Service{
+				makeService("productpage", "default", map[string]string{label.IoIstioUseWaypoint.Name: "waypoint"}),
+				makeService("reviews", "default", map[string]string{
+					label.IoIstioUseWaypoint.Name: "waypoint",
+					constants.WaypointShardLabel:  "0",
+				}),
+				makeService("ratings", "default", map[string]string{
+					label.IoIstioUseWaypoint.Name: "waypoint",
+					constants.WaypointShardLabel:  "1",
+				}),
+				makeService("details", "default", nil),
+				makeService("waypoint", "default", map[string]string{
+					label.IoIstioUseWaypoint.Name: "waypoint",
+					label.GatewayManaged.Name:     constants.ManagedGatewayMeshControllerLabel,
+				}),
+			},
+			expectedOutFile: "waypoint-status-sharded",
+		},
 	}
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
@@ -173,6 +199,9 @@ func TestWaypointStatus(t *testing.T) {
 			for _, gw := range tt.gateways {
 				_, _ = client.GatewayAPI().GatewayV1().Gateways(gw.Namespace).Create(context.Background(), gw, metav1.CreateOptions{})
 			}
+			for _, svc := range tt.services {
+				_, _ = client.Kube().CoreV1().Services(svc.Namespace).Create(context.Background(), svc, metav1.CreateOptions{})
+			}
 			defaultFile, err := os.ReadFile(fmt.Sprintf("testdata/waypoint/%s", tt.expectedOutFile))
 			if err != nil {
 				t.Fatal(err)
@@ -232,6 +261,22 @@ func makeGateway(name, namespace string, programmed, isWaypoint bool) *gateway.G
 	}
 }
 
+func makeShardedGateway(name, namespace, shards string) *gateway.Gateway {
+	gw := makeGateway(name, namespace, true, true)
+	gw.Annotations = map[string]string{constants.WaypointShardsAnnotation: shards}
+	return gw
+}
+
+func makeService(name, namespace string, labels map[string]string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+	}
+}
+
 func makeGatewayWithRevision(name, namespace string, programmed, isWaypoint bool, rev string) *gateway.Gateway {
 	gw := makeGateway(name, namespace, programmed, isWaypoint)
 	if gw.Labels == nil {
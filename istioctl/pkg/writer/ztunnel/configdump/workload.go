@@ -92,7 +92,7 @@ func (c *ConfigWriter) PrintWorkloadSummary(filter WorkloadFilter) error {
 		return iNode < jNode
 	})
 
-	fmt.Fprintln(w, "NAMESPACE\tPOD NAME\tADDRESS\tNODE\tWAYPOINT\tPROTOCOL")
+	fmt.Fprintln(w, "NAMESPACE\tPOD NAME\tADDRESS\tNODE\tWAYPOINT\tPROTOCOL\tCAPACITY")
 
 	for _, wl := range verifiedWorkloads {
 		address := strings.Join(wl.WorkloadIPs, ",")
@@ -100,8 +100,10 @@ func (c *ConfigWriter) PrintWorkloadSummary(filter WorkloadFilter) error {
 			address = wl.Hostname
 		}
 		waypoint := waypointName(wl, zDump.Services)
-		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n",
-			wl.Namespace, wl.Name, address, wl.Node, waypoint, wl.Protocol)
+		// ztunnel defaults the capacity of a workload to 1
+		capacity := max(wl.Capacity, 1)
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\n",
+			wl.Namespace, wl.Name, address, wl.Node, waypoint, wl.Protocol, capacity)
 
 	}
 	return w.Flush()
@@ -0,0 +1,532 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ztunnelconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/yaml"
+
+	"istio.io/api/annotation"
+	"istio.io/api/label"
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/istioctl/pkg/cli"
+	"istio.io/istio/istioctl/pkg/util"
+	ambientutil "istio.io/istio/istioctl/pkg/util/ambient"
+	"istio.io/istio/pilot/pkg/features"
+	pilotmodel "istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/serviceregistry/ambient"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/maps"
+	"istio.io/istio/pkg/ptr"
+	"istio.io/istio/pkg/slices"
+	"istio.io/istio/pkg/test/util/yml"
+	"istio.io/istio/pkg/util/protomarshal"
+	"istio.io/istio/pkg/util/sets"
+	"istio.io/istio/pkg/workloadapi"
+)
+
+const (
+	// previewPodCIDR and previewServiceCIDR are the ranges placeholder addresses are assigned from, for Pods and
+	// Services that have none in the files.
+	previewPodCIDR     = "10.244.0.0/16"
+	previewServiceCIDR = "10.96.0.0/16"
+	previewPodSuffix   = "preview"
+)
+
+// ExperimentalZtunnelConfig holds the experimental ztunnel-config commands.
+func ExperimentalZtunnelConfig(ctx cli.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "ztunnel-config",
+		Short:   "Preview Ztunnel configuration.",
+		Long:    "A group of experimental commands used to preview Ztunnel configuration.",
+		Aliases: []string{"zc"},
+	}
+	cmd.AddCommand(previewCmd(ctx))
+	return cmd
+}
+
+func previewCmd(ctx cli.Context) *cobra.Command {
+	var (
+		files          []string
+		meshConfigFile string
+		revision       string
+		outputFormat   string
+	)
+	cmd := &cobra.Command{
+		Use:   "preview",
+		Short: "Preview the configuration Ztunnel receives for resources in files.",
+		Long: `Preview builds the workloads, services and authorization policies Istiod sends to Ztunnel from the resources in
+files, without a cluster, so that changes to ambient configuration can be reviewed before they are applied.
+
+The Namespaces, Pods, Services, ServiceEntries, WorkloadEntries, Gateways, GatewayClasses, AuthorizationPolicies and
+PeerAuthentications in the files are used. One ready Pod is created for each Deployment, StatefulSet, DaemonSet,
+ReplicaSet and Job, and the Service and Pod Istiod deploys are created for each waypoint Gateway. Pods and Services
+without addresses are given placeholder addresses, from ` + previewPodCIDR + ` and ` + previewServiceCIDR + `
+respectively.`,
+		Example: `  # Preview the configuration of the manifests in a directory
+  istioctl x ztunnel-config preview -f manifests/
+
+  # Preview the full configuration, as sent to Ztunnel
+  istioctl x ztunnel-config preview -f manifests/ -o yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			if len(files) == 0 {
+				return fmt.Errorf("no files provided, use -f to provide the resources to preview")
+			}
+			objects, err := readPreviewObjects(files)
+			if err != nil {
+				return err
+			}
+			meshConfig := mesh.DefaultMeshConfig()
+			if meshConfigFile != "" {
+				if meshConfig, err = mesh.ReadMeshConfig(meshConfigFile); err != nil {
+					return err
+				}
+			}
+			res, err := preview(objects, meshConfig, revision, ctx.IstioNamespace())
+			if err != nil {
+				return err
+			}
+			switch outputFormat {
+			case summaryOutput:
+				return printPreviewSummary(c.OutOrStdout(), res)
+			case jsonOutput, yamlOutput:
+				return printPreviewDump(c.OutOrStdout(), res, outputFormat)
+			default:
+				return fmt.Errorf("output format %q not supported", outputFormat)
+			}
+		},
+	}
+	cmd.Flags().StringSliceVarP(&files, "filename", "f", nil, "Files or directories with the resources to preview")
+	cmd.Flags().StringVar(&meshConfigFile, "meshConfigFile", "", "Mesh configuration filename. Defaults to the default mesh configuration")
+	cmd.Flags().StringVarP(&revision, "revision", "r", "", "Revision of Istiod to preview the configuration of")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", summaryOutput, "Output format: one of json|yaml|short")
+	cmd.Long += "\n\n" + util.ExperimentalMsg
+	return cmd
+}
+
+func preview(objects []runtime.Object, meshConfig *meshconfig.MeshConfig, revision, istioNamespace string) (*ambient.PreviewResult, error) {
+	return ambient.Preview(ambient.PreviewOptions{
+		Objects:         objects,
+		MeshConfig:      meshConfig,
+		Revision:        revision,
+		SystemNamespace: istioNamespace,
+		DomainSuffix:    constants.DefaultClusterLocalDomain,
+		ClusterID:       constants.DefaultClusterName,
+		Flags: ambient.FeatureFlags{
+			DefaultAllowFromWaypoint:              features.DefaultAllowFromWaypoint,
+			EnableK8SServiceSelectWorkloadEntries: features.EnableK8SServiceSelectWorkloadEntries,
+		},
+	})
+}
+
+// readPreviewObjects reads the resources of files and directories, creating the Pods of workload controllers and the
+// Namespaces the resources are in. Resources that do not affect Ztunnel configuration are ignored.
+func readPreviewObjects(paths []string) ([]runtime.Object, error) {
+	var files []string
+	for _, p := range paths {
+		err := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			ext := filepath.Ext(path)
+			if !d.IsDir() && (path == p || ext == ".yaml" || ext == ".yml" || ext == ".json") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var objects []runtime.Object
+	namespaces := sets.New[string]()
+	referenced := sets.New[string]()
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range yml.SplitString(string(b)) {
+			if strings.TrimSpace(doc) == "" {
+				continue
+			}
+			obj, _, err := kube.IstioCodec.UniversalDeserializer().Decode([]byte(doc), nil, nil)
+			if err != nil {
+				// Custom resources of other APIs cannot be decoded, and do not affect Ztunnel.
+				continue
+			}
+			if ns, ok := obj.(*corev1.Namespace); ok {
+				namespaces.Insert(ns.Name)
+				objects = append(objects, ns)
+				continue
+			}
+			m, ok := obj.(metav1.Object)
+			if !ok {
+				continue
+			}
+			if gc, ok := obj.(*gatewayv1.GatewayClass); ok {
+				objects = append(objects, gc)
+				continue
+			}
+			if m.GetNamespace() == "" {
+				m.SetNamespace(metav1.NamespaceDefault)
+			}
+			if pod := workloadPod(obj); pod != nil {
+				obj = pod
+			}
+			referenced.Insert(m.GetNamespace())
+			objects = append(objects, obj)
+		}
+	}
+	for _, ns := range sets.SortedList(referenced.Difference(namespaces)) {
+		objects = append(objects, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}})
+	}
+	objects = addWaypoints(objects)
+	assignPreviewAddresses(objects)
+	enrollAmbientPods(objects)
+	return objects, nil
+}
+
+// enrollAmbientPods marks the Pods in ambient data plane mode as enrolled, as the CNI would.
+func enrollAmbientPods(objects []runtime.Object) {
+	ambientNamespaces := sets.New[string]()
+	for _, obj := range objects {
+		if ns, ok := obj.(*corev1.Namespace); ok && ambientutil.InAmbient(ns) {
+			ambientNamespaces.Insert(ns.Name)
+		}
+	}
+	for _, obj := range objects {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || pod.Annotations[annotation.AmbientRedirection.Name] != "" {
+			continue
+		}
+		if _, f := pod.Annotations[annotation.SidecarStatus.Name]; f {
+			continue
+		}
+		mode, f := pod.Labels[label.IoIstioDataplaneMode.Name]
+		if mode == constants.DataplaneModeAmbient || (!f && ambientNamespaces.Contains(pod.Namespace)) {
+			pod.Annotations = maps.MergeCopy(pod.Annotations, map[string]string{
+				annotation.AmbientRedirection.Name: constants.AmbientRedirectionEnabled,
+			})
+		}
+	}
+}
+
+// addWaypoints adds the resources Istiod creates for waypoints: the built-in waypoint GatewayClass, and the Service,
+// ready Pod and status address of each waypoint Gateway.
+func addWaypoints(objects []runtime.Object) []runtime.Object {
+	meshClasses := sets.New(constants.WaypointGatewayClassName)
+	classes := sets.New[string]()
+	services := sets.New[types.NamespacedName]()
+	for _, obj := range objects {
+		switch o := obj.(type) {
+		case *gatewayv1.GatewayClass:
+			classes.Insert(o.Name)
+			if o.Spec.ControllerName == constants.ManagedGatewayMeshController {
+				meshClasses.Insert(o.Name)
+			} else {
+				meshClasses.Delete(o.Name)
+			}
+		case *corev1.Service:
+			services.Insert(config.NamespacedName(o))
+		}
+	}
+	if !classes.Contains(constants.WaypointGatewayClassName) {
+		objects = append(objects, &gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: constants.WaypointGatewayClassName},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: constants.ManagedGatewayMeshController},
+		})
+	}
+	for _, obj := range objects {
+		gw, ok := obj.(*gatewayv1.Gateway)
+		if !ok || !meshClasses.Contains(string(gw.Spec.GatewayClassName)) || len(gw.Status.Addresses) > 0 {
+			continue
+		}
+		gw.Status.Addresses = []gatewayv1.GatewayStatusAddress{{
+			Type:  ptr.Of(gatewayv1.HostnameAddressType),
+			Value: fmt.Sprintf("%s.%s.svc.%s", gw.Name, gw.Namespace, constants.DefaultClusterLocalDomain),
+		}}
+		selector := map[string]string{label.IoK8sNetworkingGatewayGatewayName.Name: gw.Name}
+		if !services.Contains(config.NamespacedName(gw)) {
+			objects = append(objects, &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: gw.Name, Namespace: gw.Namespace, Labels: selector},
+				Spec: corev1.ServiceSpec{
+					Selector: selector,
+					Ports: []corev1.ServicePort{
+						{Name: "status-port", Port: 15021, AppProtocol: ptr.Of("tcp")},
+						{Name: "mesh", Port: 15008, AppProtocol: ptr.Of("all")},
+					},
+				},
+			})
+		}
+		objects = append(objects, workloadPod(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: gw.Name, Namespace: gw.Namespace},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: maps.MergeCopy(selector, map[string]string{
+					label.GatewayManaged.Name:       constants.ManagedGatewayMeshControllerLabel,
+					label.IoIstioDataplaneMode.Name: constants.DataplaneModeNone,
+				})},
+				Spec: corev1.PodSpec{ServiceAccountName: gw.Name},
+			}},
+		}))
+	}
+	return objects
+}
+
+// workloadPod returns a Pod of a workload controller, named and owned like the Pods created by Kubernetes so that
+// its workload name is the name of the controller.
+func workloadPod(obj runtime.Object) *corev1.Pod {
+	var owner metav1.OwnerReference
+	var template corev1.PodTemplateSpec
+	var meta metav1.ObjectMeta
+	name := ""
+	podLabels := map[string]string{}
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		return o
+	case *appsv1.Deployment:
+		meta, template = o.ObjectMeta, o.Spec.Template
+		owner = metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: o.Name + "-" + previewPodSuffix}
+		podLabels["pod-template-hash"] = previewPodSuffix
+		name = owner.Name + "-0"
+	case *appsv1.StatefulSet:
+		meta, template = o.ObjectMeta, o.Spec.Template
+		owner = metav1.OwnerReference{APIVersion: "apps/v1", Kind: "StatefulSet", Name: o.Name}
+		name = o.Name + "-0"
+	case *appsv1.DaemonSet:
+		meta, template = o.ObjectMeta, o.Spec.Template
+		owner = metav1.OwnerReference{APIVersion: "apps/v1", Kind: "DaemonSet", Name: o.Name}
+		name = o.Name + "-" + previewPodSuffix
+	case *appsv1.ReplicaSet:
+		meta, template = o.ObjectMeta, o.Spec.Template
+		owner = metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: o.Name}
+		name = o.Name + "-" + previewPodSuffix
+	case *batchv1.Job:
+		meta, template = o.ObjectMeta, o.Spec.Template
+		owner = metav1.OwnerReference{APIVersion: "batch/v1", Kind: "Job", Name: o.Name}
+		name = o.Name + "-" + previewPodSuffix
+	default:
+		return nil
+	}
+	owner.Controller = ptr.Of(true)
+	for k, v := range template.Labels {
+		podLabels[k] = v
+	}
+	pod := &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			GenerateName:    strings.TrimSuffix(name, "0"),
+			Namespace:       meta.Namespace,
+			Labels:          podLabels,
+			Annotations:     template.Annotations,
+			OwnerReferences: []metav1.OwnerReference{owner},
+		},
+		Spec: template.Spec,
+	}
+	if pod.Spec.ServiceAccountName == "" {
+		pod.Spec.ServiceAccountName = "default"
+	}
+	return pod
+}
+
+// assignPreviewAddresses gives ready placeholder addresses to the Pods and Services without one.
+func assignPreviewAddresses(objects []runtime.Object) {
+	podIP := netip.MustParsePrefix(previewPodCIDR).Addr()
+	serviceIP := netip.MustParsePrefix(previewServiceCIDR).Addr()
+	for _, obj := range objects {
+		switch o := obj.(type) {
+		case *corev1.Pod:
+			if o.Status.PodIP == "" && len(o.Status.PodIPs) == 0 && !o.Spec.HostNetwork {
+				podIP = podIP.Next()
+				o.Status.PodIP = podIP.String()
+			}
+			if o.Status.Phase == "" {
+				o.Status.Phase = corev1.PodRunning
+				o.Status.Conditions = append(o.Status.Conditions, corev1.PodCondition{
+					Type:   corev1.PodReady,
+					Status: corev1.ConditionTrue,
+				})
+			}
+		case *corev1.Service:
+			if o.Spec.ClusterIP == "" && o.Spec.Type != corev1.ServiceTypeExternalName {
+				serviceIP = serviceIP.Next()
+				o.Spec.ClusterIP = serviceIP.String()
+			}
+		}
+	}
+}
+
+func printPreviewSummary(out io.Writer, res *ambient.PreviewResult) error {
+	servicesByHostname := map[string]*workloadapi.Service{}
+	servicesByAddress := map[netip.Addr]*workloadapi.Service{}
+	endpoints := map[string]int{}
+	healthy := map[string]int{}
+	for _, svc := range res.Services {
+		servicesByHostname[svc.Service.Namespace+"/"+svc.Service.Hostname] = svc.Service
+		for _, a := range svc.Service.Addresses {
+			if ip, ok := netip.AddrFromSlice(a.Address); ok {
+				servicesByAddress[ip] = svc.Service
+			}
+		}
+	}
+	for _, wl := range res.Workloads {
+		for key := range wl.Workload.Services {
+			endpoints[key]++
+			if wl.Workload.Status == workloadapi.WorkloadStatus_HEALTHY {
+				healthy[key]++
+			}
+		}
+	}
+	waypointName := func(gw *workloadapi.GatewayAddress) string {
+		if gw == nil {
+			return "None"
+		}
+		var svc *workloadapi.Service
+		if h := gw.GetHostname(); h != nil {
+			svc = servicesByHostname[h.Namespace+"/"+h.Hostname]
+		} else if ip, ok := netip.AddrFromSlice(gw.GetAddress().GetAddress()); ok {
+			svc = servicesByAddress[ip]
+		}
+		if svc == nil {
+			return "Unknown"
+		}
+		return svc.Name
+	}
+
+	w := new(tabwriter.Writer).Init(out, 0, 8, 1, ' ', 0)
+	fmt.Fprintln(w, "WORKLOADS")
+	fmt.Fprintln(w, "NAMESPACE\tPOD NAME\tADDRESS\tWAYPOINT\tPROTOCOL")
+	for _, wl := range res.Workloads {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n", wl.Workload.Namespace, wl.Workload.Name, addresses(wl.Workload.Addresses),
+			waypointName(wl.Workload.Waypoint), wl.Workload.TunnelProtocol)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "SERVICES")
+	fmt.Fprintln(w, "NAMESPACE\tSERVICE NAME\tSERVICE VIP\tWAYPOINT\tENDPOINTS")
+	var bindingErrors []string
+	for _, svc := range res.Services {
+		s := svc.Service
+		vips := make([][]byte, 0, len(s.Addresses))
+		for _, a := range s.Addresses {
+			vips = append(vips, a.Address)
+		}
+		key := s.Namespace + "/" + s.Hostname
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%d/%d\n", s.Namespace, s.Name, addresses(vips), waypointName(s.Waypoint),
+			healthy[key], endpoints[key])
+		if e := svc.Waypoint.Error; e != nil {
+			bindingErrors = append(bindingErrors, fmt.Sprintf("%s/%s: %s: %s", s.Namespace, s.Name, e.Reason, e.Message))
+		}
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "POLICIES")
+	fmt.Fprintln(w, "NAMESPACE\tPOLICY NAME\tACTION\tSCOPE")
+	for _, pol := range res.Policies {
+		a := pol.Authorization
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", a.Namespace, a.Name, a.Action, a.Scope)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if len(bindingErrors) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Services not bound to their waypoint:")
+		for _, e := range bindingErrors {
+			fmt.Fprintln(out, "  "+e)
+		}
+	}
+	return nil
+}
+
+func addresses(addrs [][]byte) string {
+	res := make([]string, 0, len(addrs))
+	for _, a := range addrs {
+		if ip, ok := netip.AddrFromSlice(a); ok {
+			res = append(res, ip.String())
+		}
+	}
+	if len(res) == 0 {
+		return "None"
+	}
+	return strings.Join(res, ",")
+}
+
+// printPreviewDump prints the resources as they are sent to Ztunnel.
+func printPreviewDump(out io.Writer, res *ambient.PreviewResult, outputFormat string) error {
+	toJSON := func(msgs []proto.Message) ([]map[string]any, error) {
+		res := make([]map[string]any, 0, len(msgs))
+		for _, m := range msgs {
+			js, err := protomarshal.ToJSONMap(m)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, js)
+		}
+		return res, nil
+	}
+	dump := map[string]any{}
+	var err error
+	if dump["workloads"], err = toJSON(slices.Map(res.Workloads, func(wl pilotmodel.WorkloadInfo) proto.Message {
+		return wl.Workload
+	})); err != nil {
+		return err
+	}
+	if dump["services"], err = toJSON(slices.Map(res.Services, func(svc pilotmodel.ServiceInfo) proto.Message {
+		return svc.Service
+	})); err != nil {
+		return err
+	}
+	if dump["policies"], err = toJSON(slices.Map(res.Policies, func(pol pilotmodel.WorkloadAuthorization) proto.Message {
+		return pol.Authorization
+	})); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(dump, "", "    ")
+	if err != nil {
+		return err
+	}
+	if outputFormat == yamlOutput {
+		if b, err = yaml.JSONToYAML(b); err != nil {
+			return err
+		}
+	} else {
+		b = append(b, '\n')
+	}
+	_, err = out.Write(b)
+	return err
+}
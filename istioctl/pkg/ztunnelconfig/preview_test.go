@@ -0,0 +1,54 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ztunnelconfig
+
+import (
+	"bytes"
+	"testing"
+
+	"istio.io/istio/istioctl/pkg/cli"
+	"istio.io/istio/pilot/test/util"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestPreview(t *testing.T) {
+	cases := []struct {
+		name   string
+		args   []string
+		golden string
+	}{
+		{
+			name:   "summary",
+			args:   []string{"-f", "testdata/preview"},
+			golden: "testdata/preview.golden",
+		},
+		{
+			name:   "yaml",
+			args:   []string{"-f", "testdata/preview/bookinfo.yaml", "-f", "testdata/preview/policies.yaml", "-o", "yaml"},
+			golden: "testdata/preview.yaml.golden",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := ExperimentalZtunnelConfig(cli.NewFakeContext(&cli.NewFakeContextOption{IstioNamespace: "istio-system"}))
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(append([]string{"preview"}, tt.args...))
+			assert.NoError(t, cmd.Execute())
+			util.CompareContent(t, out.Bytes(), tt.golden)
+		})
+	}
+}
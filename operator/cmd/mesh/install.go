@@ -61,18 +61,21 @@ type InstallArgs struct {
 	ManifestsPath string
 	// Revision is the Istio control plane revision the command targets.
 	Revision string
+	// RollbackOnFailure restores the objects changed by the installation to their previous state if it fails.
+	RollbackOnFailure bool
 }
 
 func (a *InstallArgs) String() string {
 	var b strings.Builder
-	b.WriteString("InFilenames:      " + fmt.Sprint(a.InFilenames) + "\n")
-	b.WriteString("ReadinessTimeout: " + fmt.Sprint(a.ReadinessTimeout) + "\n")
-	b.WriteString("SkipConfirmation: " + fmt.Sprint(a.SkipConfirmation) + "\n")
-	b.WriteString("Force:            " + fmt.Sprint(a.Force) + "\n")
-	b.WriteString("Verify:           " + fmt.Sprint(a.Verify) + "\n")
-	b.WriteString("Set:              " + fmt.Sprint(a.Set) + "\n")
-	b.WriteString("ManifestsPath:    " + a.ManifestsPath + "\n")
-	b.WriteString("Revision:         " + a.Revision + "\n")
+	b.WriteString("InFilenames:       " + fmt.Sprint(a.InFilenames) + "\n")
+	b.WriteString("ReadinessTimeout:  " + fmt.Sprint(a.ReadinessTimeout) + "\n")
+	b.WriteString("SkipConfirmation:  " + fmt.Sprint(a.SkipConfirmation) + "\n")
+	b.WriteString("Force:             " + fmt.Sprint(a.Force) + "\n")
+	b.WriteString("Verify:            " + fmt.Sprint(a.Verify) + "\n")
+	b.WriteString("Set:               " + fmt.Sprint(a.Set) + "\n")
+	b.WriteString("ManifestsPath:     " + a.ManifestsPath + "\n")
+	b.WriteString("Revision:          " + a.Revision + "\n")
+	b.WriteString("RollbackOnFailure: " + fmt.Sprint(a.RollbackOnFailure) + "\n")
 	return b.String()
 }
 
@@ -87,6 +90,8 @@ func addInstallFlags(cmd *cobra.Command, args *InstallArgs) {
 	cmd.PersistentFlags().StringVarP(&args.ManifestsPath, "charts", "", "", ChartsDeprecatedStr)
 	cmd.PersistentFlags().StringVarP(&args.ManifestsPath, "manifests", "d", "", ManifestsFlagHelpStr)
 	cmd.PersistentFlags().StringVarP(&args.Revision, "revision", "r", "", revisionFlagHelpStr)
+	cmd.PersistentFlags().BoolVar(&args.RollbackOnFailure, "rollback-on-failure", false,
+		"If the installation fails or times out, restore every object it changed to its previous state and remove the objects it created.")
 }
 
 // InstallCmdWithArgs generates an Istio install manifest and applies it to a cluster
@@ -188,6 +193,7 @@ func Install(kubeClient kube.CLIClient, rootArgs *RootArgs, iArgs *InstallArgs,
 		Logger:         l,
 		Values:         vals,
 		ProgressLogger: progress.NewLog(),
+		Rollback:       iArgs.RollbackOnFailure,
 	}
 	if err := i.InstallManifests(manifests); err != nil {
 		return fmt.Errorf("failed to install manifests: %v", err)
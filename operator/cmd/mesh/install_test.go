@@ -0,0 +1,114 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mesh
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/yaml"
+
+	"istio.io/istio/operator/pkg/install"
+	"istio.io/istio/operator/pkg/render"
+	"istio.io/istio/operator/pkg/util/clog"
+	"istio.io/istio/operator/pkg/util/progress"
+	"istio.io/istio/pkg/config/schema/gvr"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestInstallRollback(t *testing.T) {
+	c := SetupFakeClient()
+	_, err := fakeControllerReconcileInternal(c, "minimal", liveCharts)
+	assert.NoError(t, err)
+
+	// The fake client only supports apply patches that create objects. Replace existing objects instead.
+	df := c.Dynamic().(*dynamicfake.FakeDynamicClient)
+	df.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		if _, err := df.Tracker().Get(patch.GetResource(), patch.GetNamespace(), patch.GetName()); err != nil {
+			return false, nil, nil
+		}
+		us := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(patch.GetPatch(), us); err != nil {
+			return true, nil, err
+		}
+		if err := df.Tracker().Update(patch.GetResource(), us, patch.GetNamespace()); err != nil {
+			return true, nil, err
+		}
+		o, err := df.Tracker().Get(patch.GetResource(), patch.GetNamespace(), patch.GetName())
+		return true, o, err
+	})
+
+	// Add an object owned by the installation that fails to be pruned, so the installation fails after all components
+	// are applied.
+	sas := c.Dynamic().Resource(gvr.ServiceAccount).Namespace("istio-system")
+	extra, err := sas.Get(context.Background(), "istiod", metav1.GetOptions{})
+	assert.NoError(t, err)
+	extra.SetName("istiod-old")
+	extra.SetResourceVersion("")
+	_, err = sas.Create(context.Background(), extra, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	df.PrependReactor("delete", "serviceaccounts",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.(k8stesting.DeleteAction).GetName() == "istiod-old" {
+				return true, nil, fmt.Errorf("injected error")
+			}
+			return false, nil, nil
+		})
+
+	// Change the Deployment, add a ConfigMap and remove the HorizontalPodAutoscaler.
+	manifests, vals, err := render.GenerateManifest([]string{inFileAbsolutePath("minimal")}, []string{
+		"installPackagePath=" + string(liveCharts),
+		"values.pilot.env.FOO=bar",
+		"values.pilot.jwksResolverExtraRootCA=cert",
+		"values.pilot.autoscaleEnabled=false",
+	}, false, c, nil)
+	assert.NoError(t, err)
+	installer := install.Installer{
+		SkipWait:       true,
+		Kube:           c,
+		Logger:         clog.NewDefaultLogger(),
+		Values:         vals,
+		ProgressLogger: progress.NewLog(),
+		Rollback:       true,
+	}
+	err = installer.InstallManifests(manifests)
+	assert.Error(t, err)
+	assert.Equal(t, strings.Contains(err.Error(), "rolled back to the previous installation"), true)
+
+	// The cluster should match the original installation again.
+	manifests, vals, err = render.GenerateManifest([]string{inFileAbsolutePath("minimal")}, []string{
+		"installPackagePath=" + string(liveCharts),
+	}, false, c, nil)
+	assert.NoError(t, err)
+	installer.Values = vals
+	drifts, err := installer.Drift(manifests)
+	assert.NoError(t, err)
+	assert.Equal(t, drifts, []install.Drift{{Object: "ServiceAccount/istio-system/istiod-old", Extra: true}})
+	_, err = c.Dynamic().Resource(gvr.ConfigMap).Namespace("istio-system").
+		Get(context.Background(), "pilot-jwks-extra-cacerts", metav1.GetOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,134 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mesh
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"istio.io/istio/istioctl/pkg/cli"
+	"istio.io/istio/operator/pkg/install"
+	"istio.io/istio/operator/pkg/render"
+	"istio.io/istio/operator/pkg/util/clog"
+	"istio.io/istio/pkg/config/labels"
+	"istio.io/istio/pkg/kube"
+)
+
+type ManifestDiffArgs struct {
+	// InFilenames is an array of paths to the input IstioOperator CR files.
+	InFilenames []string
+	// Set is a string with element format "path=value" where path is an IstioOperator path and the value is a
+	// value to set the node at that path to.
+	Set []string
+	// Force proceeds even if there are validation errors
+	Force bool
+	// ManifestsPath is a path to a charts and profiles directory in the local filesystem with a release tgz.
+	ManifestsPath string
+	// Revision is the Istio control plane revision the command targets.
+	Revision string
+}
+
+func addManifestDiffFlags(cmd *cobra.Command, args *ManifestDiffArgs) {
+	cmd.PersistentFlags().StringSliceVarP(&args.InFilenames, "filename", "f", nil, filenameFlagHelpStr)
+	cmd.PersistentFlags().StringArrayVarP(&args.Set, "set", "s", nil, setFlagHelpStr)
+	cmd.PersistentFlags().BoolVar(&args.Force, "force", false, ForceFlagHelpStr)
+	cmd.PersistentFlags().StringVarP(&args.ManifestsPath, "manifests", "d", "", ManifestsFlagHelpStr)
+	cmd.PersistentFlags().StringVarP(&args.Revision, "revision", "r", "", revisionFlagHelpStr)
+}
+
+func ManifestDiffCmd(ctx cli.Context, mdArgs *ManifestDiffArgs) *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff",
+		Short: "Compares an Istio install manifest to the installation in the cluster",
+		Long: `The diff subcommand generates an Istio install manifest and compares it to the objects installed in the cluster.
+Only fields set in the manifest are compared, so fields defaulted or managed by the cluster are not reported.
+Objects owned by the installation that are no longer in the manifest, and would be pruned on install, are reported as well.
+The command exits with a non-zero status if any drift is found.`,
+		Example: `  # Check a default Istio installation for drift
+  istioctl manifest diff
+
+  # Check an installation from an IstioOperator file for drift
+  istioctl manifest diff -f iop.yaml`,
+		Args: cobra.ExactArgs(0),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if !labels.IsDNS1123Label(mdArgs.Revision) && cmd.PersistentFlags().Changed("revision") {
+				return fmt.Errorf("invalid revision specified: %v", mdArgs.Revision)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeClient, err := ctx.CLIClient()
+			if err != nil {
+				return err
+			}
+			l := clog.NewConsoleLogger(cmd.OutOrStdout(), cmd.ErrOrStderr(), installerScope)
+			return ManifestDiff(kubeClient, mdArgs, cmd.OutOrStdout(), l)
+		},
+	}
+}
+
+// ManifestDiff compares the manifest generated from mdArgs to the installation in the cluster, returning an error if
+// they differ.
+func ManifestDiff(kubeClient kube.CLIClient, mdArgs *ManifestDiffArgs, w io.Writer, l clog.Logger) error {
+	setFlags := applyFlagAliases(mdArgs.Set, mdArgs.ManifestsPath, mdArgs.Revision)
+	manifests, vals, err := render.GenerateManifest(mdArgs.InFilenames, setFlags, mdArgs.Force, kubeClient, l)
+	if err != nil {
+		return fmt.Errorf("generate config: %v", err)
+	}
+	i := install.Installer{
+		Kube:   kubeClient,
+		Values: vals,
+		Logger: l,
+	}
+	drifts, err := i.Drift(manifests)
+	if err != nil {
+		return fmt.Errorf("failed to compare manifests: %v", err)
+	}
+	if len(drifts) == 0 {
+		fmt.Fprintln(w, "No drift detected.")
+		return nil
+	}
+	printDrift(w, drifts)
+	return fmt.Errorf("found drift in %d object(s)", len(drifts))
+}
+
+func printDrift(w io.Writer, drifts []install.Drift) {
+	for _, d := range drifts {
+		fmt.Fprintf(w, "%s:\n", d.Object)
+		switch {
+		case d.Missing:
+			fmt.Fprintln(w, "  missing from the cluster")
+		case d.Extra:
+			fmt.Fprintln(w, "  not in the manifest, would be pruned")
+		}
+		for _, f := range d.Fields {
+			fmt.Fprintf(w, "  %s: %s (cluster) != %s (manifest)\n", f.Path, driftValue(f.Live), driftValue(f.Desired))
+		}
+	}
+}
+
+func driftValue(v any) string {
+	if v == nil {
+		return "<unset>"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,86 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mesh
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"istio.io/istio/operator/pkg/manifest"
+	"istio.io/istio/operator/pkg/util/clog"
+	"istio.io/istio/pkg/config/schema/gvr"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestManifestDiff(t *testing.T) {
+	c := SetupFakeClient()
+	_, err := fakeControllerReconcileInternal(c, "minimal", liveCharts)
+	assert.NoError(t, err)
+
+	args := &ManifestDiffArgs{
+		InFilenames:   []string{inFileAbsolutePath("minimal")},
+		ManifestsPath: string(liveCharts),
+	}
+	diff := func() (string, error) {
+		out := &bytes.Buffer{}
+		err := ManifestDiff(c, args, out, clog.NewConsoleLogger(out, out, installerScope))
+		return out.String(), err
+	}
+
+	out, err := diff()
+	assert.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(out), "No drift detected.")
+
+	// Change a field, delete an object, and add an object owned by the installation.
+	deployments := c.Dynamic().Resource(gvr.Deployment).Namespace("istio-system")
+	istiod, err := deployments.Get(context.Background(), "istiod", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, unstructured.SetNestedField(istiod.Object, "50%", "spec", "strategy", "rollingUpdate", "maxSurge"))
+	_, err = deployments.Update(context.Background(), istiod, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, c.Dynamic().Resource(gvr.ConfigMap).Namespace("istio-system").
+		Delete(context.Background(), "istio", metav1.DeleteOptions{}))
+
+	extra, err := c.Dynamic().Resource(gvr.ServiceAccount).Namespace("istio-system").
+		Get(context.Background(), "istiod", metav1.GetOptions{})
+	assert.NoError(t, err)
+	extra.SetName("istiod-old")
+	extra.SetResourceVersion("")
+	_, err = c.Dynamic().Resource(gvr.ServiceAccount).Namespace("istio-system").
+		Create(context.Background(), extra, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	// Objects that are explicitly not pruned are not reported.
+	extra.SetName("istiod-kept")
+	extra.SetLabels(map[string]string{manifest.OwningResourceNotPruned: "true"})
+	_, err = c.Dynamic().Resource(gvr.ServiceAccount).Namespace("istio-system").
+		Create(context.Background(), extra, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	out, err = diff()
+	assert.Error(t, err)
+	assert.Equal(t, out, `ConfigMap/istio-system/istio:
+  missing from the cluster
+Deployment/istio-system/istiod:
+  spec.strategy.rollingUpdate.maxSurge: "50%" (cluster) != "100%" (manifest)
+ServiceAccount/istio-system/istiod-old:
+  not in the manifest, would be pruned
+`)
+}
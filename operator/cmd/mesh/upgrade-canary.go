@@ -0,0 +1,481 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mesh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"istio.io/api/label"
+	"istio.io/istio/istioctl/pkg/cli"
+	"istio.io/istio/istioctl/pkg/precheck"
+	"istio.io/istio/istioctl/pkg/tag"
+	"istio.io/istio/operator/pkg/install"
+	"istio.io/istio/operator/pkg/manifest"
+	"istio.io/istio/operator/pkg/uninstall"
+	"istio.io/istio/operator/pkg/util/clog"
+	"istio.io/istio/operator/pkg/util/progress"
+	"istio.io/istio/pkg/config/analysis/diag"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/proxy"
+	"istio.io/istio/pkg/slices"
+	"istio.io/istio/pkg/util/sets"
+)
+
+const (
+	// canaryCheckpointName is the name of the ConfigMap checkpointing a canary upgrade.
+	canaryCheckpointName = "istio-canary-upgrade"
+	// canaryCheckpointKey is the key of the canary upgrade state in the checkpoint ConfigMap.
+	canaryCheckpointKey = "state"
+	// restartedAtAnnotation is set on pod templates to restart workloads, like `kubectl rollout restart`.
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+)
+
+// canaryState is the progress of a canary upgrade. It is checkpointed after each step, so the upgrade can be resumed
+// or rolled back.
+type canaryState struct {
+	// From is the revision being upgraded from.
+	From string `json:"from"`
+	// To is the revision being upgraded to.
+	To string `json:"to"`
+	// Tags are the revision tags referencing From when the upgrade started.
+	Tags []string `json:"tags,omitempty"`
+	// TagsMoved records whether Tags reference To.
+	TagsMoved bool `json:"tagsMoved,omitempty"`
+	// Waves are the namespaces to migrate, in order.
+	Waves [][]string `json:"waves"`
+	// CompletedWaves is the number of Waves migrated and verified.
+	CompletedWaves int `json:"completedWaves"`
+}
+
+// canaryUpgrader runs the steps of a canary upgrade.
+type canaryUpgrader struct {
+	ctx            cli.Context
+	client         kube.CLIClient
+	istioNamespace string
+	timeout        time.Duration
+	dryRun         bool
+	out            io.Writer
+	l              clog.Logger
+	p              Printer
+	// verify checks the health of the mesh after the given namespaces were migrated away from a revision.
+	verify func(namespaces []string, from string) error
+}
+
+// CanaryUpgrade installs a new revision alongside the existing one and migrates namespaces to it in waves, or resumes
+// or rolls back such an upgrade.
+func CanaryUpgrade(ctx cli.Context, client kube.CLIClient, rootArgs *RootArgs, uArgs *upgradeArgs, stdOut io.Writer,
+	l clog.Logger, p Printer,
+) error {
+	u := &canaryUpgrader{
+		ctx:            ctx,
+		client:         client,
+		istioNamespace: ctx.IstioNamespace(),
+		timeout:        uArgs.ReadinessTimeout,
+		dryRun:         rootArgs.DryRun,
+		out:            stdOut,
+		l:              l,
+		p:              p,
+	}
+	u.verify = u.checkHealth
+
+	state, err := loadCanaryState(client, u.istioNamespace)
+	if err != nil {
+		return err
+	}
+	switch {
+	case uArgs.rollback:
+		if state == nil {
+			return fmt.Errorf("no canary upgrade to roll back")
+		}
+		return u.rollback(state)
+	case uArgs.resume:
+		if state == nil {
+			return fmt.Errorf("no canary upgrade to resume")
+		}
+		p.Printf("Resuming canary upgrade from revision %q to %q\n", state.From, state.To)
+	default:
+		if state != nil {
+			return fmt.Errorf("a canary upgrade from revision %q to %q is in progress, use --resume or --rollback",
+				state.From, state.To)
+		}
+		state, err = planCanary(client, l, uArgs.fromRevision, uArgs.Revision, uArgs.waveSize)
+		if err != nil {
+			return err
+		}
+		printCanaryPlan(p, state)
+		if u.dryRun {
+			return nil
+		}
+		if err := Install(client, rootArgs, uArgs.InstallArgs, stdOut, l, p); err != nil {
+			return err
+		}
+		if err := saveCanaryState(client, u.istioNamespace, state); err != nil {
+			return err
+		}
+	}
+	return u.run(state, uArgs.keepOldRevision)
+}
+
+// planCanary computes the revision tags and namespaces to migrate from one revision to another.
+func planCanary(client kube.CLIClient, l clog.Logger, from, to string, waveSize int) (*canaryState, error) {
+	ctx := context.Background()
+	whs, err := tag.GetRevisionWebhooks(ctx, client.Kube())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revision webhooks: %v", err)
+	}
+	tags := sets.New[string]()
+	for _, wh := range whs {
+		tagName := tag.GetWebhookTagName(wh)
+		if rev, _ := tag.GetWebhookRevision(wh); tagName != "" && rev == from {
+			tags.Insert(tagName)
+		}
+	}
+
+	namespaces, err := client.Kube().CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var migrate []string
+	for _, ns := range namespaces.Items {
+		rev, hasRev := ns.Labels[label.IoIstioRev.Name]
+		switch {
+		case hasRev && (rev == from || tags.Contains(rev)):
+			migrate = append(migrate, ns.Name)
+		case !hasRev && ns.Labels["istio-injection"] == "enabled":
+			if tags.Contains(tag.DefaultRevisionName) {
+				migrate = append(migrate, ns.Name)
+			} else if from == tag.DefaultRevisionName {
+				l.LogAndPrintf("Namespace %s uses istio-injection=enabled without a %q revision tag and will not be migrated",
+					ns.Name, tag.DefaultRevisionName)
+			}
+		}
+	}
+	migrate = slices.Sort(migrate)
+
+	state := &canaryState{
+		From:  from,
+		To:    to,
+		Tags:  sets.SortedList(tags),
+		Waves: [][]string{},
+	}
+	for len(migrate) > 0 {
+		n := min(waveSize, len(migrate))
+		state.Waves = append(state.Waves, migrate[:n])
+		migrate = migrate[n:]
+	}
+	return state, nil
+}
+
+func printCanaryPlan(p Printer, state *canaryState) {
+	p.Printf("Canary upgrade from revision %q to %q:\n", state.From, state.To)
+	p.Printf("  1. Install revision %q\n", state.To)
+	tags := "none"
+	if len(state.Tags) > 0 {
+		tags = strings.Join(state.Tags, ", ")
+	}
+	p.Printf("  2. Move revision tags to %q: %s\n", state.To, tags)
+	for i, wave := range state.Waves {
+		p.Printf("  %d. Migrate wave %d: %s\n", i+3, i+1, strings.Join(wave, ", "))
+	}
+	p.Printf("  %d. Remove revision %q\n", len(state.Waves)+3, state.From)
+}
+
+// run continues a canary upgrade from its state until it completes.
+func (u *canaryUpgrader) run(state *canaryState, keepOldRevision bool) error {
+	ctx := context.Background()
+	if !state.TagsMoved {
+		for _, t := range state.Tags {
+			if err := tag.MoveTag(ctx, u.client, t, state.To, u.istioNamespace, u.out); err != nil {
+				return u.interrupted(fmt.Errorf("failed to move revision tag %q: %v", t, err))
+			}
+		}
+		state.TagsMoved = true
+		if err := saveCanaryState(u.client, u.istioNamespace, state); err != nil {
+			return err
+		}
+	}
+
+	for i := state.CompletedWaves; i < len(state.Waves); i++ {
+		wave := state.Waves[i]
+		u.p.Printf("Migrating wave %d/%d to revision %q: %s\n", i+1, len(state.Waves), state.To, strings.Join(wave, ", "))
+		if err := u.migrateWave(wave, state.From, state.To, state.Tags); err != nil {
+			return u.interrupted(err)
+		}
+		if err := u.verify(wave, state.From); err != nil {
+			return u.interrupted(fmt.Errorf("wave %d is not healthy: %v", i+1, err))
+		}
+		state.CompletedWaves = i + 1
+		if err := saveCanaryState(u.client, u.istioNamespace, state); err != nil {
+			return err
+		}
+	}
+
+	if keepOldRevision {
+		u.p.Printf("Keeping revision %q\n", state.From)
+	} else if err := u.removeRevision(state.From); err != nil {
+		return u.interrupted(err)
+	}
+	if err := deleteCanaryState(u.client, u.istioNamespace); err != nil {
+		return err
+	}
+	u.p.Printf("Canary upgrade from revision %q to %q completed\n", state.From, state.To)
+	return nil
+}
+
+// rollback migrates all namespaces and tags back to the old revision and removes the new revision.
+func (u *canaryUpgrader) rollback(state *canaryState) error {
+	ctx := context.Background()
+	whs, err := tag.GetWebhooksWithRevision(ctx, u.client.Kube(), state.From)
+	if err != nil {
+		return err
+	}
+	if len(whs) == 0 {
+		return fmt.Errorf("revision %q was removed, the canary upgrade can no longer be rolled back", state.From)
+	}
+	u.p.Printf("Rolling back canary upgrade from revision %q to %q\n", state.From, state.To)
+
+	if state.TagsMoved {
+		for _, t := range state.Tags {
+			if err := tag.MoveTag(ctx, u.client, t, state.From, u.istioNamespace, u.out); err != nil {
+				return fmt.Errorf("failed to move revision tag %q: %v", t, err)
+			}
+		}
+		state.TagsMoved = false
+		if err := saveCanaryState(u.client, u.istioNamespace, state); err != nil {
+			return err
+		}
+	}
+
+	// Moving the tags affected namespaces of waves that were not migrated yet, so roll back every wave.
+	for i := len(state.Waves) - 1; i >= 0; i-- {
+		wave := state.Waves[i]
+		u.p.Printf("Rolling back wave %d/%d to revision %q: %s\n", i+1, len(state.Waves), state.From, strings.Join(wave, ", "))
+		if err := u.migrateWave(wave, state.To, state.From, state.Tags); err != nil {
+			return err
+		}
+		if err := u.verify(wave, state.To); err != nil {
+			return fmt.Errorf("wave %d is not healthy: %v", i+1, err)
+		}
+		state.CompletedWaves = min(state.CompletedWaves, i)
+		if err := saveCanaryState(u.client, u.istioNamespace, state); err != nil {
+			return err
+		}
+	}
+
+	if err := u.removeRevision(state.To); err != nil {
+		return err
+	}
+	if err := deleteCanaryState(u.client, u.istioNamespace); err != nil {
+		return err
+	}
+	u.p.Printf("Canary upgrade rolled back to revision %q\n", state.From)
+	return nil
+}
+
+func (u *canaryUpgrader) interrupted(err error) error {
+	return fmt.Errorf("%v\nThe canary upgrade was interrupted; fix the issue and continue it with --resume, "+
+		"or revert it with --rollback", err)
+}
+
+// migrateWave moves namespaces from one revision to another, restarts their workloads and waits for them to be ready.
+// Namespaces referencing a revision tag are not relabeled, as the tag itself is moved.
+func (u *canaryUpgrader) migrateWave(namespaces []string, from, to string, tags []string) error {
+	var workloads []manifest.Manifest
+	for _, ns := range namespaces {
+		if err := relabelNamespace(u.client, ns, from, to, tags); err != nil {
+			return fmt.Errorf("failed to relabel namespace %s: %v", ns, err)
+		}
+		restarted, err := u.restartWorkloads(ns, from)
+		if err != nil {
+			return fmt.Errorf("failed to restart workloads in namespace %s: %v", ns, err)
+		}
+		u.p.Printf("  Namespace %s: restarted %d workloads\n", ns, len(restarted))
+		workloads = append(workloads, restarted...)
+	}
+	pl := progress.NewLog().NewComponent("Workloads")
+	if err := install.WaitForResources(workloads, u.client, u.timeout, u.dryRun, pl); err != nil {
+		pl.ReportError(err.Error())
+		return err
+	}
+	pl.ReportFinished()
+	return nil
+}
+
+// relabelNamespace points a namespace referencing a revision directly at another revision.
+func relabelNamespace(client kube.CLIClient, name, from, to string, tags []string) error {
+	ns, err := client.Kube().CoreV1().Namespaces().Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if rev := ns.Labels[label.IoIstioRev.Name]; rev != from || slices.Contains(tags, rev) {
+		return nil
+	}
+	patch := fmt.Sprintf(`{"metadata":{"labels":{%q:%q}}}`, label.IoIstioRev.Name, to)
+	_, err = client.Kube().CoreV1().Namespaces().Patch(context.Background(), name, types.MergePatchType, []byte(patch),
+		metav1.PatchOptions{})
+	return err
+}
+
+// restartWorkloads restarts the workloads of a namespace with pods injected by the given revision, returning them.
+func (u *canaryUpgrader) restartWorkloads(namespace, revision string) ([]manifest.Manifest, error) {
+	pods, err := u.client.Kube().CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", label.IoIstioRev.Name, revision),
+	})
+	if err != nil {
+		return nil, err
+	}
+	restartedAt := time.Now().Format(time.RFC3339)
+	patch := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`, restartedAtAnnotation, restartedAt))
+	seen := sets.New[string]()
+	var restarted []manifest.Manifest
+	for _, pod := range pods.Items {
+		name, meta := kube.GetDeployMetaFromPod(&pod)
+		if seen.InsertContains(meta.Kind + "/" + name.Name) {
+			continue
+		}
+		apps := u.client.Kube().AppsV1()
+		switch meta.Kind {
+		case gvk.Deployment.Kind:
+			_, err = apps.Deployments(namespace).Patch(context.Background(), name.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+		case gvk.StatefulSet.Kind:
+			_, err = apps.StatefulSets(namespace).Patch(context.Background(), name.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+		case gvk.DaemonSet.Kind:
+			_, err = apps.DaemonSets(namespace).Patch(context.Background(), name.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+		default:
+			u.l.LogAndPrintf("  Pod %s/%s is not managed by a Deployment, StatefulSet or DaemonSet and must be restarted manually",
+				pod.Namespace, pod.Name)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to restart %s %s: %v", meta.Kind, name.Name, err)
+		}
+		us := &unstructured.Unstructured{}
+		us.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind(meta.Kind))
+		us.SetName(name.Name)
+		us.SetNamespace(namespace)
+		restarted = append(restarted, manifest.Manifest{Unstructured: us})
+	}
+	return restarted, nil
+}
+
+// checkHealth verifies that no proxy of the migrated namespaces is still connected to the old revision, and that the
+// precheck analyzers report no errors.
+func (u *canaryUpgrader) checkHealth(namespaces []string, from string) error {
+	client := u.client
+	if from != tag.DefaultRevisionName {
+		var err error
+		if client, err = u.ctx.CLIClientWithRevision(from); err != nil {
+			return err
+		}
+	}
+	infos, err := proxy.GetProxyInfo(client, u.istioNamespace)
+	if err != nil {
+		return fmt.Errorf("failed to get proxy status: %v", err)
+	}
+	var stale []string
+	for _, pi := range *infos {
+		if i := strings.LastIndex(pi.ID, "."); i >= 0 && slices.Contains(namespaces, pi.ID[i+1:]) {
+			stale = append(stale, pi.ID)
+		}
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("proxies still connected to revision %q: %s", from, strings.Join(slices.Sort(stale), ", "))
+	}
+
+	msgs, err := precheck.CheckControlPlane(u.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to run precheck: %v", err)
+	}
+	var errs []string
+	for _, m := range msgs {
+		if m.Type.Level().IsWorseThanOrEqualTo(diag.Error) {
+			errs = append(errs, m.String())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("precheck failed:\n%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// removeRevision uninstalls the control plane of a revision.
+func (u *canaryUpgrader) removeRevision(revision string) error {
+	u.p.Printf("Removing revision %q\n", revision)
+	objects, err := uninstall.GetPrunedResources(u.client, "", "", revision, false)
+	if err != nil {
+		return err
+	}
+	if err := uninstall.DeleteObjectsList(u.client, u.dryRun, u.l, objects); err != nil {
+		return fmt.Errorf("failed to remove revision %q: %v", revision, err)
+	}
+	return nil
+}
+
+// loadCanaryState returns the checkpointed state of a canary upgrade, or nil if there is none.
+func loadCanaryState(client kube.CLIClient, istioNamespace string) (*canaryState, error) {
+	cm, err := client.Kube().CoreV1().ConfigMaps(istioNamespace).Get(context.Background(), canaryCheckpointName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read canary upgrade checkpoint: %v", err)
+	}
+	state := &canaryState{}
+	if err := json.Unmarshal([]byte(cm.Data[canaryCheckpointKey]), state); err != nil {
+		return nil, fmt.Errorf("invalid canary upgrade checkpoint %s/%s: %v", istioNamespace, canaryCheckpointName, err)
+	}
+	return state, nil
+}
+
+// saveCanaryState checkpoints the state of a canary upgrade.
+func saveCanaryState(client kube.CLIClient, istioNamespace string, state *canaryState) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: canaryCheckpointName, Namespace: istioNamespace},
+		Data:       map[string]string{canaryCheckpointKey: string(b)},
+	}
+	cms := client.Kube().CoreV1().ConfigMaps(istioNamespace)
+	if _, err = cms.Update(context.Background(), cm, metav1.UpdateOptions{}); kerrors.IsNotFound(err) {
+		_, err = cms.Create(context.Background(), cm, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to checkpoint canary upgrade: %v", err)
+	}
+	return nil
+}
+
+func deleteCanaryState(client kube.CLIClient, istioNamespace string) error {
+	err := client.Kube().CoreV1().ConfigMaps(istioNamespace).Delete(context.Background(), canaryCheckpointName, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete canary upgrade checkpoint: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mesh
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	admitv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"istio.io/api/label"
+	"istio.io/istio/operator/pkg/util/clog"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/ptr"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func canaryNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func tagWebhook(tagName, revision string) *admitv1.MutatingWebhookConfiguration {
+	return &admitv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{
+		Name:   "istio-revision-tag-" + tagName,
+		Labels: map[string]string{label.IoIstioTag.Name: tagName, label.IoIstioRev.Name: revision},
+	}}
+}
+
+func newTestCanaryUpgrader(objects ...runtime.Object) (*canaryUpgrader, *[][]string) {
+	var verified [][]string
+	out := &bytes.Buffer{}
+	u := &canaryUpgrader{
+		client:         kube.NewFakeClient(objects...),
+		istioNamespace: "istio-system",
+		timeout:        time.Second,
+		out:            out,
+		l:              clog.NewConsoleLogger(out, out, installerScope),
+		p:              NewPrinterForWriter(out),
+	}
+	u.verify = func(namespaces []string, from string) error {
+		verified = append(verified, namespaces)
+		return nil
+	}
+	return u, &verified
+}
+
+func TestPlanCanary(t *testing.T) {
+	u, _ := newTestCanaryUpgrader(
+		tagWebhook("prod", "1-24"),
+		tagWebhook("canary", "1-25"),
+		canaryNamespace("a", map[string]string{label.IoIstioRev.Name: "1-24"}),
+		canaryNamespace("b", map[string]string{label.IoIstioRev.Name: "prod"}),
+		canaryNamespace("c", map[string]string{label.IoIstioRev.Name: "canary"}),
+		canaryNamespace("d", map[string]string{"istio-injection": "enabled"}),
+		canaryNamespace("e", map[string]string{label.IoIstioRev.Name: "1-24"}),
+		canaryNamespace("f", nil),
+	)
+	state, err := planCanary(u.client, u.l, "1-24", "1-26", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, state, &canaryState{
+		From:  "1-24",
+		To:    "1-26",
+		Tags:  []string{"prod"},
+		Waves: [][]string{{"a", "b"}, {"e"}},
+	})
+}
+
+func TestCanaryState(t *testing.T) {
+	u, _ := newTestCanaryUpgrader()
+	state, err := loadCanaryState(u.client, u.istioNamespace)
+	assert.NoError(t, err)
+	assert.Equal(t, state, nil)
+
+	want := &canaryState{From: "1-24", To: "1-25", Waves: [][]string{{"a"}, {"b"}}}
+	assert.NoError(t, saveCanaryState(u.client, u.istioNamespace, want))
+	want.CompletedWaves = 1
+	assert.NoError(t, saveCanaryState(u.client, u.istioNamespace, want))
+	state, err = loadCanaryState(u.client, u.istioNamespace)
+	assert.NoError(t, err)
+	assert.Equal(t, state, want)
+
+	assert.NoError(t, deleteCanaryState(u.client, u.istioNamespace))
+	state, err = loadCanaryState(u.client, u.istioNamespace)
+	assert.NoError(t, err)
+	assert.Equal(t, state, nil)
+}
+
+func TestRestartWorkloads(t *testing.T) {
+	pod := func(name, revision string, owner *metav1.OwnerReference) *corev1.Pod {
+		p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "a",
+			Labels:    map[string]string{label.IoIstioRev.Name: revision, "pod-template-hash": "abc"},
+		}}
+		if owner != nil {
+			p.GenerateName = owner.Name + "-"
+			p.OwnerReferences = []metav1.OwnerReference{*owner}
+		}
+		return p
+	}
+	rs := &metav1.OwnerReference{Kind: "ReplicaSet", APIVersion: "apps/v1", Name: "reviews-abc", Controller: ptr.Of(true)}
+	u, _ := newTestCanaryUpgrader(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: "a"}},
+		pod("reviews-abc-1", "1-24", rs),
+		pod("reviews-abc-2", "1-24", rs),
+		pod("ratings-abc-1", "1-25", rs),
+		pod("standalone", "1-24", nil),
+	)
+	restarted, err := u.restartWorkloads("a", "1-24")
+	assert.NoError(t, err)
+	assert.Equal(t, len(restarted), 1)
+	assert.Equal(t, restarted[0].GetKind(), "Deployment")
+	assert.Equal(t, restarted[0].GetName(), "reviews")
+
+	d, err := u.client.Kube().AppsV1().Deployments("a").Get(context.Background(), "reviews", metav1.GetOptions{})
+	assert.NoError(t, err)
+	if d.Spec.Template.Annotations[restartedAtAnnotation] == "" {
+		t.Fatalf("expected deployment to be restarted")
+	}
+}
+
+func TestCanaryRun(t *testing.T) {
+	u, verified := newTestCanaryUpgrader(
+		canaryNamespace("a", map[string]string{label.IoIstioRev.Name: "1-24"}),
+		canaryNamespace("b", map[string]string{label.IoIstioRev.Name: "1-24"}),
+		canaryNamespace("c", map[string]string{label.IoIstioRev.Name: "1-24"}),
+	)
+	// The first wave was already migrated before the upgrade was interrupted.
+	state := &canaryState{From: "1-24", To: "1-25", TagsMoved: true, Waves: [][]string{{"a"}, {"b", "c"}}, CompletedWaves: 1}
+	assert.NoError(t, saveCanaryState(u.client, u.istioNamespace, state))
+
+	assert.NoError(t, u.run(state, true))
+	assert.Equal(t, *verified, [][]string{{"b", "c"}})
+	for ns, rev := range map[string]string{"a": "1-24", "b": "1-25", "c": "1-25"} {
+		n, err := u.client.Kube().CoreV1().Namespaces().Get(context.Background(), ns, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, n.Labels[label.IoIstioRev.Name], rev)
+	}
+	state, err := loadCanaryState(u.client, u.istioNamespace)
+	assert.NoError(t, err)
+	assert.Equal(t, state, nil)
+}
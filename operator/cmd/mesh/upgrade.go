@@ -15,14 +15,29 @@
 package mesh
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 
 	"istio.io/istio/istioctl/pkg/cli"
 	"istio.io/istio/operator/pkg/util/clog"
+	"istio.io/istio/pkg/config/labels"
 )
 
 type upgradeArgs struct {
 	*InstallArgs
+	// canary upgrades to a new revision alongside the existing one, migrating namespaces in waves.
+	canary bool
+	// fromRevision is the revision a canary upgrade migrates from.
+	fromRevision string
+	// waveSize is the number of namespaces migrated in each wave of a canary upgrade.
+	waveSize int
+	// resume continues a canary upgrade from its checkpoint.
+	resume bool
+	// rollback reverts a canary upgrade from its checkpoint.
+	rollback bool
+	// keepOldRevision skips removing the old revision once a canary upgrade completes.
+	keepOldRevision bool
 }
 
 // UpgradeCmd upgrades Istio control plane in-place with eligibility checks.
@@ -34,7 +49,45 @@ func UpgradeCmd(ctx cli.Context) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "upgrade",
 		Short: "Upgrade Istio control plane in-place",
-		Long:  "The upgrade command is an alias for the install command",
+		Long: `The upgrade command is an alias for the install command, unless --canary is set.
+
+With --canary, the new revision is installed alongside the existing one. Revision tags are then moved to it, and
+namespaces are migrated in waves: they are relabeled, their workloads are restarted, and the proxies and cluster
+are checked before the next wave. Progress is checkpointed in a ConfigMap in the Istio namespace, so an interrupted
+upgrade can be continued with --resume or reverted with --rollback. Once all namespaces are migrated, the old
+revision is removed.`,
+		Example: `  # Upgrade Istio in-place
+  istioctl upgrade
+
+  # Upgrade from the default revision to revision 1-25, two namespaces at a time
+  istioctl upgrade --canary --revision 1-25 --wave-size 2
+
+  # Continue an interrupted canary upgrade
+  istioctl upgrade --canary --resume
+
+  # Revert an interrupted canary upgrade
+  istioctl upgrade --canary --rollback`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if !upgradeArgs.canary {
+				return nil
+			}
+			if upgradeArgs.resume && upgradeArgs.rollback {
+				return fmt.Errorf("--resume and --rollback cannot be used together")
+			}
+			if upgradeArgs.resume || upgradeArgs.rollback {
+				return nil
+			}
+			if upgradeArgs.Revision == "" || !labels.IsDNS1123Label(upgradeArgs.Revision) {
+				return fmt.Errorf("a valid --revision is required for a canary upgrade")
+			}
+			if upgradeArgs.Revision == upgradeArgs.fromRevision {
+				return fmt.Errorf("--revision must differ from --from-revision")
+			}
+			if upgradeArgs.waveSize < 1 {
+				return fmt.Errorf("--wave-size must be at least 1")
+			}
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) (e error) {
 			l := clog.NewConsoleLogger(cmd.OutOrStdout(), cmd.ErrOrStderr(), installerScope)
 			p := NewPrinterForWriter(cmd.OutOrStderr())
@@ -42,10 +95,25 @@ func UpgradeCmd(ctx cli.Context) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if upgradeArgs.canary {
+				return CanaryUpgrade(ctx, client, rootArgs, upgradeArgs, cmd.OutOrStdout(), l, p)
+			}
 			return Install(client, rootArgs, upgradeArgs.InstallArgs, cmd.OutOrStdout(), l, p)
 		},
 	}
 	addFlags(cmd, rootArgs)
 	addInstallFlags(cmd, upgradeArgs.InstallArgs)
+	cmd.PersistentFlags().BoolVar(&upgradeArgs.canary, "canary", false,
+		"Upgrade to the revision set with --revision alongside the existing revision, migrating namespaces in waves")
+	cmd.PersistentFlags().StringVar(&upgradeArgs.fromRevision, "from-revision", "default",
+		"Revision to migrate from in a canary upgrade")
+	cmd.PersistentFlags().IntVar(&upgradeArgs.waveSize, "wave-size", 1,
+		"Number of namespaces migrated in each wave of a canary upgrade")
+	cmd.PersistentFlags().BoolVar(&upgradeArgs.resume, "resume", false,
+		"Continue an interrupted canary upgrade from its checkpoint")
+	cmd.PersistentFlags().BoolVar(&upgradeArgs.rollback, "rollback", false,
+		"Revert a canary upgrade from its checkpoint, migrating namespaces back and removing the new revision")
+	cmd.PersistentFlags().BoolVar(&upgradeArgs.keepOldRevision, "keep-old-revision", false,
+		"Do not remove the old revision once a canary upgrade completes")
 	return cmd
 }
@@ -0,0 +1,216 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"istio.io/istio/operator/pkg/manifest"
+	"istio.io/istio/pkg/kube/controllers"
+	"istio.io/istio/pkg/maps"
+	"istio.io/istio/pkg/slices"
+	"istio.io/istio/pkg/util/sets"
+)
+
+// Drift describes how an object in the cluster differs from the rendered manifest.
+type Drift struct {
+	// Object identifies the object, in the form Kind/namespace/name.
+	Object string
+	// Missing is set if the object is in the manifest, but not in the cluster.
+	Missing bool
+	// Extra is set if the object is owned by the installation, but not in the manifest. It would be pruned on install.
+	Extra bool
+	// Fields are the fields whose value in the cluster differs from the manifest.
+	Fields []FieldDrift
+}
+
+// FieldDrift is a single field that differs between the cluster and the manifest.
+type FieldDrift struct {
+	// Path is the field path, such as spec.template.spec.containers[0].image.
+	Path string
+	// Live is the value in the cluster, or nil if the field is not set.
+	Live any
+	// Desired is the value in the manifest.
+	Desired any
+}
+
+// ignoredFields are fields that are managed by the API server or controllers, rather than by the install.
+// Indexes into lists are matched with [*].
+var ignoredFields = map[string]sets.String{
+	"": sets.New("status"),
+	// istiod patches the CA bundle in, and switches the failure policy to Fail once it is ready.
+	"ValidatingWebhookConfiguration": sets.New("webhooks[*].clientConfig.caBundle", "webhooks[*].failurePolicy"),
+	"MutatingWebhookConfiguration":   sets.New("webhooks[*].clientConfig.caBundle"),
+}
+
+// Drift compares a set of rendered manifests to the objects in the cluster. Only fields set in the manifest are
+// compared, so defaults filled in by the API server are not reported. Objects owned by the installation that are not in
+// the manifest are reported as extra.
+func (i Installer) Drift(manifests []manifest.ManifestSet) ([]Drift, error) {
+	var drifts []Drift
+	for _, mfs := range manifests {
+		for _, m := range mfs.Manifests {
+			desired, err := i.applyLabelsAndAnnotations(manifest.Manifest{Unstructured: m.DeepCopy()}, string(mfs.Component))
+			if err != nil {
+				return nil, err
+			}
+			d, err := i.objectDrift(desired)
+			if err != nil {
+				return nil, err
+			}
+			if d != nil {
+				drifts = append(drifts, *d)
+			}
+		}
+	}
+
+	extra, err := i.prunable(manifests)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range extra {
+		drifts = append(drifts, Drift{Object: objectString(obj), Extra: true})
+	}
+	slices.SortFunc(drifts, func(a, b Drift) int {
+		return cmp.Compare(a.Object, b.Object)
+	})
+	return drifts, nil
+}
+
+func (i Installer) objectDrift(desired manifest.Manifest) (*Drift, error) {
+	dc, err := i.Kube.DynamicClientFor(desired.GroupVersionKind(), desired.Unstructured, "")
+	if err != nil {
+		return nil, err
+	}
+	name := objectString(desired.Unstructured)
+	live, err := dc.Get(context.Background(), desired.GetName(), metav1.GetOptions{})
+	if controllers.IgnoreNotFound(err) != nil {
+		return nil, fmt.Errorf("failed to get %v: %v", name, err)
+	}
+	if live == nil || err != nil {
+		return &Drift{Object: name, Missing: true}, nil
+	}
+
+	// Normalize both sides, so numbers are compared the same way.
+	var want, got map[string]any
+	if err := normalize(desired.Object, &want); err != nil {
+		return nil, err
+	}
+	if err := normalize(live.Object, &got); err != nil {
+		return nil, err
+	}
+	ignored := ignoredFields[""].Union(ignoredFields[desired.GetKind()])
+	fields := diffFields("", "", want, got, ignored)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return &Drift{Object: name, Fields: fields}, nil
+}
+
+// diffFields returns the fields set in desired that differ in live. pattern is path, with list indexes replaced by
+// [*], to match against ignored.
+func diffFields(path, pattern string, desired, live any, ignored sets.String) []FieldDrift {
+	if ignored.Contains(pattern) {
+		return nil
+	}
+	switch want := desired.(type) {
+	case map[string]any:
+		got, ok := live.(map[string]any)
+		if !ok && live != nil {
+			return []FieldDrift{{Path: path, Live: live, Desired: desired}}
+		}
+		var res []FieldDrift
+		for _, k := range slices.Sort(maps.Keys(want)) {
+			res = append(res, diffFields(joinPath(path, k), joinPath(pattern, k), want[k], got[k], ignored)...)
+		}
+		return res
+	case []any:
+		got, ok := live.([]any)
+		if len(want) == 0 && len(got) == 0 {
+			return nil
+		}
+		if !ok || len(got) != len(want) {
+			return []FieldDrift{{Path: path, Live: live, Desired: desired}}
+		}
+		var res []FieldDrift
+		for idx := range want {
+			res = append(res, diffFields(path+"["+strconv.Itoa(idx)+"]", pattern+"[*]", want[idx], got[idx], ignored)...)
+		}
+		return res
+	}
+	if live == nil && isZero(desired) {
+		return nil
+	}
+	if reflect.DeepEqual(desired, live) || equalQuantity(desired, live) {
+		return nil
+	}
+	return []FieldDrift{{Path: path, Live: live, Desired: desired}}
+}
+
+// equalQuantity reports whether both values are equivalent resource quantities, as the API server canonicalizes them.
+func equalQuantity(a, b any) bool {
+	as, ok := a.(string)
+	if !ok {
+		return false
+	}
+	bs, ok := b.(string)
+	if !ok {
+		return false
+	}
+	aq, err := resource.ParseQuantity(as)
+	if err != nil {
+		return false
+	}
+	bq, err := resource.ParseQuantity(bs)
+	if err != nil {
+		return false
+	}
+	return aq.Cmp(bq) == 0
+}
+
+func isZero(v any) bool {
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
+
+func normalize(in map[string]any, out *map[string]any) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+func joinPath(path, key string) string {
+	if strings.ContainsAny(key, "./") {
+		key = "[" + key + "]"
+	} else if path != "" {
+		key = "." + key
+	}
+	return path + key
+}
+
+func objectString(o *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s/%s", o.GetKind(), o.GetNamespace(), o.GetName())
+}
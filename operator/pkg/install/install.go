@@ -22,6 +22,7 @@ import (
 
 	"github.com/hashicorp/go-multierror"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	klabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
@@ -53,6 +54,11 @@ type Installer struct {
 	WaitTimeout    time.Duration
 	Logger         clog.Logger
 	ProgressLogger *progress.Log
+	// Rollback restores every object the installation changed to its previous state if the installation fails.
+	Rollback bool
+
+	// snapshot records the state of objects before they are changed, when Rollback is set.
+	snapshot *snapshot
 }
 
 // InstallManifests applies a set of rendered manifests to the cluster.
@@ -73,26 +79,43 @@ func (i Installer) InstallManifests(manifests []manifest.ManifestSet) error {
 		}
 	}
 
+	if i.Rollback && !i.DryRun {
+		i.snapshot = newSnapshot()
+	}
+
 	// Finally, we can actually install all the manifests
 	if err := i.install(manifests); err != nil {
-		return err
+		return i.rollback(err)
 	}
 
 	// We may need to manually deploy some webhooks out-of-band from the install, making this th
 	ownerLabels := getOwnerLabels(i.Values, "")
 	webhooks, err := webhook.WebhooksToDeploy(i.Values, i.Kube, ownerLabels, i.DryRun)
 	if err != nil {
-		return fmt.Errorf("failed generating webhooks: %v", err)
+		return i.rollback(fmt.Errorf("failed generating webhooks: %v", err))
 	}
 	for _, wh := range webhooks {
 		if err := i.serverSideApply(wh); err != nil {
-			return fmt.Errorf("failed deploying webhooks: %v", err)
+			return i.rollback(fmt.Errorf("failed deploying webhooks: %v", err))
 		}
 	}
 
 	return nil
 }
 
+// rollback restores the objects changed by the installation, if Rollback is set, and returns the installation error.
+func (i Installer) rollback(cause error) error {
+	if i.snapshot == nil {
+		return cause
+	}
+	i.ProgressLogger.SetState(progress.StateRollingBack)
+	if err := i.snapshot.restore(i.Kube, i.ProgressLogger); err != nil {
+		return fmt.Errorf("%v\nfailed to roll back to the previous installation: %v", cause, err)
+	}
+	i.ProgressLogger.SetState(progress.StateRolledBack)
+	return fmt.Errorf("%v\nrolled back to the previous installation", cause)
+}
+
 // installSystemNamespace creates the system namespace before install
 func (i Installer) installSystemNamespace() error {
 	ns := i.Values.GetPathStringOr("metadata.namespace", "istio-system")
@@ -204,6 +227,11 @@ func (i Installer) serverSideApply(obj manifest.Manifest) error {
 	if i.DryRun {
 		return nil
 	}
+	if i.snapshot != nil {
+		if err := i.snapshot.record(dc, obj.Unstructured); err != nil {
+			return err
+		}
+	}
 	if _, err := dc.Patch(context.TODO(), obj.GetName(), types.ApplyPatchType, []byte(obj.Content), metav1.PatchOptions{
 		DryRun:       dryRun,
 		Force:        ptr.Of(true),
@@ -232,6 +260,24 @@ func (i Installer) prune(manifests []manifest.ManifestSet) error {
 	}
 	i.ProgressLogger.SetState(progress.StatePruning)
 
+	objs, err := i.prunable(manifests)
+	if err != nil {
+		return err
+	}
+	var errs util.Errors
+	for _, obj := range objs {
+		if i.snapshot != nil {
+			i.snapshot.recordLive(obj)
+		}
+		if err := uninstall.DeleteResource(i.Kube, i.DryRun, i.Logger, obj); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs.ToError()
+}
+
+// prunable returns the resources in the cluster owned by the installation, but not a part of the given set of objects.
+func (i Installer) prunable(manifests []manifest.ManifestSet) ([]*unstructured.Unstructured, error) {
 	// Build up a map of component->resources, so we know what to keep around
 	excluded := map[component.Name]sets.String{}
 	// Include all components in case we disabled some.
@@ -248,21 +294,21 @@ func (i Installer) prune(manifests []manifest.ManifestSet) error {
 	selector := klabels.Set(coreLabels).AsSelectorPreValidated()
 	componentRequirement, err := klabels.NewRequirement(manifest.IstioComponentLabel, selection.Exists, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	selector = selector.Add(*componentRequirement)
 
-	var errs util.Errors
+	var prunable []*unstructured.Unstructured
 	resources := uninstall.PrunedResourcesSchemas()
 	for _, gvk := range resources {
 		dc, err := i.Kube.DynamicClientFor(gvk, nil, "")
 		if err != nil {
-			return err
+			return nil, err
 		}
 		objs, err := dc.List(context.Background(), metav1.ListOptions{LabelSelector: selector.String()})
 		if err := controllers.IgnoreNotFound(err); err != nil {
 			// Cluster may not even have these resources; ignore these errors
-			return err
+			return nil, err
 		}
 		if objs == nil {
 			continue
@@ -281,14 +327,11 @@ func (i Installer) prune(manifests []manifest.ManifestSet) error {
 				if !componentLabels.Matches(klabels.Set(obj.GetLabels())) {
 					continue
 				}
-
-				if err := uninstall.DeleteResource(i.Kube, i.DryRun, i.Logger, &obj); err != nil {
-					errs = append(errs, err)
-				}
+				prunable = append(prunable, &obj)
 			}
 		}
 	}
-	return errs.ToError()
+	return prunable, nil
 }
 
 var componentDependencies = map[component.Name][]component.Name{
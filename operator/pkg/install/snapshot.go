@@ -0,0 +1,164 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"istio.io/istio/operator/pkg/manifest"
+	"istio.io/istio/operator/pkg/util"
+	"istio.io/istio/operator/pkg/util/progress"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/ptr"
+)
+
+// snapshot records the state of objects before the installation changes them, so they can be restored if it fails.
+type snapshot struct {
+	mu sync.Mutex
+	// recorded holds the hashes of the objects already in the snapshot. Only the first state is kept.
+	recorded map[string]struct{}
+	// objects are in the order they were changed in.
+	objects []snapshotObject
+}
+
+type snapshotObject struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+	// previous is the object before the installation changed it, or nil if the installation created it.
+	previous *unstructured.Unstructured
+}
+
+func newSnapshot() *snapshot {
+	return &snapshot{recorded: map[string]struct{}{}}
+}
+
+// record fetches obj from the cluster and adds its current state to the snapshot, unless it is already recorded.
+func (s *snapshot) record(dc dynamic.ResourceInterface, obj *unstructured.Unstructured) error {
+	if s.has(obj) {
+		return nil
+	}
+	live, err := dc.Get(context.Background(), obj.GetName(), metav1.GetOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to snapshot %v: %v", objectString(obj), err)
+	}
+	if err != nil {
+		live = nil
+	}
+	s.add(obj, live)
+	return nil
+}
+
+// recordLive adds an object already fetched from the cluster to the snapshot, unless it is already recorded.
+func (s *snapshot) recordLive(live *unstructured.Unstructured) {
+	if s.has(live) {
+		return
+	}
+	s.add(live, live.DeepCopy())
+}
+
+func (s *snapshot) has(obj *unstructured.Unstructured) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, f := s.recorded[manifest.ObjectHash(obj)]
+	return f
+}
+
+func (s *snapshot) add(obj, previous *unstructured.Unstructured) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h := manifest.ObjectHash(obj)
+	if _, f := s.recorded[h]; f {
+		return
+	}
+	s.recorded[h] = struct{}{}
+	s.objects = append(s.objects, snapshotObject{
+		gvk:       obj.GroupVersionKind(),
+		namespace: obj.GetNamespace(),
+		name:      obj.GetName(),
+		previous:  previous,
+	})
+}
+
+// restore returns every recorded object to its previous state, in the reverse order they were changed in. Objects
+// created by the installation are removed.
+func (s *snapshot) restore(client kube.CLIClient, pl *progress.Log) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var errs util.Errors
+	for idx := len(s.objects) - 1; idx >= 0; idx-- {
+		o := s.objects[idx]
+		name := fmt.Sprintf("%s/%s/%s", o.gvk.Kind, o.namespace, o.name)
+		var err error
+		if o.previous == nil {
+			err = s.remove(client, o)
+			if err == nil {
+				pl.ReportRollback("Removed " + name)
+			}
+		} else {
+			err = s.restoreObject(client, o)
+			if err == nil {
+				pl.ReportRollback("Restored " + name)
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to roll back %v: %v", name, err))
+		}
+	}
+	return errs.ToError()
+}
+
+func (s *snapshot) remove(client kube.CLIClient, o snapshotObject) error {
+	dc, err := client.DynamicClientFor(o.gvk, nil, o.namespace)
+	if err != nil {
+		return err
+	}
+	err = dc.Delete(context.Background(), o.name, metav1.DeleteOptions{PropagationPolicy: ptr.Of(metav1.DeletePropagationForeground)})
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *snapshot) restoreObject(client kube.CLIClient, o snapshotObject) error {
+	dc, err := client.DynamicClientFor(o.gvk, o.previous, "")
+	if err != nil {
+		return err
+	}
+	previous := o.previous.DeepCopy()
+	previous.SetManagedFields(nil)
+	current, err := dc.Get(context.Background(), o.name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		// The object was removed, for example by pruning. Recreate it.
+		previous.SetResourceVersion("")
+		previous.SetUID("")
+		_, err = dc.Create(context.Background(), previous, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	previous.SetResourceVersion(current.GetResourceVersion())
+	_, err = dc.Update(context.Background(), previous, metav1.UpdateOptions{})
+	return err
+}
@@ -33,6 +33,8 @@ const (
 	StatePruning
 	StateComplete
 	StateUninstallComplete
+	StateRollingBack
+	StateRolledBack
 )
 
 // Log records the progress of an installation
@@ -154,9 +156,23 @@ func (p *Log) SetState(state InstallState) {
 		p.SetMessage(`{{ green "✔" }} Installation complete`, true)
 	case StateUninstallComplete:
 		p.SetMessage(`{{ green "✔" }} Uninstall complete`, true)
+	case StateRollingBack:
+		// Finish the line, so the rolled back objects are reported below it.
+		p.SetMessage(`{{ yellow "-" }} Rolling back to the previous installation`, true)
+		p.bar = createBar()
+	case StateRolledBack:
+		p.SetMessage(`{{ yellow "✔" }} Rolled back to the previous installation`, true)
 	}
 }
 
+// ReportRollback records an object that was restored to its state before the installation.
+func (p *Log) ReportRollback(msg string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.SetMessage("  "+msg, true)
+	p.bar = createBar()
+}
+
 func (p *Log) NewComponent(component string) *ManifestLog {
 	ml := &ManifestLog{
 		report: p.reportProgress(component),
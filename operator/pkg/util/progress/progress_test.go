@@ -73,4 +73,13 @@ func TestProgressLog(t *testing.T) {
 
 	p.SetState(StateUninstallComplete)
 	expect(`✔ Uninstall complete`)
+
+	p.SetState(StateRollingBack)
+	expect(`- Rolling back to the previous installation`)
+
+	p.ReportRollback("Restored Deployment/istio-system/istiod")
+	expect(`  Restored Deployment/istio-system/istiod`)
+
+	p.SetState(StateRolledBack)
+	expect(`✔ Rolled back to the previous installation`)
 }
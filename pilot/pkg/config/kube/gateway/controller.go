@@ -23,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	inferencev1 "sigs.k8s.io/gateway-api-inference-extension/api/v1"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayalpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gateway "sigs.k8s.io/gateway-api/apis/v1beta1"
 	gatewayx "sigs.k8s.io/gateway-api/apisx/v1alpha1"
 
@@ -144,6 +145,7 @@ type Inputs struct {
 	HTTPRoutes           krt.Collection[*gatewayv1.HTTPRoute]
 	GRPCRoutes           krt.Collection[*gatewayv1.GRPCRoute]
 	TCPRoutes            krt.Collection[*gatewayv1.TCPRoute]
+	UDPRoutes            krt.Collection[*gatewayalpha2.UDPRoute]
 	TLSRoutes            krt.Collection[*gatewayv1.TLSRoute]
 	ListenerSets         krt.Collection[*gatewayv1.ListenerSet]
 	ReferenceGrants      krt.Collection[*gateway.ReferenceGrant]
@@ -204,6 +206,7 @@ func NewController(
 		BackendTLSPolicies: buildClient[*gatewayv1.BackendTLSPolicy](c, kc, gvr.BackendTLSPolicy, opts, "informer/BackendTLSPolicies"),
 		TLSRoutes:          buildClient[*gatewayv1.TLSRoute](c, kc, gvr.TLSRoute, opts, "informer/TLSRoutes"),
 		TCPRoutes:          buildClient[*gatewayv1.TCPRoute](c, kc, gvr.TCPRoute, opts, "informer/TCPRoutes"),
+		UDPRoutes:          buildClient[*gatewayalpha2.UDPRoute](c, kc, gvr.UDPRoute, opts, "informer/UDPRoutes"),
 		ListenerSets:       buildClient[*gatewayv1.ListenerSet](c, kc, gvr.ListenerSet, opts, "informer/ListenerSet"),
 
 		ReferenceGrants: buildClient[*gateway.ReferenceGrant](c, kc, gvr.ReferenceGrant, opts, "informer/ReferenceGrants"),
@@ -324,6 +327,12 @@ func NewController(
 		opts,
 	)
 	status.RegisterStatus(c.status, tcpRoutes.Status, GetStatus, c.tagWatcher.AccessUnprotected())
+	udpRoutes := UDPRouteCollection(
+		inputs.UDPRoutes,
+		routeInputs,
+		opts,
+	)
+	status.RegisterStatus(c.status, udpRoutes.Status, GetStatus, c.tagWatcher.AccessUnprotected())
 	tlsRoutes := TLSRouteCollection(
 		inputs.TLSRoutes,
 		routeInputs,
@@ -345,6 +354,7 @@ func NewController(
 
 	RouteAttachments := krt.JoinCollection([]krt.Collection[RouteAttachment]{
 		tcpRoutes.RouteAttachments,
+		udpRoutes.RouteAttachments,
 		tlsRoutes.RouteAttachments,
 		httpRoutes.RouteAttachments,
 		grpcRoutes.RouteAttachments,
@@ -354,6 +364,7 @@ func NewController(
 	})
 	Ancestors := krt.JoinCollection([]krt.Collection[AncestorBackend]{
 		tcpRoutes.Ancestors,
+		udpRoutes.Ancestors,
 		tlsRoutes.Ancestors,
 		httpRoutes.Ancestors,
 		grpcRoutes.Ancestors,
@@ -389,6 +400,7 @@ func NewController(
 
 	VirtualServices := krt.JoinCollection([]krt.Collection[config.Config]{
 		tcpRoutes.VirtualServices,
+		udpRoutes.VirtualServices,
 		tlsRoutes.VirtualServices,
 		httpAndGrpcVS,
 	}, opts.WithName("DerivedVirtualServices")...)
@@ -36,6 +36,7 @@ import (
 	klabels "k8s.io/apimachinery/pkg/labels"
 	inferencev1 "sigs.k8s.io/gateway-api-inference-extension/api/v1"
 	k8s "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayalpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayx "sigs.k8s.io/gateway-api/apisx/v1alpha1"
 
 	"istio.io/api/annotation"
@@ -629,6 +630,13 @@ func referenceAllowed(
 	hostnames []k8s.Hostname,
 	localNamespace string,
 ) (*ParentError, *WaypointError) {
+	if routeKind == gvk.UDPRoute && (parentRef.Kind == gvk.Service || parentRef.Kind == gvk.ServiceEntry) {
+		// UDP is only supported on gateways, where it is served by Envoy's UDP proxy
+		return &ParentError{
+			Reason:  ParentErrorNotAccepted,
+			Message: fmt.Sprintf("%s is not supported for mesh traffic", routeKind.Kind),
+		}, nil
+	}
 	switch parentRef.Kind {
 	case gvk.Service:
 
@@ -862,6 +870,29 @@ func convertTCPRoute(ctx RouteContext, r k8s.TCPRouteRule, obj *k8s.TCPRoute, en
 	}, backendErr
 }
 
+func convertUDPRoute(ctx RouteContext, r gatewayalpha2.UDPRouteRule, obj *gatewayalpha2.UDPRoute) (*istio.TCPRoute, *ConfigError) {
+	if tcpWeightSum(r.BackendRefs) == 0 {
+		// The spec requires us to drop datagrams when there are no >0 weight backends
+		return &istio.TCPRoute{
+			Route: []*istio.RouteDestination{{
+				Destination: &istio.Destination{
+					Host:   "internal.cluster.local",
+					Subset: "zero-weight",
+					Port:   &istio.PortSelector{Number: 65535},
+				},
+				Weight: 0,
+			}},
+		}, nil
+	}
+	dest, backendErr, err := buildTCPDestination(ctx, r.BackendRefs, obj.Namespace, true, gvk.UDPRoute)
+	if err != nil {
+		return nil, err
+	}
+	return &istio.TCPRoute{
+		Route: dest,
+	}, backendErr
+}
+
 func convertTLSRoute(ctx RouteContext, r k8s.TLSRouteRule, obj *k8s.TLSRoute, enforceRefGrant bool) (*istio.TLSRoute, *ConfigError) {
 	if tcpWeightSum(r.BackendRefs) == 0 {
 		// The spec requires us to reject connections when there are no >0 weight backends
@@ -2190,6 +2221,7 @@ var supportedProtocols = sets.New(
 	k8s.HTTPSProtocolType,
 	k8s.TLSProtocolType,
 	k8s.TCPProtocolType,
+	k8s.UDPProtocolType,
 	k8s.ProtocolType(protocol.HBONE),
 )
 
@@ -2204,6 +2236,8 @@ func listenerProtocolToIstio(name k8s.GatewayController, p k8s.ProtocolType) (st
 		return string(p), nil
 	case k8s.TCPProtocolType:
 		return string(p), nil
+	case k8s.UDPProtocolType:
+		return string(p), nil
 	// Our own custom types
 	case k8s.ProtocolType(protocol.HBONE):
 		if name != constants.ManagedGatewayMeshController && name != constants.ManagedGatewayEastWestController {
@@ -2215,10 +2249,19 @@ func listenerProtocolToIstio(name k8s.GatewayController, p k8s.ProtocolType) (st
 	if supportedProtocols.Contains(up) {
 		return "", fmt.Errorf("protocol %q is unsupported. hint: %q (uppercase) may be supported", p, up)
 	}
-	// Note: the k8s.UDPProtocolType is explicitly left to hit this path
 	return "", fmt.Errorf("protocol %q is unsupported", p)
 }
 
+// backendClientCertificateResource returns the credential resource name of the spec.tls.backend.clientCertificateRef
+// field on a Gateway, or an empty string if the field is not set.
+func backendClientCertificateResource(gw *k8s.Gateway) string {
+	if gw.Spec.TLS == nil || gw.Spec.TLS.Backend == nil || gw.Spec.TLS.Backend.ClientCertificateRef == nil {
+		return ""
+	}
+	ref := *gw.Spec.TLS.Backend.ClientCertificateRef
+	return creds.ToKubernetesGatewayResource(ptr.OrDefault((*string)(ref.Namespace), gw.GetNamespace()), string(ref.Name))
+}
+
 // validateBackendClientCertificateRef validates the spec.tls.backend.clientCertificateRef
 // field on a Gateway. It returns nil if the field is not set or the reference is valid.
 func validateBackendClientCertificateRef(
@@ -2652,6 +2695,8 @@ func GetCommonRouteInfo(spec any) ([]k8s.ParentReference, []k8s.Hostname, config
 	switch t := spec.(type) {
 	case *k8s.TCPRoute:
 		return t.Spec.ParentRefs, nil, gvk.TCPRoute
+	case *gatewayalpha2.UDPRoute:
+		return t.Spec.ParentRefs, nil, gvk.UDPRoute
 	case *k8s.TLSRoute:
 		return t.Spec.ParentRefs, t.Spec.Hostnames, gvk.TLSRoute
 	case *k8s.HTTPRoute:
@@ -2668,6 +2713,8 @@ func GetCommonRouteStateParents(spec any) []k8s.RouteParentStatus {
 	switch t := spec.(type) {
 	case *k8s.TCPRoute:
 		return t.Status.Parents
+	case *gatewayalpha2.UDPRoute:
+		return t.Status.Parents
 	case *k8s.TLSRoute:
 		return t.Status.Parents
 	case *k8s.HTTPRoute:
@@ -2691,6 +2738,8 @@ func GetStatus[I, IS any](spec I) IS {
 	switch t := any(spec).(type) {
 	case *k8s.TCPRoute:
 		return any(t.Status).(IS)
+	case *gatewayalpha2.UDPRoute:
+		return any(t.Status).(IS)
 	case *k8s.TLSRoute:
 		return any(t.Status).(IS)
 	case *k8s.HTTPRoute:
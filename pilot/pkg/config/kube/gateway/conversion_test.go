@@ -121,6 +121,11 @@ var services = []*model.Service{
 				Port:     34001,
 				Protocol: "TCP",
 			},
+			{
+				Name:     "udp",
+				Port:     5353,
+				Protocol: "UDP",
+			},
 		},
 		Hostname: "istio-ingressgateway.istio-system.svc.domain.suffix",
 	},
@@ -671,7 +676,7 @@ func (t *TestStatusQueue) Dump() string {
 		objs = append(objs, obj)
 	}
 	slices.SortFunc(objs, func(a, b crd.IstioKind) int {
-		ord := []string{gvk.GatewayClass.Kind, gvk.Gateway.Kind, gvk.HTTPRoute.Kind, gvk.GRPCRoute.Kind, gvk.TLSRoute.Kind, gvk.TCPRoute.Kind}
+		ord := []string{gvk.GatewayClass.Kind, gvk.Gateway.Kind, gvk.HTTPRoute.Kind, gvk.GRPCRoute.Kind, gvk.TLSRoute.Kind, gvk.TCPRoute.Kind, gvk.UDPRoute.Kind}
 		if r := cmp.Compare(slices.Index(ord, a.Kind), slices.Index(ord, b.Kind)); r != 0 {
 			return r
 		}
@@ -708,6 +713,7 @@ func TestConvertResources(t *testing.T) {
 	}{
 		{name: "http"},
 		{name: "tcp"},
+		{name: "udp"},
 		{name: "tls"},
 		{name: "tls-terminate"},
 		{name: "grpc"},
@@ -800,6 +806,7 @@ func TestConvertResources(t *testing.T) {
 		},
 		{name: "frontend-tls-invalid"},
 		{name: "backend-tls-client-cert"},
+		{name: "backend-tls-client-cert-conflict"},
 	}
 	test.SetForTest(t, &features.EnableGatewayAPIGatewayClassController, false)
 	test.SetForTest(t, &features.EnableGatewayAPIInferenceExtension, true)
@@ -877,6 +884,7 @@ func setupClientCRDs(t *testing.T, kc kube.CLIClient) {
 		gvr.HTTPRoute,
 		gvr.GRPCRoute,
 		gvr.TCPRoute,
+		gvr.UDPRoute,
 		gvr.TLSRoute,
 		gvr.ServiceEntry,
 		gvr.XBackendTrafficPolicy,
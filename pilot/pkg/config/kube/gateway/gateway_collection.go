@@ -35,6 +35,7 @@ import (
 	"istio.io/istio/pkg/ptr"
 	"istio.io/istio/pkg/revisions"
 	"istio.io/istio/pkg/slices"
+	"istio.io/istio/pkg/util/sets"
 )
 
 type Gateway struct {
@@ -255,6 +256,47 @@ func ListenerSetCollection(
 	return statusCol, gw
 }
 
+// backendClientCertificateConflict reports whether another Gateway deployed behind the same services references a
+// different backend client certificate. These Gateways are merged onto the same proxies, which can only present a
+// single client certificate to backends, so none is applied.
+func backendClientCertificateConflict(
+	ctx krt.HandlerContext,
+	obj *gatewayv1.Gateway,
+	backendClientCertificate string,
+	gatewayServices []string,
+	serviceIndex krt.Index[string, *gatewayv1.Gateway],
+	gatewayClasses krt.Collection[gatewaycommon.GatewayClass],
+	domainSuffix string,
+) *ConfigError {
+	for _, svc := range gatewayServices {
+		for _, other := range serviceIndex.Fetch(ctx, svc) {
+			if other.Namespace == obj.Namespace && other.Name == obj.Name {
+				continue
+			}
+			class := gatewaycommon.FetchGatewayClass(ctx, gatewayClasses, other.Spec.GatewayClassName)
+			if class == nil {
+				continue
+			}
+			classInfo, f := gatewaycommon.ClassInfos[class.Controller]
+			if !f || classInfo.DisableRouteGeneration {
+				continue
+			}
+			otherServices, _ := extractGatewayServices(domainSuffix, other, classInfo)
+			if !slices.Contains(otherServices, svc) {
+				continue
+			}
+			if backendClientCertificateResource(other) != backendClientCertificate {
+				return &ConfigError{
+					Reason: InvalidClientCertificateRef,
+					Message: fmt.Sprintf("clientCertificateRef is not applied: Gateway %s/%s is deployed behind the same service %v "+
+						"with a different client certificate", other.Namespace, other.Name, svc),
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func listenerSetParentErr(err *ConfigError) *gatewaycommon.ListenerStatusConfigError {
 	if err == nil {
 		return nil
@@ -282,6 +324,15 @@ func GatewayCollection(
 	listenerIndex := krt.NewIndex(listenerSets, "gatewayParent", func(o ListenerSet) []types.NamespacedName {
 		return []types.NamespacedName{o.GatewayParent}
 	})
+	// The class of a Gateway is not known here, so index it by the services of every possible class.
+	serviceIndex := krt.NewIndex(gateways, "gatewayService", func(o *gatewayv1.Gateway) []string {
+		services := sets.New[string]()
+		for _, disableNameSuffix := range []bool{false, true} {
+			svcs, _ := extractGatewayServices(domainSuffix, o, gatewaycommon.ClassInfo{DisableNameSuffix: disableNameSuffix})
+			services.InsertAll(svcs...)
+		}
+		return sets.SortedList(services)
+	})
 	// Note: tagWatcher.IsMine() is intentionally not filtered at this config-emission layer. See the
 	// comment in ListenerSetCollection above for the rationale.
 	statusCol, gw := krt.NewStatusManyCollection(gateways, func(ctx krt.HandlerContext, obj *gatewayv1.Gateway) (*gatewayv1.GatewayStatus, []Gateway) {
@@ -339,6 +390,18 @@ func GatewayCollection(
 			gwListenerConflicts = gwConflict.ConflictsForGateway(obj)
 		}
 
+		backendTLSErr := validateBackendClientCertificateRef(ctx, obj, secrets, grants)
+		backendClientCertificate := ""
+		if backendTLSErr == nil {
+			backendClientCertificate = backendClientCertificateResource(obj)
+		}
+		if backendClientCertificate != "" {
+			backendTLSErr = backendClientCertificateConflict(ctx, obj, backendClientCertificate, gatewayServices, serviceIndex, gatewayClasses, domainSuffix)
+			if backendTLSErr != nil {
+				backendClientCertificate = ""
+			}
+		}
+
 		for i, l := range kgw.Listeners {
 			if reason, ok := gwListenerConflicts[l.Name]; ok {
 				status.Listeners = gatewaycommon.ReportListenerConflict(i, l, obj, status.Listeners, reason, gatewaycommon.GenerateGatewaySupportedKinds)
@@ -371,6 +434,9 @@ func GatewayCollection(
 			meta[model.InternalGatewayServiceAnnotation] = strings.Join(gatewayServices, ",")
 
 			meta[constants.InternalServiceAccount] = serviceAccountName
+			if backendClientCertificate != "" {
+				meta[constants.InternalBackendClientCertificate] = backendClientCertificate
+			}
 
 			// Each listener generates an Istio Gateway with a single Server. This allows binding to a specific listener.
 			gatewayConfig := config.Config{
@@ -421,15 +487,20 @@ func GatewayCollection(
 			}
 			listenerSets[ls.Parent] = true
 			servers = append(servers, ls.Config.Spec.(*istio.Gateway).Servers...)
+			lsConfig := ls.Config
+			if backendClientCertificate != "" {
+				// The backend client certificate of the parent applies to its ListenerSets as well.
+				lsConfig = ptr.Of(ls.Config.DeepCopy())
+				lsConfig.Annotations[constants.InternalBackendClientCertificate] = backendClientCertificate
+			}
 			result = append(result, Gateway{
-				Config:     ls.Config,
+				Config:     lsConfig,
 				Parent:     ls.Parent,
 				ParentInfo: ls.ParentInfo,
 				Valid:      ls.Valid,
 			})
 		}
 
-		backendTLSErr := validateBackendClientCertificateRef(ctx, obj, secrets, grants)
 		reportGatewayStatus(context, obj, status, classInfo, gatewayServices, servers, len(listenerSets), err, backendTLSErr, validListeners)
 		return status, result
 	}, opts.WithName("KubernetesGateway")...)
@@ -26,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	inferencev1 "sigs.k8s.io/gateway-api-inference-extension/api/v1"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayalpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	istio "istio.io/api/networking/v1alpha3"
 	networkingclient "istio.io/client-go/pkg/apis/networking/v1"
@@ -534,6 +535,81 @@ func TCPRouteCollection(
 	}
 }
 
+// UDPRouteCollection converts UDPRoutes to VirtualServices with tcp routes. These are only bound to UDP gateway
+// servers, where the routes are served by Envoy's UDP proxy.
+func UDPRouteCollection(
+	udpRoutes krt.Collection[*gatewayalpha2.UDPRoute],
+	inputs RouteContextInputs,
+	opts krt.OptionsBuilder,
+) RouteResult[*gatewayalpha2.UDPRoute, gatewayalpha2.UDPRouteStatus] {
+	routeCount := gatewayRouteAttachmentCountCollection(inputs, udpRoutes, gvk.UDPRoute, opts)
+	ancestorBackends := krt.NewManyCollection(udpRoutes, func(krtctx krt.HandlerContext, obj *gatewayalpha2.UDPRoute) []AncestorBackend {
+		return extractAncestorBackends(
+			obj.ObjectMeta,
+			kind.FromString(obj.Kind),
+			obj.Spec.ParentRefs,
+			obj.Spec.Rules,
+			func(r gatewayalpha2.UDPRouteRule) []gatewayv1.BackendRef {
+				return r.BackendRefs
+			},
+		)
+	}, opts.WithName("UDPAncestors")...)
+	status, virtualServices := krt.NewStatusManyCollection(udpRoutes, func(krtctx krt.HandlerContext, obj *gatewayalpha2.UDPRoute) (
+		*gatewayalpha2.UDPRouteStatus,
+		[]config.Config,
+	) {
+		ctx := inputs.WithCtx(krtctx)
+		status := obj.Status.DeepCopy()
+		route := obj.Spec
+		parentStatus, parentRefs, _, gwResult := computeRoute(ctx, obj,
+			func(mesh bool, obj *gatewayalpha2.UDPRoute) iter.Seq2[*istio.TCPRoute, *ConfigError] {
+				return func(yield func(*istio.TCPRoute, *ConfigError) bool) {
+					for _, r := range route.Rules {
+						if !yield(convertUDPRoute(ctx, r, obj)) {
+							return
+						}
+					}
+				}
+			})
+		status.Parents = parentStatus
+
+		vs := []config.Config{}
+		count := 0
+		for _, parent := range filteredReferences(parentRefs) {
+			if parent.IsMesh() {
+				// UDPRoute is not supported for mesh traffic; such parents are rejected as their kind is not allowed.
+				continue
+			}
+			name := fmt.Sprintf("%s~udp~%d~%s", obj.Name, count, constants.KubernetesGatewayName)
+			vs = append(vs, config.Config{
+				Meta: config.Meta{
+					CreationTimestamp: obj.CreationTimestamp.Time,
+					GroupVersionKind:  gvk.VirtualService,
+					Name:              name,
+					Annotations:       routeMeta(obj),
+					Namespace:         obj.Namespace,
+					Domain:            ctx.DomainSuffix,
+				},
+				Spec: &istio.VirtualService{
+					// UDP has no hostname to match on, and each listener can have at most one route bound to it.
+					Hosts:    []string{"*"},
+					Gateways: []string{parent.InternalName},
+					Tcp:      gwResult.routes,
+				},
+			})
+			count++
+		}
+		return status, vs
+	}, opts.WithName("UDPRoute")...)
+
+	return RouteResult[*gatewayalpha2.UDPRoute, gatewayalpha2.UDPRouteStatus]{
+		VirtualServices:  virtualServices,
+		RouteAttachments: routeCount,
+		Status:           status,
+		Ancestors:        ancestorBackends,
+	}
+}
+
 func TLSRouteCollection(
 	tlsRoutes krt.Collection[*gatewayv1.TLSRoute],
 	inputs RouteContextInputs,
@@ -231,6 +231,8 @@ func GenerateGatewaySupportedKinds(l gatewayv1.Listener) ([]gatewayv1.RouteGroup
 		}
 	case gatewayv1.TCPProtocolType:
 		supported = []gatewayv1.RouteGroupKind{ToRouteKind(gvk.TCPRoute)}
+	case gatewayv1.UDPProtocolType:
+		supported = []gatewayv1.RouteGroupKind{ToRouteKind(gvk.UDPRoute)}
 	case gatewayv1.TLSProtocolType:
 		supported = []gatewayv1.RouteGroupKind{ToRouteKind(gvk.TLSRoute)}
 		if l.TLS != nil && ptr.OrEmpty(l.TLS.Mode) == gatewayv1.TLSModeTerminate {
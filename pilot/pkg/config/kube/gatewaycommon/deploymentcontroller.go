@@ -23,10 +23,12 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/go-multierror"
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	klabels "k8s.io/apimachinery/pkg/labels"
@@ -62,6 +64,7 @@ import (
 	"istio.io/istio/pkg/slices"
 	"istio.io/istio/pkg/test/util/tmpl"
 	"istio.io/istio/pkg/test/util/yml"
+	"istio.io/istio/pkg/util/istiomultierror"
 	"istio.io/istio/pkg/util/sets"
 )
 
@@ -332,22 +335,9 @@ func (d *DeploymentController) Reconcile(req types.NamespacedName) error {
 		return nil
 	}
 
-	var controller gateway.GatewayController
-	if gc := d.gatewayClasses.Get(string(gw.Spec.GatewayClassName), ""); gc != nil {
-		controller = gc.Spec.ControllerName
-	} else {
-		builtin, f := BuiltinGatewayClasses[gw.Spec.GatewayClassName]
-		if f {
-			controller = builtin
-		} else if features.EnableAgentgateway {
-			if agwClass, f := AgentgatewayClasses[gw.Spec.GatewayClassName]; f {
-				controller = agwClass
-			}
-		}
-	}
-	ci, f := ClassInfos[controller]
+	ci, f := d.classInfo(gw)
 	if !f {
-		log.Debugf("skipping unknown controller %q", controller)
+		log.Debugf("skipping unknown controller for class %q", gw.Spec.GatewayClassName)
 		return nil
 	}
 	log.Infof("reconciling gateway with controller %s", ci.Controller)
@@ -363,6 +353,25 @@ func (d *DeploymentController) Reconcile(req types.NamespacedName) error {
 	return d.configureIstioGateway(log, *gw, ci)
 }
 
+// classInfo returns the class of a Gateway, if it is implemented by one of our controllers.
+func (d *DeploymentController) classInfo(gw *gateway.Gateway) (ClassInfo, bool) {
+	var controller gateway.GatewayController
+	if gc := d.gatewayClasses.Get(string(gw.Spec.GatewayClassName), ""); gc != nil {
+		controller = gc.Spec.ControllerName
+	} else {
+		builtin, f := BuiltinGatewayClasses[gw.Spec.GatewayClassName]
+		if f {
+			controller = builtin
+		} else if features.EnableAgentgateway {
+			if agwClass, f := AgentgatewayClasses[gw.Spec.GatewayClassName]; f {
+				controller = agwClass
+			}
+		}
+	}
+	ci, f := ClassInfos[controller]
+	return ci, f
+}
+
 var errPushContext = errors.New("PushContext not initialized")
 
 func (d *DeploymentController) configureIstioGateway(log *istiolog.Scope, gw gateway.Gateway, gi ClassInfo) error {
@@ -387,6 +396,147 @@ func (d *DeploymentController) configureIstioGateway(log *istiolog.Scope, gw gat
 	}
 	log.Info("reconciling")
 
+	input := d.templateInput(gw, gi)
+
+	if overwriteControllerVersion {
+		log.Debugf("write controller version, existing=%v", existingControllerVersion)
+		if err := d.setGatewayControllerVersion(gw); err != nil {
+			return fmt.Errorf("update gateway annotation: %v", err)
+		}
+	} else {
+		log.Debugf("controller version existing=%v, no action needed", existingControllerVersion)
+	}
+
+	rendered, err := d.renderResources(gi, input)
+	if err != nil {
+		// Just log error, we do not need to retry since rendering errors are not ephemeral errors
+		log.Errorf("error rendering templates: %v", err)
+		return nil
+	}
+	for _, r := range rendered {
+		if err := d.apply(gi.Controller, r.yml, r.input); err != nil {
+			return fmt.Errorf("apply failed: %v", err)
+		}
+	}
+	if gi.Controller == constants.ManagedGatewayMeshController {
+		if err := d.pruneWaypointShards(gw, input.DeploymentName, rendered); err != nil {
+			return fmt.Errorf("prune failed: %v", err)
+		}
+	}
+
+	log.Info("gateway updated")
+	return nil
+}
+
+// renderedResource is a rendered resource of a Gateway, with the input it was rendered from.
+type renderedResource struct {
+	yml   string
+	input TemplateInput
+}
+
+// renderResources renders the resources deployed for a Gateway. A sharded waypoint is rendered once per shard, with
+// labels selecting the pods of the shard, and once for the Service selecting the pods of every shard, which is the
+// address of the Gateway.
+func (d *DeploymentController) renderResources(gi ClassInfo, input TemplateInput) ([]renderedResource, error) {
+	rendered, err := d.render(gi.Templates, input)
+	if err != nil {
+		return nil, err
+	}
+	shards := 0
+	if gi.Controller == constants.ManagedGatewayMeshController {
+		shards = model.WaypointShards(input.Annotations)
+	}
+	if shards == 0 {
+		return slices.Map(rendered, func(yml string) renderedResource {
+			return renderedResource{yml: yml, input: input}
+		}), nil
+	}
+	var out []renderedResource
+	for _, yml := range rendered {
+		if kind := renderedMeta(yml).Kind; kind == gvk.Service.Kind || kind == gvk.ServiceAccount.Kind {
+			out = append(out, renderedResource{yml: yml, input: input})
+		}
+	}
+	for i := range shards {
+		shard := input
+		shard.DeploymentName = model.WaypointShardName(input.DeploymentName, i)
+		shard.ShardLabels = map[string]string{constants.WaypointShardLabel: strconv.Itoa(i)}
+		rendered, err := d.render(gi.Templates, shard)
+		if err != nil {
+			return nil, err
+		}
+		for _, yml := range rendered {
+			// The ServiceAccount is shared by all shards
+			if renderedMeta(yml).Kind != gvk.ServiceAccount.Kind {
+				out = append(out, renderedResource{yml: yml, input: shard})
+			}
+		}
+	}
+	return out, nil
+}
+
+func renderedMeta(yml string) metav1.PartialObjectMetadata {
+	meta := metav1.PartialObjectMetadata{}
+	if err := yaml.Unmarshal([]byte(yml), &meta); err != nil {
+		return metav1.PartialObjectMetadata{}
+	}
+	return meta
+}
+
+// pruneWaypointShards deletes the resources left behind when the number of shards of a waypoint changes: the
+// unsharded Deployment once the waypoint is sharded, and the resources of shards that are no longer rendered.
+// Only resources owned by the Gateway and named after the waypoint or one of its shards are deleted.
+func (d *DeploymentController) pruneWaypointShards(gw gateway.Gateway, name string, rendered []renderedResource) error {
+	keep := sets.New[string]()
+	sharded := false
+	for _, r := range rendered {
+		meta := renderedMeta(r.yml)
+		keep.Insert(meta.Kind + "/" + meta.Name)
+		sharded = sharded || len(r.input.ShardLabels) > 0
+	}
+	stale := func(kind string, o controllers.Object) bool {
+		if keep.Contains(kind + "/" + o.GetName()) {
+			return false
+		}
+		// Unsharded resources are only replaced once the waypoint is sharded
+		if !strings.HasPrefix(o.GetName(), name+"-shard-") && (o.GetName() != name || !sharded) {
+			return false
+		}
+		return slices.FindFunc(o.GetOwnerReferences(), func(ref metav1.OwnerReference) bool {
+			return ref.UID == gw.UID
+		}) != nil
+	}
+	selector := klabels.SelectorFromSet(map[string]string{label.IoK8sNetworkingGatewayGatewayName.Name: gw.Name})
+	err := istiomultierror.New()
+	err = multierror.Append(err, pruneStale(d.deployments, gw.Namespace, selector, gvk.Deployment.Kind, stale))
+	err = multierror.Append(err, pruneStale(d.services, gw.Namespace, selector, gvk.Service.Kind, stale))
+	err = multierror.Append(err, pruneStale(d.hpas, gw.Namespace, selector, gvk.HorizontalPodAutoscaler.Kind, stale))
+	err = multierror.Append(err, pruneStale(d.pdbs, gw.Namespace, selector, gvk.PodDisruptionBudget.Kind, stale))
+	return err.ErrorOrNil()
+}
+
+func pruneStale[T controllers.ComparableObject](
+	c kclient.Client[T],
+	namespace string,
+	selector klabels.Selector,
+	kind string,
+	stale func(kind string, o controllers.Object) bool,
+) error {
+	err := istiomultierror.New()
+	for _, o := range c.List(namespace, selector) {
+		if !stale(kind, o) {
+			continue
+		}
+		log.Infof("deleting stale %v %v/%v", kind, o.GetNamespace(), o.GetName())
+		if derr := c.Delete(o.GetName(), o.GetNamespace()); derr != nil && !kerrors.IsNotFound(derr) {
+			err = multierror.Append(err, derr)
+		}
+	}
+	return err.ErrorOrNil()
+}
+
+// templateInput builds the input of the templates rendering the resources deployed for a Gateway.
+func (d *DeploymentController) templateInput(gw gateway.Gateway, gi ClassInfo) TemplateInput {
 	var ns *corev1.Namespace
 	if d.namespaces != nil {
 		ns = d.namespaces.Get(gw.Namespace, "")
@@ -448,30 +598,7 @@ func (d *DeploymentController) configureIstioGateway(log *istiolog.Scope, gw gat
 	input.InfrastructureLabels = extractInfrastructureLabels(gw)
 	input.InfrastructureAnnotations = extractInfrastructureAnnotations(gw)
 	d.setLabelOverrides(gw, input)
-
-	if overwriteControllerVersion {
-		log.Debugf("write controller version, existing=%v", existingControllerVersion)
-		if err := d.setGatewayControllerVersion(gw); err != nil {
-			return fmt.Errorf("update gateway annotation: %v", err)
-		}
-	} else {
-		log.Debugf("controller version existing=%v, no action needed", existingControllerVersion)
-	}
-
-	rendered, err := d.render(gi.Templates, input)
-	if err != nil {
-		// Just log error, we do not need to retry since rendering errors are not ephemeral errors
-		log.Errorf("error rendering templates: %v", err)
-		return nil
-	}
-	for _, t := range rendered {
-		if err := d.apply(gi.Controller, t, input); err != nil {
-			return fmt.Errorf("apply failed: %v", err)
-		}
-	}
-
-	log.Info("gateway updated")
-	return nil
+	return input
 }
 
 func (d *DeploymentController) setLabelOverrides(gw gateway.Gateway, input TemplateInput) {
@@ -785,12 +912,12 @@ func (d *DeploymentController) setGatewayControllerVersion(gws gateway.Gateway)
 	return d.patcher(gvr.KubernetesGateway, gws.GetName(), gws.GetNamespace(), []byte(patch), "status")
 }
 
-// apply server-side applies a template to the cluster.
-func (d *DeploymentController) apply(controller string, yml string, input TemplateInput) error {
+// prepareObject validates a rendered template and labels it as managed by the controller.
+func prepareObject(controller string, yml string, input TemplateInput) (unstructured.Unstructured, error) {
 	data := map[string]any{}
 	err := yaml.Unmarshal([]byte(yml), &data)
 	if err != nil {
-		return err
+		return unstructured.Unstructured{}, err
 	}
 	us := unstructured.Unstructured{Object: data}
 
@@ -799,13 +926,13 @@ func (d *DeploymentController) apply(controller string, yml string, input Templa
 	kind := us.GetKind()
 	allowedKinds := sets.New("Deployment", "DaemonSet", "Service", "ServiceAccount", "HorizontalPodAutoscaler", "PodDisruptionBudget")
 	if !allowedKinds.Contains(kind) {
-		return fmt.Errorf("unexpected object kind %q, only %v are allowed", kind, allowedKinds.UnsortedList())
+		return unstructured.Unstructured{}, fmt.Errorf("unexpected object kind %q, only %v are allowed", kind, allowedKinds.UnsortedList())
 	}
 
 	// safeguard: validate object namespace matches gateway namespace
 	objNamespace := us.GetNamespace()
 	if objNamespace != input.Namespace {
-		return fmt.Errorf("object namespace %q does not match gateway namespace %q", objNamespace, input.Namespace)
+		return unstructured.Unstructured{}, fmt.Errorf("object namespace %q does not match gateway namespace %q", objNamespace, input.Namespace)
 	}
 
 	// safeguard: validate object name matches expected names from template
@@ -815,12 +942,22 @@ func (d *DeploymentController) apply(controller string, yml string, input Templa
 	// also accept gateway name as prefix for backward compat
 	validName := expectedNames.Contains(objName) || strings.HasPrefix(objName, input.Name)
 	if !validName {
-		return fmt.Errorf("object name %q does not match expected pattern (expected %v or prefix of %q)", objName, expectedNames.UnsortedList(), input.Name)
+		return unstructured.Unstructured{}, fmt.Errorf("object name %q does not match expected pattern (expected %v or prefix of %q)",
+			objName, expectedNames.UnsortedList(), input.Name)
 	}
 
 	// set managed-by label
 	clabel := strings.ReplaceAll(controller, "/", "-")
 	err = unstructured.SetNestedField(us.Object, clabel, "metadata", "labels", label.GatewayManaged.Name)
+	if err != nil {
+		return unstructured.Unstructured{}, err
+	}
+	return us, nil
+}
+
+// apply server-side applies a template to the cluster.
+func (d *DeploymentController) apply(controller string, yml string, input TemplateInput) error {
+	us, err := prepareObject(controller, yml, input)
 	if err != nil {
 		return err
 	}
@@ -906,6 +1043,8 @@ type TemplateInput struct {
 	GatewayNameLabel          string
 	IsEastWestGateway         bool
 	ControllerLabel           string
+	// ShardLabels select the pods of a shard of a waypoint. They are empty unless the waypoint is sharded.
+	ShardLabels map[string]string
 }
 
 func extractServicePorts(gw gateway.Gateway, listenerSets []gateway.Listener) []corev1.ServicePort {
@@ -917,13 +1056,23 @@ func extractServicePorts(gw gateway.Gateway, listenerSets []gateway.Listener) []
 		Protocol:    corev1.ProtocolTCP,
 		AppProtocol: &tcp,
 	})
-	portNums := sets.New[int32]()
+	type portKey struct {
+		port     int32
+		protocol corev1.Protocol
+	}
+	portNums := sets.New[portKey]()
 	allListeners := append(slices.Clone(gw.Spec.Listeners), listenerSets...)
 	for i, l := range allListeners {
-		if portNums.Contains(l.Port) {
+		// UDP listeners can share a port number with TCP based listeners, such as DNS on port 53.
+		transport := corev1.ProtocolTCP
+		if l.Protocol == gateway.UDPProtocolType {
+			transport = corev1.ProtocolUDP
+		}
+		key := portKey{port: l.Port, protocol: transport}
+		if portNums.Contains(key) {
 			continue
 		}
-		portNums.Insert(l.Port)
+		portNums.Insert(key)
 		name := sanitizeListenerNameForPort(string(l.Name))
 		if name == "" {
 			// Should not happen since name is required, but in case an invalid resource gets in...
@@ -933,10 +1082,12 @@ func extractServicePorts(gw gateway.Gateway, listenerSets []gateway.Listener) []
 		svcPorts = append(svcPorts, corev1.ServicePort{
 			Name:        name,
 			Port:        l.Port,
-			Protocol:    corev1.ProtocolTCP,
+			Protocol:    transport,
 			AppProtocol: &appProtocol,
 		})
-		if features.EnableQUICListeners && protocol.Parse(appProtocol) == protocol.HTTPS {
+		quicKey := portKey{port: l.Port, protocol: corev1.ProtocolUDP}
+		if features.EnableQUICListeners && protocol.Parse(appProtocol) == protocol.HTTPS && !portNums.Contains(quicKey) {
+			portNums.Insert(quicKey)
 			svcPorts = append(svcPorts, corev1.ServicePort{
 				Name:        name + "-quic",
 				Port:        l.Port,
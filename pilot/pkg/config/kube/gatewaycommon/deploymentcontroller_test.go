@@ -16,6 +16,7 @@ package gatewaycommon
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -27,7 +28,9 @@ import (
 	"go.uber.org/atomic"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -309,6 +312,32 @@ func TestConfigureIstioGateway(t *testing.T) {
 			discoveryNamespaceFilter: discoveryNamespacesFilter,
 			enableQUICListeners:      true,
 		},
+		{
+			name: "udp",
+			gw: k8s.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "default",
+				},
+				Spec: k8s.GatewaySpec{
+					GatewayClassName: k8s.ObjectName(features.GatewayAPIDefaultGatewayClass),
+					Listeners: []k8s.Listener{
+						{
+							Name:     "dns-tcp",
+							Port:     k8s.PortNumber(53),
+							Protocol: k8s.TCPProtocolType,
+						},
+						{
+							Name:     "dns-udp",
+							Port:     k8s.PortNumber(53),
+							Protocol: k8s.UDPProtocolType,
+						},
+					},
+				},
+			},
+			objects:                  defaultObjects,
+			discoveryNamespaceFilter: discoveryNamespacesFilter,
+		},
 		{
 			name: "waypoint",
 			gw: k8s.Gateway{
@@ -500,6 +529,29 @@ func TestConfigureIstioGateway(t *testing.T) {
 			values: `global:
   hub: test
   tag: test
+  network: network-1`,
+		},
+		{
+			name: "waypoint-sharded",
+			gw: k8s.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "namespace",
+					Namespace:   "default",
+					Annotations: map[string]string{constants.WaypointShardsAnnotation: "2"},
+				},
+				Spec: k8s.GatewaySpec{
+					GatewayClassName: constants.WaypointGatewayClassName,
+					Listeners: []k8s.Listener{{
+						Name:     "mesh",
+						Port:     k8s.PortNumber(15008),
+						Protocol: "ALL",
+					}},
+				},
+			},
+			objects: defaultObjects,
+			values: `global:
+  hub: test
+  tag: test
   network: network-1`,
 		},
 		{
@@ -891,6 +943,101 @@ func TestMeshGatewayReconciliation(t *testing.T) {
 	assert.ChannelIsEmpty(t, writes)
 }
 
+func TestWaypointShardPruning(t *testing.T) {
+	c := kube.NewFakeClient(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "default",
+		},
+	})
+	c.Kube().Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &kubeVersion.Info{Major: "1", Minor: "28"}
+	tw := revisions.NewTagWatcher(c, "", "istio-system")
+	env := newTestEnv()
+	d := NewDeploymentController(c, "", env, testInjectionConfig(t, `global:
+  hub: test
+  tag: test`), func(fn func()) {}, tw, "", "")
+	// The fake client does not support server side apply, so apply the Deployments and Services directly
+	d.patcher = func(g schema.GroupVersionResource, name string, namespace string, data []byte, subresources ...string) error {
+		switch g {
+		case gvr.Deployment:
+			return fakeApply(d.deployments, &appsv1.Deployment{}, data)
+		case gvr.Service:
+			return fakeApply(d.services, &corev1.Service{}, data)
+		}
+		return nil
+	}
+
+	stop := test.NewStop(t)
+	gws := clienttest.Wrap(t, d.gateways)
+	env.PushContext().InitDone.Store(true)
+	go tw.Run(stop)
+	go d.Run(stop)
+	c.RunAndWait(stop)
+	kube.WaitForCacheSync("test", stop, d.queue.HasSynced)
+
+	names := func(objs []controllers.Object) []string {
+		return slices.Sort(slices.Map(objs, controllers.Object.GetName))
+	}
+	deployments := func() []string {
+		return names(slices.Map(d.deployments.List("default", klabels.Everything()), func(o *appsv1.Deployment) controllers.Object { return o }))
+	}
+	services := func() []string {
+		return names(slices.Map(d.services.List("default", klabels.Everything()), func(o *corev1.Service) controllers.Object { return o }))
+	}
+
+	waypoint := &k8s.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "waypoint",
+			Namespace: "default",
+			UID:       "waypoint-uid",
+		},
+		Spec: k8s.GatewaySpec{
+			GatewayClassName: constants.WaypointGatewayClassName,
+			Listeners: []k8s.Listener{{
+				Name:     "mesh",
+				Port:     k8s.PortNumber(15008),
+				Protocol: "HBONE",
+			}},
+		},
+	}
+	gws.Create(waypoint)
+	assert.EventuallyEqual(t, deployments, []string{"waypoint"})
+	assert.EventuallyEqual(t, services, []string{"waypoint"})
+
+	waypoint.Annotations = map[string]string{constants.WaypointShardsAnnotation: "3"}
+	gws.Update(waypoint)
+	assert.EventuallyEqual(t, deployments, []string{"waypoint-shard-0", "waypoint-shard-1", "waypoint-shard-2"})
+	assert.EventuallyEqual(t, services, []string{"waypoint", "waypoint-shard-0", "waypoint-shard-1", "waypoint-shard-2"})
+	// Each shard only selects its own pods, so the selectors of the shards do not overlap
+	for _, dep := range d.deployments.List("default", klabels.Everything()) {
+		assert.Equal(t, dep.Spec.Selector.MatchLabels, map[string]string{
+			label.IoK8sNetworkingGatewayGatewayName.Name: "waypoint",
+			constants.WaypointShardLabel:                 strings.TrimPrefix(dep.Name, "waypoint-shard-"),
+		})
+		assert.Equal(t, dep.Spec.Template.Labels["service.istio.io/canonical-name"], "waypoint")
+	}
+
+	waypoint.Annotations = map[string]string{constants.WaypointShardsAnnotation: "2"}
+	gws.Update(waypoint)
+	assert.EventuallyEqual(t, deployments, []string{"waypoint-shard-0", "waypoint-shard-1"})
+	assert.EventuallyEqual(t, services, []string{"waypoint", "waypoint-shard-0", "waypoint-shard-1"})
+
+	waypoint.Annotations = nil
+	gws.Update(waypoint)
+	assert.EventuallyEqual(t, deployments, []string{"waypoint"})
+	assert.EventuallyEqual(t, services, []string{"waypoint"})
+}
+
+func fakeApply[T controllers.ComparableObject](c kclient.Client[T], obj T, data []byte) error {
+	if err := json.Unmarshal(data, obj); err != nil {
+		return err
+	}
+	if _, err := c.Create(obj); !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+	_, err := c.Update(obj)
+	return err
+}
+
 func buildFilter(allowedNamespace string) kubetypes.DynamicObjectFilter {
 	return kubetypes.NewStaticObjectFilter(func(obj any) bool {
 		if ns, ok := obj.(string); ok {
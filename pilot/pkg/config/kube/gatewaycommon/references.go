@@ -140,7 +140,7 @@ func ReferenceGrantsCollection(referenceGrants krt.Collection[*gatewayv1beta1.Re
 			}
 			ref := NormalizeReference(&from.Group, &from.Kind, config.GroupVersionKind{})
 			switch ref {
-			case gvk.KubernetesGateway, gvk.HTTPRoute, gvk.GRPCRoute, gvk.TLSRoute, gvk.TCPRoute, gvk.ListenerSet:
+			case gvk.KubernetesGateway, gvk.HTTPRoute, gvk.GRPCRoute, gvk.TLSRoute, gvk.TCPRoute, gvk.UDPRoute, gvk.ListenerSet:
 				fromKey.Kind = ref
 			default:
 				// Not supported type. Not an error; may be for another controller
@@ -0,0 +1,137 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gatewaycommon
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	gateway "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayconsts "sigs.k8s.io/gateway-api/pkg/consts"
+	"sigs.k8s.io/yaml"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/mesh/meshwatcher"
+	"istio.io/istio/pkg/config/schema/gvr"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/inject"
+	"istio.io/istio/pkg/revisions"
+	"istio.io/istio/pkg/slices"
+)
+
+// RenderOptions configures the offline rendering of the resources deployed for Gateways.
+type RenderOptions struct {
+	// Objects are the Gateways to render, and the GatewayClasses, ListenerSets, ConfigMaps and Namespaces that
+	// affect them.
+	Objects []runtime.Object
+	// ProxyConfigs are the ProxyConfig resources, which can select the proxy image of Gateways.
+	ProxyConfigs []config.Config
+	// InjectConfig holds the templates, values and mesh config of the revision deploying the Gateways.
+	InjectConfig inject.WebhookConfig
+	Revision     string
+	// SystemNamespace is the namespace of the ConfigMaps holding the defaults of each GatewayClass.
+	SystemNamespace string
+	ClusterID       cluster.ID
+	// KubeVersion is the minor version of the Kubernetes cluster the resources are rendered for.
+	KubeVersion string
+}
+
+// RenderedGateway is the set of resources the DeploymentController applies for a Gateway.
+type RenderedGateway struct {
+	Gateway   types.NamespacedName
+	Resources []string
+}
+
+// Render returns the resources the DeploymentController would apply for each Gateway of the objects, without a
+// cluster. The objects are served by a fake client, so that Gateways are rendered by the same code as in the
+// controller. Gateways that are not deployed by this revision are skipped.
+func Render(opts RenderOptions) ([]RenderedGateway, error) {
+	client := kube.NewFakeClientWithVersion(opts.KubeVersion, opts.Objects...)
+	err := kube.CreateFakeCRD(client, gvr.ListenerSet,
+		map[string]string{gatewayconsts.BundleVersionAnnotation: gatewayconsts.BundleVersion})
+	if err != nil {
+		return nil, err
+	}
+	env := model.NewEnvironment()
+	env.Watcher = meshwatcher.NewTestWatcher(opts.InjectConfig.MeshConfig)
+	store := model.NewFakeStore()
+	for _, pc := range opts.ProxyConfigs {
+		if _, err := store.Create(pc); err != nil {
+			return nil, fmt.Errorf("invalid ProxyConfig %s/%s: %v", pc.Namespace, pc.Name, err)
+		}
+	}
+	env.PushContext().ProxyConfigs = model.GetProxyConfigs(store, opts.InjectConfig.MeshConfig)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	tw := revisions.NewTagWatcher(client, opts.Revision, opts.SystemNamespace)
+	d := NewDeploymentController(client, opts.ClusterID, env, func() inject.WebhookConfig {
+		return opts.InjectConfig
+	}, func(fn func()) {}, tw, opts.Revision, opts.SystemNamespace)
+	client.RunAndWait(stop)
+	go tw.Run(stop)
+	kube.WaitForCacheSync("gateway render", stop, tw.HasSynced, d.gateways.HasSynced, d.gatewayClasses.HasSynced,
+		d.listenerSets.HasSynced, d.configMaps.HasSynced, d.namespaces.HasSynced)
+
+	gateways := d.gateways.List(metav1.NamespaceAll, klabels.Everything())
+	slices.SortBy(gateways, func(gw *gateway.Gateway) string {
+		return gw.Namespace + "/" + gw.Name
+	})
+	var out []RenderedGateway
+	for _, gw := range gateways {
+		gi, f := d.classInfo(gw)
+		if !f || gi.Templates == "" || !IsManaged(&gw.Spec) || !tw.IsMine(gw.ObjectMeta) {
+			continue
+		}
+		resources, err := d.renderGateway(*gw, gi)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render Gateway %s/%s: %v", gw.Namespace, gw.Name, err)
+		}
+		out = append(out, RenderedGateway{Gateway: config.NamespacedName(gw), Resources: resources})
+	}
+	return out, nil
+}
+
+// renderGateway renders the resources of a Gateway as they are applied, before they are merged with the existing
+// resources.
+func (d *DeploymentController) renderGateway(gw gateway.Gateway, gi ClassInfo) ([]string, error) {
+	rendered, err := d.renderResources(gi, d.templateInput(gw, gi))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(rendered))
+	for _, r := range rendered {
+		us, err := prepareObject(gi.Controller, r.yml, r.input)
+		if err != nil {
+			return nil, err
+		}
+		j, err := json.Marshal(us.Object)
+		if err != nil {
+			return nil, err
+		}
+		b, err := yaml.JSONToYAML(j)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, string(b))
+	}
+	return out, nil
+}
@@ -0,0 +1,123 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gatewaycommon
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8s "sigs.k8s.io/gateway-api/apis/v1"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pkg/test/util/assert"
+	"istio.io/istio/pkg/test/util/file"
+)
+
+func TestRender(t *testing.T) {
+	defaultNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		// golden is the output of the DeploymentController for the same objects
+		golden string
+	}{
+		{
+			name: "simple",
+			objects: []runtime.Object{defaultNamespace, &k8s.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "default",
+					Namespace:   "default",
+					Labels:      map[string]string{"should": "see"},
+					Annotations: map[string]string{"should": "see"},
+				},
+				Spec: k8s.GatewaySpec{
+					GatewayClassName: k8s.ObjectName(features.GatewayAPIDefaultGatewayClass),
+				},
+			}},
+			golden: "simple.yaml",
+		},
+		{
+			name: "customizations",
+			objects: []runtime.Object{defaultNamespace, &k8s.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "namespace",
+					Namespace: "default",
+				},
+				Spec: k8s.GatewaySpec{
+					GatewayClassName: k8s.ObjectName(features.GatewayAPIDefaultGatewayClass),
+					Infrastructure: &k8s.GatewayInfrastructure{
+						Labels: map[k8s.LabelKey]k8s.LabelValue{"foo": "bar"},
+						ParametersRef: &k8s.LocalParametersReference{
+							Group: "",
+							Kind:  "ConfigMap",
+							Name:  "gw-options",
+						},
+					},
+				},
+			}, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "gw-options", Namespace: "default"},
+				Data: map[string]string{
+					"podDisruptionBudget": `
+spec:
+  minAvailable: 1`,
+					"horizontalPodAutoscaler": `
+spec:
+  minReplicas: 2
+  maxReplicas: 2`,
+					"deployment": `
+metadata:
+  annotations:
+    cm-annotation: cm-annotation-value
+spec:
+  replicas: 4
+  template:
+    spec:
+      containers:
+      - name: istio-proxy
+        resources:
+          requests:
+            cpu: 222m`,
+				},
+			}},
+			golden: "customizations.yaml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := Render(RenderOptions{
+				Objects:         tt.objects,
+				InjectConfig:    testInjectionConfig(t, "")(),
+				SystemNamespace: "istio-system",
+				ClusterID:       "Kubernetes",
+				KubeVersion:     "28",
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, len(rendered), 1)
+			gw := tt.objects[1].(*k8s.Gateway)
+			assert.Equal(t, rendered[0].Gateway, types.NamespacedName{Namespace: gw.Namespace, Name: gw.Name})
+
+			got := strings.Join(rendered[0].Resources, "---\n") + "---\n"
+			// The controller first annotates the Gateway with its version, which is not part of the rendered resources.
+			want := file.AsStringOrFail(t, filepath.Join("testdata", "deployment", tt.golden))
+			_, want, _ = strings.Cut(want, "---\n")
+			assert.Equal(t, got, want)
+		})
+	}
+}
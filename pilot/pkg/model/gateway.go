@@ -24,11 +24,13 @@ import (
 	"istio.io/istio/pkg/config"
 	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/gateway"
+	"istio.io/istio/pkg/config/host"
 	"istio.io/istio/pkg/config/protocol"
 	"istio.io/istio/pkg/config/schema/gvk"
 	"istio.io/istio/pkg/maps"
 	"istio.io/istio/pkg/monitoring"
 	"istio.io/istio/pkg/ptr"
+	"istio.io/istio/pkg/slices"
 	"istio.io/istio/pkg/util/protomarshal"
 	"istio.io/istio/pkg/util/sets"
 )
@@ -53,6 +55,9 @@ type MergedServers struct {
 type TLSServerInfo struct {
 	RouteName string
 	SNIHosts  []string
+	// MisdirectedHosts are the hosts served by other Gateway API HTTPS listeners on the same port, which should be
+	// answered with 421 (Misdirected Request) when received on this server's connections.
+	MisdirectedHosts []string
 }
 
 // MergedGateway describes a set of gateways for a workload merged into a single logical gateway.
@@ -101,6 +106,17 @@ type MergedGateway struct {
 	// for Gateway API gateways). Secrets in the same namespace that are not referenced by any gateway config are
 	// explicitly excluded to prevent unintended access (e.g., the citadel root key/cert).
 	VerifiedCertificateReferences sets.String
+
+	// BackendClientCertificate is the SDS resource name of the client certificate presented to backends that
+	// require TLS, if configured by a Gateway API gateway and authorized for this proxy.
+	BackendClientCertificate string
+}
+
+func (i *TLSServerInfo) GetMisdirectedHosts() []string {
+	if i != nil {
+		return i.MisdirectedHosts
+	}
+	return nil
 }
 
 func (g *MergedGateway) HasAutoPassthroughGateways() bool {
@@ -190,6 +206,11 @@ func mergeGateways(gateways []gatewayWithInstances, proxy *Proxy, ps *PushContex
 	verifiedCertificateReferences := sets.New[string]()
 	http3AdvertisingRoutes := sets.New[string]()
 	tlsHostsByPort := map[uint32]map[string]string{} // port -> host/bind map
+	gatewayAPIHTTPSServers := sets.New[*networking.Server]()
+	backendClientCertificate := ""
+	// All gateway configs merged onto the proxy share its clusters, so the backend client certificate is only
+	// applied if they all agree on it.
+	backendClientCertificates := sets.New[string]()
 	autoPassthrough := false
 
 	log.Debugf("mergeGateways: merging %d gateways", len(gateways))
@@ -198,6 +219,7 @@ func mergeGateways(gateways []gatewayWithInstances, proxy *Proxy, ps *PushContex
 		gatewayName := gatewayConfig.Namespace + "/" + gatewayConfig.Name // Format: %s/%s
 		gatewayCfg := gatewayConfig.Spec.(*networking.Gateway)
 		log.Debugf("mergeGateways: merging gateway %q :\n%v", gatewayName, gatewayCfg)
+		backendClientCertificates.Insert(gatewayConfig.Annotations[constants.InternalBackendClientCertificate])
 		snames := sets.String{}
 		for _, s := range gatewayCfg.Servers {
 			if len(s.Name) > 0 {
@@ -282,6 +304,15 @@ func mergeGateways(gateways []gatewayWithInstances, proxy *Proxy, ps *PushContex
 				}
 			}
 
+			// The backend client certificate is authorized with the same rules, as it is an SDS resource for the gateway.
+			if rn := gatewayConfig.Annotations[constants.InternalBackendClientCertificate]; rn != "" && identityVerified {
+				parse, err := credentials.ParseResourceName(rn, proxy.VerifiedIdentity.Namespace, "", "")
+				if (err == nil && configAndProxyAllowed && parse.Namespace == lookupNamespace) || ps.SecretAllowed(gwKind, rn, lookupNamespace) {
+					verifiedCertificateReferences.Insert(rn)
+					backendClientCertificate = rn
+				}
+			}
+
 			for _, resolvedPort := range resolvePorts(s.Port.Number, gwAndInstance.instances, gwAndInstance.legacyGatewaySelector) {
 				routeName := gatewayRDSRouteName(s, resolvedPort, gatewayConfig)
 				if s.Tls != nil {
@@ -301,6 +332,10 @@ func mergeGateways(gateways []gatewayWithInstances, proxy *Proxy, ps *PushContex
 						continue
 					}
 					tlsServerInfo[s] = &TLSServerInfo{SNIHosts: GetSNIHostsForServer(s), RouteName: routeName}
+					if gatewayConfig.Annotations[constants.InternalGatewaySemantics] == constants.GatewaySemanticsGateway &&
+						gateway.IsHTTPSServerWithTLSTermination(s) {
+						gatewayAPIHTTPSServers.Insert(s)
+					}
 					if s.Tls.Mode == networking.ServerTLSSettings_AUTO_PASSTHROUGH {
 						autoPassthrough = true
 					}
@@ -453,6 +488,7 @@ func mergeGateways(gateways []gatewayWithInstances, proxy *Proxy, ps *PushContex
 			}
 		}
 	}
+	setMisdirectedHosts(mergedServers, tlsServerInfo, gatewayAPIHTTPSServers)
 	autoPassthroughSNIHosts := sets.Set[string]{}
 	if autoPassthrough {
 		for _, tls := range mergedServers {
@@ -463,6 +499,11 @@ func mergeGateways(gateways []gatewayWithInstances, proxy *Proxy, ps *PushContex
 			}
 		}
 	}
+	if backendClientCertificates.Len() > 1 {
+		log.Warnf("mergeGateways: gateways merged onto proxy %s reference different backend client certificates %v, none is applied",
+			proxy.ID, sets.SortedList(backendClientCertificates))
+		backendClientCertificate = ""
+	}
 	return &MergedGateway{
 		MergedServers:                   mergedServers,
 		MergedQUICTransportServers:      mergedQUICServers,
@@ -475,6 +516,42 @@ func mergeGateways(gateways []gatewayWithInstances, proxy *Proxy, ps *PushContex
 		AutoPassthroughSNIHosts:         autoPassthroughSNIHosts,
 		PortMap:                         getTargetPortMap(serversByRouteName),
 		VerifiedCertificateReferences:   verifiedCertificateReferences,
+		BackendClientCertificate:        backendClientCertificate,
+	}
+}
+
+// setMisdirectedHosts computes the misdirected hosts of the Gateway API HTTPS servers sharing a port. Clients may
+// reuse a connection for any host covered by its certificate, so requests for a host that is served by another, more
+// specific, server must be rejected to make the client open a new connection for it. Servers without a wildcard
+// host reject all hosts they do not serve.
+func setMisdirectedHosts(mergedServers map[ServerPort]*MergedServers, tlsServerInfo map[*networking.Server]*TLSServerInfo,
+	gatewayAPIServers sets.Set[*networking.Server],
+) {
+	for _, ms := range mergedServers {
+		servers := slices.Filter(ms.Servers, gatewayAPIServers.Contains)
+		if len(servers) < 2 {
+			continue
+		}
+		for _, s := range servers {
+			info := tlsServerInfo[s]
+			misdirected := sets.New[string]()
+			if !slices.Contains(info.SNIHosts, "*") {
+				misdirected.Insert("*")
+			}
+			for _, other := range servers {
+				if other == s {
+					continue
+				}
+				for _, h := range tlsServerInfo[other].SNIHosts {
+					for _, own := range info.SNIHosts {
+						if h != own && host.Name(h).SubsetOf(host.Name(own)) {
+							misdirected.Insert(h)
+						}
+					}
+				}
+			}
+			info.MisdirectedHosts = sets.SortedList(misdirected)
+		}
 	}
 }
 
@@ -16,6 +16,8 @@ package model
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"testing"
 
 	networking "istio.io/api/networking/v1alpha3"
@@ -587,6 +589,159 @@ func makeInternalConfig(name, namespace, host, portName, portProtocol string, po
 	return c
 }
 
+func TestMergeGatewaysMisdirectedHosts(t *testing.T) {
+	tlsSimple := networking.ServerTLSSettings_SIMPLE
+	creds := []string{"kubernetes-gateway://ns/cert"}
+	wildcard := makeInternalConfig("wildcard", "ns", "*.example.com", "https-wildcard", "HTTPS", 443, "ingressgateway", "", tlsSimple, creds, "sa")
+	foo := makeInternalConfig("foo", "ns", "foo.example.com", "https-foo", "HTTPS", 443, "ingressgateway", "", tlsSimple, creds, "sa")
+	bar := makeInternalConfig("bar", "ns", "bar.example.com", "https-bar", "HTTPS", 443, "ingressgateway", "", tlsSimple, creds, "sa")
+	istioFoo := makeConfig("istio-foo", "ns", "foo.example.com", "https-foo", "HTTPS", 443, "ingressgateway", "", tlsSimple, creds, "sa")
+
+	tests := []struct {
+		name     string
+		gwConfig []config.Config
+		expected map[string][]string
+	}{
+		{
+			name:     "single server",
+			gwConfig: []config.Config{foo},
+			expected: map[string][]string{"foo": nil},
+		},
+		{
+			name:     "wildcard and specific server",
+			gwConfig: []config.Config{wildcard, foo},
+			expected: map[string][]string{
+				"wildcard": {"*", "foo.example.com"},
+				"foo":      {"*"},
+			},
+		},
+		{
+			name:     "unrelated servers",
+			gwConfig: []config.Config{foo, bar},
+			expected: map[string][]string{
+				"foo": {"*"},
+				"bar": {"*"},
+			},
+		},
+		{
+			name:     "istio gateway is ignored",
+			gwConfig: []config.Config{wildcard, istioFoo},
+			expected: map[string][]string{
+				"wildcard":  nil,
+				"istio-foo": nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instances := []gatewayWithInstances{}
+			for _, c := range tt.gwConfig {
+				instances = append(instances, gatewayWithInstances{c, true, nil})
+			}
+			mgw := mergeGateways(instances, &Proxy{}, makePushContext())
+			for s, info := range mgw.TLSServerInfo {
+				name := strings.TrimPrefix(mgw.GatewayNameForServer[s], "ns/")
+				expected, ok := tt.expected[name]
+				if !ok {
+					t.Fatalf("unexpected server for gateway %v", name)
+				}
+				if !slices.Equal(info.MisdirectedHosts, expected) {
+					t.Errorf("%v: expected misdirected hosts %v, got %v", name, expected, info.MisdirectedHosts)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeGatewaysBackendClientCertificate(t *testing.T) {
+	proxyIdentity := makeProxy(func() *spiffe.Identity {
+		identity, _ := spiffe.ParseIdentity("spiffe://td/ns/ns/sa/sa")
+		return &identity
+	})
+	withBackendCert := func(name, rn string) config.Config {
+		c := makeInternalConfig(name, "ns", name+".example.com", "http", "HTTP", 80, "ingressgateway", "",
+			networking.ServerTLSSettings_SIMPLE, []string{}, "sa")
+		if rn != "" {
+			c.Annotations[constants.InternalBackendClientCertificate] = rn
+		}
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		gwConfig []config.Config
+		proxy    *Proxy
+		expected string
+	}{
+		{
+			name:     "same namespace",
+			gwConfig: []config.Config{withBackendCert("foo", "kubernetes-gateway://ns/client")},
+			proxy:    proxyIdentity,
+			expected: "kubernetes-gateway://ns/client",
+		},
+		{
+			name:     "allowed namespace",
+			gwConfig: []config.Config{withBackendCert("foo", "kubernetes-gateway://"+AllowedNamespace+"/client")},
+			proxy:    proxyIdentity,
+			expected: "kubernetes-gateway://" + AllowedNamespace + "/client",
+		},
+		{
+			name:     "not allowed namespace",
+			gwConfig: []config.Config{withBackendCert("foo", "kubernetes-gateway://"+NotAllowedNamespace+"/client")},
+			proxy:    proxyIdentity,
+			expected: "",
+		},
+		{
+			name:     "unverified proxy",
+			gwConfig: []config.Config{withBackendCert("foo", "kubernetes-gateway://ns/client")},
+			proxy:    makeProxy(func() *spiffe.Identity { return nil }),
+			expected: "",
+		},
+		{
+			name: "merged gateways agree",
+			gwConfig: []config.Config{
+				withBackendCert("foo", "kubernetes-gateway://ns/client"),
+				withBackendCert("bar", "kubernetes-gateway://ns/client"),
+			},
+			proxy:    proxyIdentity,
+			expected: "kubernetes-gateway://ns/client",
+		},
+		{
+			name: "merged gateways disagree",
+			gwConfig: []config.Config{
+				withBackendCert("foo", "kubernetes-gateway://ns/client"),
+				withBackendCert("bar", "kubernetes-gateway://ns/other"),
+			},
+			proxy:    proxyIdentity,
+			expected: "",
+		},
+		{
+			name: "merged gateway without certificate",
+			gwConfig: []config.Config{
+				withBackendCert("foo", "kubernetes-gateway://ns/client"),
+				withBackendCert("bar", ""),
+			},
+			proxy:    proxyIdentity,
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instances := []gatewayWithInstances{}
+			for _, c := range tt.gwConfig {
+				instances = append(instances, gatewayWithInstances{c, true, nil})
+			}
+			mgw := mergeGateways(instances, tt.proxy, makePushContext())
+			if mgw.BackendClientCertificate != tt.expected {
+				t.Errorf("expected backend client certificate %q, got %q", tt.expected, mgw.BackendClientCertificate)
+			}
+			if tt.expected != "" && !mgw.VerifiedCertificateReferences.Contains(tt.expected) {
+				t.Errorf("expected %q to be a verified certificate reference, got %v", tt.expected, mgw.VerifiedCertificateReferences)
+			}
+		})
+	}
+}
+
 func TestFilterStrictGatewayMerging(t *testing.T) {
 	noCreds := []string{}
 	tlsSimple := networking.ServerTLSSettings_SIMPLE
@@ -707,6 +707,13 @@ var (
 		"Duplicate subsets across destination rules for same host",
 	)
 
+	// ProxylessGRPCUnsupportedPolicy tracks DestinationRule traffic policy settings that could not be applied
+	// to proxyless gRPC clients.
+	ProxylessGRPCUnsupportedPolicy = monitoring.NewGauge(
+		"pilot_grpc_unsupported_traffic_policy",
+		"DestinationRule traffic policy settings not supported by proxyless gRPC clients.",
+	)
+
 	// totalVirtualServices tracks the total number of virtual service
 	totalVirtualServices = monitoring.NewGauge(
 		"pilot_virt_services",
@@ -732,6 +739,7 @@ var (
 		ProxyStatusClusterNoInstances,
 		DuplicatedDomains,
 		DuplicatedSubsets,
+		ProxylessGRPCUnsupportedPolicy,
 	}
 )
 
@@ -307,6 +307,9 @@ const (
 	TrafficDirectionInboundVIP TrafficDirection = "inbound-vip"
 	// TrafficDirectionOutbound indicates outbound traffic
 	TrafficDirectionOutbound TrafficDirection = "outbound"
+	// TrafficDirectionOutboundUDP indicates outbound UDP traffic, as proxied by gateways. UDP clusters are
+	// kept apart from outbound clusters as a service may use the same port number for both TCP and UDP.
+	TrafficDirectionOutboundUDP TrafficDirection = "outbound-udp"
 
 	// trafficDirectionOutboundSrvPrefix the prefix for a DNS SRV type subset key
 	trafficDirectionOutboundSrvPrefix = string(TrafficDirectionOutbound) + "_"
@@ -1832,6 +1835,16 @@ func (ports PortList) GetByPort(num int) (*Port, bool) {
 	return nil, false
 }
 
+// GetUDPByPort retrieves a UDP port declaration by port value
+func (ports PortList) GetUDPByPort(num int) (*Port, bool) {
+	for _, port := range ports {
+		if port.Port == num && port.Protocol == protocol.UDP {
+			return port, true
+		}
+	}
+	return nil, false
+}
+
 func (p *Port) Equals(other *Port) bool {
 	return p.Name == other.Name && p.Port == other.Port && p.Protocol == other.Protocol
 }
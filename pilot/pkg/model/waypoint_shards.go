@@ -0,0 +1,69 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strconv"
+
+	"github.com/cespare/xxhash/v2"
+
+	"istio.io/istio/pkg/config/constants"
+)
+
+// MaxWaypointShards bounds the number of shards of a waypoint, as each shard is a Deployment.
+const MaxWaypointShards = 64
+
+// WaypointShards returns the number of shards of a waypoint Gateway from its annotations, or 0 if the waypoint is not
+// sharded. Invalid values, and a single shard, leave the waypoint unsharded.
+func WaypointShards(annotations map[string]string) int {
+	v, f := annotations[constants.WaypointShardsAnnotation]
+	if !f {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 2 || n > MaxWaypointShards {
+		return 0
+	}
+	return n
+}
+
+// WaypointShardName returns the name of the Deployment and Service of a shard of a waypoint.
+func WaypointShardName(name string, shard int) string {
+	return name + "-shard-" + strconv.Itoa(shard)
+}
+
+// WaypointShardFor returns the shard of a waypoint serving a Service. A Service can be pinned to a shard with the
+// shard label; otherwise its namespace and name are hashed with a consistent hash, so that changing the number of
+// shards moves as few Services as possible.
+func WaypointShardFor(namespace, name string, labels map[string]string, shards int) int {
+	if v, f := labels[constants.WaypointShardLabel]; f {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 && n < shards {
+			return n
+		}
+	}
+	return jumpHash(xxhash.Sum64String(namespace+"/"+name), shards)
+}
+
+// jumpHash implements the jump consistent hash from "A Fast, Minimal Memory, Consistent Hash Algorithm" (Lamping
+// and Veach).
+func jumpHash(key uint64, buckets int) int {
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
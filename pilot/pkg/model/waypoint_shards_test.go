@@ -0,0 +1,80 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/test/util/assert"
+)
+
+func TestWaypointShards(t *testing.T) {
+	cases := []struct {
+		value string
+		want  int
+	}{
+		{"", 0},
+		{"abc", 0},
+		{"-1", 0},
+		{"1", 0},
+		{"2", 2},
+		{"64", 64},
+		{"65", 0},
+	}
+	for _, tt := range cases {
+		t.Run(tt.value, func(t *testing.T) {
+			annotations := map[string]string{}
+			if tt.value != "" {
+				annotations[constants.WaypointShardsAnnotation] = tt.value
+			}
+			assert.Equal(t, WaypointShards(annotations), tt.want)
+		})
+	}
+}
+
+func TestWaypointShardFor(t *testing.T) {
+	t.Run("pinned", func(t *testing.T) {
+		assert.Equal(t, WaypointShardFor("default", "reviews", map[string]string{constants.WaypointShardLabel: "2"}, 3), 2)
+	})
+	t.Run("invalid pin", func(t *testing.T) {
+		assert.Equal(t,
+			WaypointShardFor("default", "reviews", map[string]string{constants.WaypointShardLabel: "3"}, 3),
+			WaypointShardFor("default", "reviews", nil, 3))
+	})
+	t.Run("consistent", func(t *testing.T) {
+		counts := make([]int, 4)
+		moved := 0
+		for i := range 1000 {
+			name := fmt.Sprintf("svc-%d", i)
+			shard := WaypointShardFor("default", name, nil, 4)
+			counts[shard]++
+			// Adding a shard only moves Services to the new shard.
+			if grown := WaypointShardFor("default", name, nil, 5); grown != shard {
+				assert.Equal(t, grown, 4)
+				moved++
+			}
+		}
+		for _, c := range counts {
+			if c < 200 || c > 300 {
+				t.Fatalf("unbalanced shards: %v", counts)
+			}
+		}
+		if moved < 150 || moved > 250 {
+			t.Fatalf("expected about a fifth of services to move, got %d", moved)
+		}
+	})
+}
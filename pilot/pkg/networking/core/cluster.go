@@ -99,10 +99,11 @@ func (configgen *ConfigGeneratorImpl) BuildDeltaClusters(proxy *model.Proxy, upd
 		// check with the name of our service (cluster names are in the format outbound|<port>|<subset>|<hostname>).
 		dir, subset, svcHost, port := model.ParseSubsetKey(cluster)
 		// Inbound clusters don't have svchost in its format. So don't add it to serviceClusters.
-		if dir == model.TrafficDirectionInbound {
+		if dir == model.TrafficDirectionInbound || dir == model.TrafficDirectionOutboundUDP {
 			// Append all inbound clusters because in both stow/delta we always build all inbound clusters.
 			// In reality, the delta building is only for outbound clusters. We need to revisit here once we support delta for inbound.
 			// So deletedClusters.Difference(builtClusters) would give us the correct deleted inbound clusters.
+			// The same applies to gateway UDP clusters, which are always built in full.
 			deletedClusters.Insert(cluster)
 		} else {
 			if subset == "" {
@@ -376,6 +377,7 @@ func (configgen *ConfigGeneratorImpl) buildClusters(proxy *model.Proxy, req *mod
 		if proxy.Type == model.Router && proxy.MergedGateway != nil && proxy.MergedGateway.ContainsAutoPassthroughGateways {
 			clusters = append(clusters, configgen.buildOutboundSniDnatClusters(proxy, req, patcher)...)
 		}
+		clusters = append(clusters, configgen.buildGatewayUDPClusters(cb, proxy, patcher)...)
 		clusters = append(clusters, patcher.insertedClusters()...)
 		// Ingress gateway needs the clusters necessary for Double HBONE communications
 		// that happen cross cluster. A request arrives at the ingress and the LB
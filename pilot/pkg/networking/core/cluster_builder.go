@@ -148,6 +148,8 @@ type ClusterBuilder struct {
 	cache                     model.XdsCache
 	credentialSocketExist     bool
 	fileCredentialSocketExist bool
+	// Client certificate presented by gateways to TLS backends.
+	backendClientCertificate string
 }
 
 // NewClusterBuilder builds an instance of ClusterBuilder.
@@ -170,6 +172,9 @@ func NewClusterBuilder(proxy *model.Proxy, req *model.PushRequest, cache model.X
 		req:                req,
 		cache:              cache,
 	}
+	if proxy.MergedGateway != nil {
+		cb.backendClientCertificate = proxy.MergedGateway.BackendClientCertificate
+	}
 	if proxy.Metadata != nil {
 		if proxy.Metadata.TLSClientCertChain != "" {
 			cb.metadataCerts = &metadataCerts{
@@ -47,6 +47,8 @@ type clusterCache struct {
 	proxyView               model.ProxyView
 	metadataCerts           *metadataCerts // metadata certificates of proxy
 	endpointBuilder         *endpoints.EndpointBuilder
+	// client certificate presented by gateways to TLS backends
+	backendClientCertificate string
 
 	// service attributes
 	http2          bool // http2 identifies if the cluster is for an http2 service
@@ -98,6 +100,9 @@ func (t *clusterCache) Key() any {
 	}
 	h.Write(Separator)
 
+	h.WriteString(t.backendClientCertificate)
+	h.Write(Separator)
+
 	if t.service != nil {
 		h.WriteString(string(t.service.Hostname))
 		h.Write(Slash)
@@ -192,23 +197,24 @@ func buildClusterKey(service *model.Service, port *model.Port, cb *ClusterBuilde
 		)
 	}
 	return clusterCache{
-		clusterName:             clusterName,
-		proxyVersion:            cb.proxyVersion.String(),
-		locality:                cb.locality,
-		preserveHTTP1HeaderCase: shouldPreserveHeaderCase(cb.proxyMetadata, cb.req.Push),
-		proxyClusterID:          cb.clusterID,
-		proxyType:               cb.proxyType,
-		proxyView:               cb.proxyView,
-		hbone:                   cb.sendHbone,
-		http2:                   port.Protocol.IsHTTP2(),
-		downstreamAuto:          cb.sidecarProxy() && port.Protocol.IsUnsupported(),
-		supportsIPv4:            cb.supportsIPv4,
-		service:                 service,
-		destinationRule:         dr,
-		envoyFilterKeys:         efKeys,
-		metadataCerts:           cb.metadataCerts,
-		peerAuthVersion:         cb.sidecarScope.AuthnPolicies.GetVersion(),
-		serviceAccounts:         cb.req.Push.ServiceAccounts(service.Hostname, service.Attributes.Namespace),
-		endpointBuilder:         eb,
+		clusterName:              clusterName,
+		proxyVersion:             cb.proxyVersion.String(),
+		locality:                 cb.locality,
+		preserveHTTP1HeaderCase:  shouldPreserveHeaderCase(cb.proxyMetadata, cb.req.Push),
+		proxyClusterID:           cb.clusterID,
+		proxyType:                cb.proxyType,
+		proxyView:                cb.proxyView,
+		hbone:                    cb.sendHbone,
+		http2:                    port.Protocol.IsHTTP2(),
+		downstreamAuto:           cb.sidecarProxy() && port.Protocol.IsUnsupported(),
+		supportsIPv4:             cb.supportsIPv4,
+		service:                  service,
+		destinationRule:          dr,
+		envoyFilterKeys:          efKeys,
+		metadataCerts:            cb.metadataCerts,
+		peerAuthVersion:          cb.sidecarScope.AuthnPolicies.GetVersion(),
+		serviceAccounts:          cb.req.Push.ServiceAccounts(service.Hostname, service.Attributes.Namespace),
+		endpointBuilder:          eb,
+		backendClientCertificate: cb.backendClientCertificate,
 	}
 }
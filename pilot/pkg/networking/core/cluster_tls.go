@@ -176,6 +176,11 @@ func (cb *ClusterBuilder) buildUpstreamClusterTLSContext(opts *buildClusterOpts,
 		}
 	case networking.ClientTLSSettings_SIMPLE:
 		tlsContext, err = constructUpstreamTLS(opts, tls, c, false)
+		// Gateways may be configured to present a client certificate to all TLS backends.
+		if err == nil && cb.backendClientCertificate != "" && len(tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs) == 0 {
+			tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs = append(tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs,
+				sec_model.ConstructSdsSecretConfigForCredential(cb.backendClientCertificate, opts.credentialSocketExist, nil))
+		}
 
 	case networking.ClientTLSSettings_MUTUAL:
 		tlsContext, err = constructUpstreamTLS(opts, tls, c, true)
@@ -1765,6 +1765,42 @@ func TestBuildUpstreamClusterTLSContext(t *testing.T) {
 	}
 }
 
+func TestBuildUpstreamClusterTLSContextBackendClientCertificate(t *testing.T) {
+	cases := []struct {
+		name     string
+		tls      *networking.ClientTLSSettings
+		expected []string
+	}{
+		{
+			name:     "simple",
+			tls:      &networking.ClientTLSSettings{Mode: networking.ClientTLSSettings_SIMPLE},
+			expected: []string{"kubernetes-gateway://ns/client"},
+		},
+		{
+			name: "mutual keeps its own certificate",
+			tls: &networking.ClientTLSSettings{
+				Mode:              networking.ClientTLSSettings_MUTUAL,
+				ClientCertificate: "/path/to/cert",
+				PrivateKey:        "/path/to/key",
+			},
+			expected: []string{"file-cert:/path/to/cert~/path/to/key"},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy := newGatewayProxy()
+			proxy.MergedGateway = &model.MergedGateway{BackendClientCertificate: "kubernetes-gateway://ns/client"}
+			cb := NewClusterBuilder(proxy, nil, model.DisabledCache{})
+			ret, err := cb.buildUpstreamClusterTLSContext(&buildClusterOpts{mutable: newTestCluster()}, tt.tls)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := slices.Map(ret.CommonTlsContext.TlsCertificateSdsSecretConfigs, (*tls.SdsSecretConfig).GetName)
+			assert.Equal(t, names, tt.expected)
+		})
+	}
+}
+
 func TestBuildAutoMtlsSettings(t *testing.T) {
 	tlsSettings := &networking.ClientTLSSettings{
 		Mode:            networking.ClientTLSSettings_ISTIO_MUTUAL,
@@ -17,6 +17,7 @@ package core
 import (
 	"encoding/binary"
 	"fmt"
+	"net/http"
 	"sort"
 	"strconv"
 	"strings"
@@ -131,6 +132,8 @@ func (configgen *ConfigGeneratorImpl) buildGatewayListeners(builder *ListenerBui
 	proxyConfig := builder.node.Metadata.ProxyConfigOrDefault(builder.push.Mesh.DefaultConfig)
 	// listener port -> host/bind
 	tlsHostsByPort := map[uint32]map[string]string{}
+	// UDP servers are proxied by a listener filter rather than filter chains, so their listeners are built directly.
+	udpListeners := make([]*listener.Listener, 0)
 	for _, port := range mergedGateway.ServerPorts {
 		// Skip ports we cannot bind to. Note that mergeGateways will already translate Service port to
 		// targetPort, which handles the common case of exposing ports like 80 and 443 but listening on
@@ -150,6 +153,13 @@ func (configgen *ConfigGeneratorImpl) buildGatewayListeners(builder *ListenerBui
 			extraBind = nil
 		}
 
+		if protocol.Parse(port.Protocol) == protocol.UDP {
+			if l := buildGatewayUDPListener(builder, port, bind, extraBind); l != nil {
+				udpListeners = append(udpListeners, l)
+			}
+			continue
+		}
+
 		// NOTE: There is no gating here to check for the value of the QUIC feature flag. However,
 		// they are created in MergeGatways only when the flag is set. So when it is turned off, the
 		// MergedQUICTransportServers would be nil so that no listener would be created. It is written this way
@@ -228,6 +238,14 @@ func (configgen *ConfigGeneratorImpl) buildGatewayListeners(builder *ListenerBui
 		}
 		listeners = append(listeners, ml.mutable.Listener)
 	}
+	for _, l := range udpListeners {
+		// HTTP/3 listeners also bind to UDP, and cannot share the port.
+		if _, f := mutableopts[l.Name]; f {
+			errs = multierror.Append(errs, fmt.Errorf("gateway omitting UDP listener %q due to a conflicting HTTP/3 listener", l.Name))
+			continue
+		}
+		listeners = append(listeners, l)
+	}
 	// We'll try to return any listeners we successfully marshaled; if we have none, we'll emit the error we built up
 	err := errs.ErrorOrNil()
 	if err != nil {
@@ -235,7 +253,7 @@ func (configgen *ConfigGeneratorImpl) buildGatewayListeners(builder *ListenerBui
 		log.Info(err.Error())
 	}
 
-	if len(mutableopts) == 0 {
+	if len(mutableopts) == 0 && len(udpListeners) == 0 {
 		log.Warnf("gateway has zero listeners for node %v", builder.node.ID)
 		return builder
 	}
@@ -552,13 +570,26 @@ func (configgen *ConfigGeneratorImpl) buildGatewayHTTPRouteConfig(node *model.Pr
 		}
 	}
 
+	misdirectedHosts := sets.New[string]()
+	for _, server := range servers {
+		misdirectedHosts.InsertAll(merged.TLSServerInfo[server].GetMisdirectedHosts()...)
+	}
+
 	var virtualHosts []*route.VirtualHost
 	if len(vHostDedupMap) == 0 {
 		port := int(servers[0].Port.Number)
 		log.Warnf("constructed http route config for route %s on port %d with no vhosts; Setting up a default 404 vhost", routeName, port)
+		domains := []string{"*"}
+		if misdirectedHosts.Contains("*") {
+			// Other hosts are misdirected; only the hosts of the server itself should get a 404.
+			domains = nil
+			for _, server := range servers {
+				domains = append(domains, merged.TLSServerInfo[server].SNIHosts...)
+			}
+		}
 		virtualHosts = []*route.VirtualHost{{
 			Name:    util.DomainName("blackhole", port),
-			Domains: []string{"*"},
+			Domains: domains,
 			// Empty route list will cause Envoy to 404 NR any requests
 			Routes: []*route.Route{},
 		}}
@@ -571,6 +602,10 @@ func (configgen *ConfigGeneratorImpl) buildGatewayHTTPRouteConfig(node *model.Pr
 		}
 	}
 
+	if vHost := buildMisdirectedVirtualHost(misdirectedHosts, virtualHosts, int(servers[0].Port.Number)); vHost != nil {
+		virtualHosts = append(virtualHosts, vHost)
+	}
+
 	util.SortVirtualHosts(virtualHosts)
 
 	routeCfg := &route.RouteConfiguration{
@@ -585,6 +620,35 @@ func (configgen *ConfigGeneratorImpl) buildGatewayHTTPRouteConfig(node *model.Pr
 	return routeCfg
 }
 
+// buildMisdirectedVirtualHost builds a virtual host answering requests for hosts served by another server on the
+// same port with 421 (Misdirected Request), so the client retries on a new connection. Hosts that already have a
+// virtual host are skipped.
+func buildMisdirectedVirtualHost(misdirectedHosts sets.String, virtualHosts []*route.VirtualHost, port int) *route.VirtualHost {
+	for _, vh := range virtualHosts {
+		for _, d := range vh.Domains {
+			misdirectedHosts.Delete(d)
+		}
+	}
+	if len(misdirectedHosts) == 0 {
+		return nil
+	}
+	return &route.VirtualHost{
+		Name:    util.DomainName("misdirected", port),
+		Domains: sets.SortedList(misdirectedHosts),
+		Routes: []*route.Route{{
+			Name: "misdirected",
+			Match: &route.RouteMatch{
+				PathSpecifier: &route.RouteMatch_Prefix{Prefix: "/"},
+			},
+			Action: &route.Route_DirectResponse{
+				DirectResponse: &route.DirectResponseAction{
+					Status: http.StatusMisdirectedRequest,
+				},
+			},
+		}},
+	}
+}
+
 // hashRouteList returns a hash of a list of pointers
 func hashRouteList(r []*route.Route) uint64 {
 	// nolint: gosec
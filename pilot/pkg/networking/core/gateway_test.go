@@ -15,6 +15,7 @@
 package core
 
 import (
+	"net/http"
 	"reflect"
 	"sort"
 	"strings"
@@ -2855,6 +2856,121 @@ func TestGatewayHTTPRouteConfig(t *testing.T) {
 	}
 }
 
+func TestGatewayHTTPRouteConfigMisdirected(t *testing.T) {
+	cfg := `
+apiVersion: networking.istio.io/v1
+kind: Gateway
+metadata:
+  name: wildcard
+  namespace: default
+  annotations:
+    internal.istio.io/gateway-semantics: gateway
+spec:
+  selector:
+    istio: ingressgateway
+  servers:
+  - port:
+      number: 443
+      name: https-wildcard
+      protocol: HTTPS
+    hosts:
+    - "*.example.com"
+    tls:
+      mode: SIMPLE
+      credentialName: cert
+---
+apiVersion: networking.istio.io/v1
+kind: Gateway
+metadata:
+  name: foo
+  namespace: default
+  annotations:
+    internal.istio.io/gateway-semantics: gateway
+spec:
+  selector:
+    istio: ingressgateway
+  servers:
+  - port:
+      number: 443
+      name: https-foo
+      protocol: HTTPS
+    hosts:
+    - foo.example.com
+    tls:
+      mode: SIMPLE
+      credentialName: cert
+---
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: wildcard
+  namespace: default
+spec:
+  hosts:
+  - "*.example.com"
+  gateways:
+  - wildcard
+  http:
+  - route:
+    - destination:
+        host: example.org
+---
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: foo
+  namespace: default
+spec:
+  hosts:
+  - foo.example.com
+  gateways:
+  - foo
+  http:
+  - route:
+    - destination:
+        host: example.org
+`
+	cases := []struct {
+		routeName            string
+		expectedVirtualHosts map[string][]string
+	}{
+		{
+			routeName: "https.443.https-wildcard.wildcard.default",
+			expectedVirtualHosts: map[string][]string{
+				"*.example.com:443": {"*.example.com"},
+				"misdirected:443":   {"*", "foo.example.com"},
+			},
+		},
+		{
+			routeName: "https.443.https-foo.foo.default",
+			expectedVirtualHosts: map[string][]string{
+				"foo.example.com:443": {"foo.example.com"},
+				"misdirected:443":     {"*"},
+			},
+		},
+	}
+	cg := NewConfigGenTest(t, TestOptions{ConfigString: cfg})
+	proxy := cg.SetupProxy(&proxyGateway)
+	for _, tt := range cases {
+		t.Run(tt.routeName, func(t *testing.T) {
+			r := cg.ConfigGen.buildGatewayHTTPRouteConfig(proxy, cg.PushContext(), tt.routeName)
+			if r == nil {
+				t.Fatal("got an empty route configuration")
+			}
+			vh := make(map[string][]string)
+			for _, h := range r.VirtualHosts {
+				vh[h.Name] = h.Domains
+				if h.Name == "misdirected:443" && h.Routes[0].GetDirectResponse().GetStatus() != http.StatusMisdirectedRequest {
+					t.Errorf("expected misdirected requests to get a 421 response, got %v", h.Routes[0].GetAction())
+				}
+			}
+			if !maps.EqualFunc(tt.expectedVirtualHosts, vh, slices.Equal) {
+				t.Errorf("got unexpected virtual hosts. Expected: %v, Got: %v", tt.expectedVirtualHosts, vh)
+			}
+		})
+	}
+}
+
 func TestBuildGatewayListeners(t *testing.T) {
 	cases := []struct {
 		name              string
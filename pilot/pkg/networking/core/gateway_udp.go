@@ -0,0 +1,258 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	xds "github.com/cncf/xds/go/xds/core/v3"
+	matcher "github.com/cncf/xds/go/xds/type/matcher/v3"
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	udpproxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/udp/udp_proxy/v3"
+	consistenthashing "github.com/envoyproxy/go-control-plane/envoy/extensions/matching/input_matchers/consistent_hashing/v3"
+
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/pkg/model"
+	istionetworking "istio.io/istio/pilot/pkg/networking"
+	"istio.io/istio/pilot/pkg/networking/core/match"
+	"istio.io/istio/pilot/pkg/networking/util"
+	"istio.io/istio/pilot/pkg/util/protoconv"
+	"istio.io/istio/pilot/pkg/xds/endpoints"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/protocol"
+	"istio.io/istio/pkg/log"
+	"istio.io/istio/pkg/util/sets"
+	"istio.io/istio/pkg/wellknown"
+)
+
+// gatewayUDPRoutes returns the destinations of the first VirtualService TCP route that applies to a UDP server.
+// UDPRoutes are converted to VirtualServices with TCP blocks, as UDP has no further matching capabilities.
+func gatewayUDPRoutes(push *model.PushContext, node *model.Proxy, server *networking.Server, gatewayName string) []*networking.RouteDestination {
+	gatewayServerHosts := sets.NewWithLength[host.Name](len(server.Hosts))
+	for _, hostname := range server.Hosts {
+		gatewayServerHosts.Insert(host.Name(hostname))
+	}
+	for _, v := range push.VirtualServicesForGateway(node.ConfigNamespace, gatewayName) {
+		if len(pickMatchingGatewayHosts(gatewayServerHosts, v)) == 0 {
+			continue
+		}
+		for _, tcp := range v.Spec.(*networking.VirtualService).Tcp {
+			if len(tcp.Route) > 0 && l4MultiMatch(tcp.Match, server, gatewayName) {
+				return tcp.Route
+			}
+		}
+	}
+	return nil
+}
+
+// udpDestinationPort returns the port of the service a UDP destination refers to. If no port is specified,
+// the only UDP port of the service is used, falling back to the listener port.
+func udpDestinationPort(destination *networking.Destination, service *model.Service, listenerPort int) int {
+	if destination.GetPort() != nil {
+		return int(destination.GetPort().GetNumber())
+	}
+	if service != nil {
+		var udpPorts model.PortList
+		for _, p := range service.Ports {
+			if p.Protocol == protocol.UDP {
+				udpPorts = append(udpPorts, p)
+			}
+		}
+		if len(udpPorts) == 1 {
+			return udpPorts[0].Port
+		}
+	}
+	return listenerPort
+}
+
+// udpDestinationCluster returns the name of the UDP cluster for a destination.
+func udpDestinationCluster(push *model.PushContext, node *model.Proxy, destination *networking.Destination, listenerPort int) string {
+	service := push.ServiceForHostname(node, host.Name(destination.Host))
+	return model.BuildSubsetKey(model.TrafficDirectionOutboundUDP, destination.Subset, host.Name(destination.Host),
+		udpDestinationPort(destination, service, listenerPort))
+}
+
+// buildGatewayUDPListener builds a listener proxying UDP datagrams for the UDP servers on a port.
+// Returns nil if no route applies to the servers.
+func buildGatewayUDPListener(builder *ListenerBuilder, port model.ServerPort, bind string, extraBind []string) *listener.Listener {
+	mergedGateway := builder.node.MergedGateway
+	serversForPort := mergedGateway.MergedServers[port]
+	if serversForPort == nil {
+		return nil
+	}
+	var routes []*networking.RouteDestination
+	for _, server := range serversForPort.Servers {
+		if routes = gatewayUDPRoutes(builder.push, builder.node, server, mergedGateway.GatewayNameForServer[server]); len(routes) > 0 {
+			break
+		}
+	}
+	if len(routes) == 0 {
+		log.Debugf("gateway UDP listener on port %d has no routes", port.Number)
+		return nil
+	}
+
+	name := getListenerName(bind, int(port.Number), istionetworking.TransportProtocolQUIC)
+	var clusters []string
+	var weights []uint32
+	for _, r := range routes {
+		// A route without weight is the only route, which receives all traffic.
+		if r.Weight == 0 && len(routes) > 1 {
+			continue
+		}
+		clusters = append(clusters, udpDestinationCluster(builder.push, builder.node, r.Destination, int(port.Number)))
+		weights = append(weights, uint32(r.Weight))
+	}
+	statPrefix := name
+	if len(clusters) == 1 {
+		statPrefix = clusters[0]
+	}
+	udpProxy := &udpproxy.UdpProxyConfig{
+		StatPrefix: statPrefix,
+		RouteSpecifier: &udpproxy.UdpProxyConfig_Matcher{
+			Matcher: buildUDPRouteMatcher(clusters, weights),
+		},
+	}
+
+	l := &listener.Listener{
+		Name:             name,
+		Address:          util.BuildNetworkAddress(bind, port.Number, istionetworking.TransportProtocolQUIC),
+		TrafficDirection: core.TrafficDirection_OUTBOUND,
+		UdpListenerConfig: &listener.UdpListenerConfig{
+			DownstreamSocketConfig: &core.UdpSocketConfig{},
+		},
+		ListenerFilters: []*listener.ListenerFilter{{
+			Name:       wellknown.UDPProxy,
+			ConfigType: &listener.ListenerFilter_TypedConfig{TypedConfig: protoconv.MessageToAny(udpProxy)},
+		}},
+	}
+	if features.EnableDualStack && len(extraBind) > 0 {
+		l.AdditionalAddresses = util.BuildAdditionalAddresses(extraBind, port.Number)
+		for _, additionalAddress := range l.AdditionalAddresses {
+			additionalAddress.GetAddress().GetSocketAddress().Protocol = core.SocketAddress_UDP
+		}
+	}
+	return l
+}
+
+// buildUDPRouteMatcher builds the matcher selecting the cluster of each UDP session. When there are multiple
+// clusters, sessions are spread by the hash of the source address, in proportion to the weight of each cluster.
+func buildUDPRouteMatcher(clusters []string, weights []uint32) *matcher.Matcher {
+	routeTo := func(cluster string) *matcher.Matcher_OnMatch {
+		return &matcher.Matcher_OnMatch{
+			OnMatch: &matcher.Matcher_OnMatch_Action{
+				Action: &xds.TypedExtensionConfig{
+					Name:        "route",
+					TypedConfig: protoconv.MessageToAny(&udpproxy.Route{Cluster: cluster}),
+				},
+			},
+		}
+	}
+	m := &matcher.Matcher{OnNoMatch: routeTo(clusters[0])}
+	if len(clusters) == 1 {
+		return m
+	}
+
+	// Each cluster receives the hashes in [sum of the previous weights, sum of the weights up to this one).
+	// Matchers are evaluated in order, so they are listed from the last cluster down to the second one; the first
+	// cluster receives the remaining hashes.
+	thresholds := make([]uint32, len(weights))
+	var total uint32
+	for i, w := range weights {
+		thresholds[i] = total
+		total += w
+	}
+	list := &matcher.Matcher_MatcherList{}
+	for i := len(clusters) - 1; i > 0; i-- {
+		list.Matchers = append(list.Matchers, &matcher.Matcher_MatcherList_FieldMatcher{
+			Predicate: &matcher.Matcher_MatcherList_Predicate{
+				MatchType: &matcher.Matcher_MatcherList_Predicate_SinglePredicate_{
+					SinglePredicate: &matcher.Matcher_MatcherList_Predicate_SinglePredicate{
+						Input: match.SourceIP,
+						Matcher: &matcher.Matcher_MatcherList_Predicate_SinglePredicate_CustomMatch{
+							CustomMatch: &xds.TypedExtensionConfig{
+								Name: "envoy.matching.matchers.consistent_hashing",
+								TypedConfig: protoconv.MessageToAny(&consistenthashing.ConsistentHashing{
+									Threshold: thresholds[i],
+									Modulo:    total,
+								}),
+							},
+						},
+					},
+				},
+			},
+			OnMatch: routeTo(clusters[i]),
+		})
+	}
+	m.MatcherType = &matcher.Matcher_MatcherList_{MatcherList: list}
+	return m
+}
+
+// buildGatewayUDPClusters builds the clusters for the destinations of UDP gateway servers.
+func (configgen *ConfigGeneratorImpl) buildGatewayUDPClusters(cb *ClusterBuilder, proxy *model.Proxy,
+	cp clusterPatcher,
+) []*cluster.Cluster {
+	clusters := make([]*cluster.Cluster, 0)
+	if proxy.MergedGateway == nil {
+		return clusters
+	}
+	push := cb.req.Push
+	built := sets.New[string]()
+	for _, port := range proxy.MergedGateway.ServerPorts {
+		if protocol.Parse(port.Protocol) != protocol.UDP || proxy.MergedGateway.MergedServers[port] == nil {
+			continue
+		}
+		for _, server := range proxy.MergedGateway.MergedServers[port].Servers {
+			gatewayName := proxy.MergedGateway.GatewayNameForServer[server]
+			for _, r := range gatewayUDPRoutes(push, proxy, server, gatewayName) {
+				service := push.ServiceForHostname(proxy, host.Name(r.Destination.Host))
+				if service == nil {
+					continue
+				}
+				svcPort, f := service.Ports.GetUDPByPort(udpDestinationPort(r.Destination, service, int(port.Number)))
+				if !f {
+					continue
+				}
+				subset := r.Destination.Subset
+				clusterName := model.BuildSubsetKey(model.TrafficDirectionOutboundUDP, subset, service.Hostname, svcPort.Port)
+				if built.InsertContains(clusterName) {
+					continue
+				}
+
+				discoveryType := convertResolution(cb.proxyType, service)
+				if discoveryType == cluster.Cluster_ORIGINAL_DST {
+					// There is no original destination to forward to, as the gateway is the destination.
+					continue
+				}
+				var lbEndpoints []*endpoint.LocalityLbEndpoints
+				if discoveryType == cluster.Cluster_STRICT_DNS || discoveryType == cluster.Cluster_LOGICAL_DNS {
+					destRule := proxy.SidecarScope.DestinationRule(model.TrafficDirectionOutbound, proxy, service.Hostname)
+					lbEndpoints = endpoints.NewCDSEndpointBuilder(proxy, push, clusterName, model.TrafficDirectionOutboundUDP,
+						subset, service.Hostname, svcPort.Port, service, destRule,
+					).FromServiceEndpoints()
+				}
+				c := cb.buildCluster(clusterName, discoveryType, lbEndpoints, model.TrafficDirectionOutbound, svcPort, service, nil, subset)
+				if c == nil {
+					continue
+				}
+				c.cluster.ConnectTimeout = push.Mesh.ConnectTimeout
+				maybeApplyEdsConfig(c.cluster)
+				clusters = cp.conditionallyAppend(clusters, []host.Name{service.Hostname}, c.build())
+			}
+		}
+	}
+	return clusters
+}
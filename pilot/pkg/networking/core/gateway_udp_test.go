@@ -0,0 +1,175 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	matcher "github.com/cncf/xds/go/xds/type/matcher/v3"
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	udpproxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/udp/udp_proxy/v3"
+	consistenthashing "github.com/envoyproxy/go-control-plane/envoy/extensions/matching/input_matchers/consistent_hashing/v3"
+
+	"istio.io/istio/pilot/test/xdstest"
+	"istio.io/istio/pkg/test/util/assert"
+	"istio.io/istio/pkg/wellknown"
+)
+
+const udpGatewayConfig = `
+apiVersion: networking.istio.io/v1
+kind: Gateway
+metadata:
+  name: dns
+  namespace: not-default
+spec:
+  selector:
+    istio: ingressgateway
+  servers:
+  - port:
+      number: 5353
+      name: dns
+      protocol: UDP
+    hosts:
+    - "*"
+  - port:
+      number: 5354
+      name: dns-unrouted
+      protocol: UDP
+    hosts:
+    - "*"
+---
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: dns
+  namespace: not-default
+spec:
+  hosts:
+  - "*"
+  gateways:
+  - dns
+  tcp:
+  - match:
+    - port: 5353
+    route:
+    - destination:
+        host: dns.example.com
+      weight: 25
+    - destination:
+        host: dns-alt.example.com
+        port:
+          number: 53
+      weight: 75
+---
+apiVersion: networking.istio.io/v1
+kind: ServiceEntry
+metadata:
+  name: dns
+  namespace: not-default
+spec:
+  hosts:
+  - dns.example.com
+  ports:
+  - number: 8053
+    name: tcp-dns
+    protocol: TCP
+  - number: 53
+    name: udp-dns
+    protocol: UDP
+  resolution: STATIC
+  endpoints:
+  - address: 10.0.0.1
+---
+apiVersion: networking.istio.io/v1
+kind: ServiceEntry
+metadata:
+  name: dns-alt
+  namespace: not-default
+spec:
+  hosts:
+  - dns-alt.example.com
+  ports:
+  - number: 53
+    name: udp-dns
+    protocol: UDP
+  resolution: DNS
+  endpoints:
+  - address: alt.example.com
+`
+
+func TestGatewayUDPListener(t *testing.T) {
+	cg := NewConfigGenTest(t, TestOptions{ConfigString: udpGatewayConfig})
+	proxy := cg.SetupProxy(&proxyGateway)
+	listeners := cg.Listeners(proxy)
+
+	if l := xdstest.ExtractListener("udp_0.0.0.0_5354", listeners); l != nil {
+		t.Fatalf("unexpected listener for a UDP server without routes: %v", l.Name)
+	}
+	l := xdstest.ExtractListener("udp_0.0.0.0_5353", listeners)
+	if l == nil {
+		t.Fatalf("expected UDP listener, got %v", xdstest.ExtractListenerNames(listeners))
+	}
+	assert.Equal(t, l.GetAddress().GetSocketAddress().GetProtocol(), core.SocketAddress_UDP)
+	assert.Equal(t, len(l.GetFilterChains()), 0)
+
+	udpProxy := &udpproxy.UdpProxyConfig{}
+	if err := xdstest.ExtractListenerFilters(l)[wellknown.UDPProxy].GetTypedConfig().UnmarshalTo(udpProxy); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, udpProxy.GetStatPrefix(), "udp_0.0.0.0_5353")
+	m := udpProxy.GetMatcher()
+	assert.Equal(t, udpRouteCluster(t, m.GetOnNoMatch()), "outbound-udp|53||dns.example.com")
+	matchers := m.GetMatcherList().GetMatchers()
+	assert.Equal(t, len(matchers), 1)
+	assert.Equal(t, udpRouteCluster(t, matchers[0].GetOnMatch()), "outbound-udp|53||dns-alt.example.com")
+	hashing := &consistenthashing.ConsistentHashing{}
+	if err := matchers[0].GetPredicate().GetSinglePredicate().GetCustomMatch().GetTypedConfig().UnmarshalTo(hashing); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, hashing.GetThreshold(), uint32(25))
+	assert.Equal(t, hashing.GetModulo(), uint32(100))
+}
+
+func TestGatewayUDPClusters(t *testing.T) {
+	cg := NewConfigGenTest(t, TestOptions{ConfigString: udpGatewayConfig})
+	proxy := cg.SetupProxy(&proxyGateway)
+	clusters := cg.Clusters(proxy)
+	xdstest.ValidateClusters(t, clusters)
+
+	c := xdstest.ExtractCluster("outbound-udp|53||dns.example.com", clusters)
+	if c == nil {
+		t.Fatalf("expected UDP cluster, got %v", xdstest.MapKeys(xdstest.ExtractClusters(clusters)))
+	}
+	assert.Equal(t, c.GetType(), cluster.Cluster_EDS)
+	assert.Equal(t, c.GetEdsClusterConfig().GetServiceName(), "outbound-udp|53||dns.example.com")
+
+	c = xdstest.ExtractCluster("outbound-udp|53||dns-alt.example.com", clusters)
+	if c == nil {
+		t.Fatal("expected UDP cluster for the DNS service")
+	}
+	assert.Equal(t, c.GetType(), cluster.Cluster_STRICT_DNS)
+	assert.Equal(t, c.GetLoadAssignment().GetEndpoints()[0].GetLbEndpoints()[0].GetEndpoint().GetAddress().GetSocketAddress().GetAddress(),
+		"alt.example.com")
+}
+
+func udpRouteCluster(t *testing.T, onMatch *matcher.Matcher_OnMatch) string {
+	t.Helper()
+	r := &udpproxy.Route{}
+	if err := onMatch.GetAction().GetTypedConfig().UnmarshalTo(r); err != nil {
+		t.Fatal(err)
+	}
+	return r.GetCluster()
+}
@@ -29,6 +29,7 @@ import (
 	"istio.io/istio/pilot/pkg/networking/util"
 	"istio.io/istio/pilot/pkg/util/protoconv"
 	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/slices"
 	"istio.io/istio/pkg/util/sets"
 )
 
@@ -92,6 +93,9 @@ type clusterBuilder struct {
 	svc    *model.Service
 	port   *model.Port
 	filter sets.String
+
+	// rejected holds the clusters with traffic policy settings that cannot be ignored safely, which are not sent.
+	rejected sets.String
 }
 
 func newClusterBuilder(node *model.Proxy, push *model.PushContext, defaultClusterName string, filter sets.String) (*clusterBuilder, error) {
@@ -123,6 +127,8 @@ func newClusterBuilder(node *model.Proxy, push *model.PushContext, defaultCluste
 
 		svc:  svc,
 		port: port,
+
+		rejected: sets.New[string](),
 	}, nil
 }
 
@@ -146,7 +152,10 @@ func (b *clusterBuilder) build() []*cluster.Cluster {
 	if defaultCluster != nil {
 		out = append(out, defaultCluster)
 	}
-	return append(out, subsetClusters...)
+	out = append(out, subsetClusters...)
+	return slices.FilterInPlace(out, func(c *cluster.Cluster) bool {
+		return !b.rejected.Contains(c.Name)
+	})
 }
 
 // edsCluster creates a simple cluster to read endpoints from ads/eds.
@@ -200,15 +209,41 @@ func (b *clusterBuilder) applyDestinationRule(defaultCluster *cluster.Cluster) (
 }
 
 // applyTrafficPolicy mutates the given cluster (if not-nil) so that the given merged traffic policy applies.
+// Settings that gRPC cannot honor are reported through unsupportedPolicy rather than silently dropped. If one of them
+// cannot be ignored safely, the cluster is rejected instead.
 func (b *clusterBuilder) applyTrafficPolicy(c *cluster.Cluster, trafficPolicy *networking.TrafficPolicy) {
 	// cluster can be nil if it wasn't requested
 	if c == nil {
 		return
 	}
+	for _, u := range UnsupportedTrafficPolicy(trafficPolicy) {
+		b.unsupportedPolicy(c, u.Setting, u.Reason)
+		if u.Rejected {
+			b.rejected.Insert(c.Name)
+		}
+	}
+	if b.rejected.Contains(c.Name) {
+		return
+	}
 	b.applyTLS(c, trafficPolicy)
 	b.applyLoadBalancing(c, trafficPolicy)
 	b.applyCircuitBreakers(c, trafficPolicy)
-	// TODO status or log when unsupported features are included
+	b.applyOutlierDetection(c, trafficPolicy)
+}
+
+// unsupportedPolicy records a traffic policy setting that could not be applied to the cluster, so it shows
+// up in the push status of the proxy instead of being silently ignored.
+func (b *clusterBuilder) unsupportedPolicy(c *cluster.Cluster, setting, reason string) {
+	proxyID := ""
+	if b.node != nil {
+		proxyID = b.node.ID
+	}
+	log.Debugf("cannot apply %s to cluster %s for %s: %s", setting, c.Name, proxyID, reason)
+	if b.push == nil {
+		return
+	}
+	b.push.AddMetric(model.ProxylessGRPCUnsupportedPolicy, proxyID+"/"+c.Name+"/"+setting, proxyID,
+		fmt.Sprintf("cluster %s: %s is not supported: %s", c.Name, setting, reason))
 }
 
 func (b *clusterBuilder) applyLoadBalancing(c *cluster.Cluster, policy *networking.TrafficPolicy) {
@@ -221,21 +256,35 @@ func (b *clusterBuilder) applyLoadBalancing(c *cluster.Cluster, policy *networki
 		return
 	}
 
-	if lb.GetConsistentHash() != nil {
-		corexds.ApplyRingHashLoadBalancer(c, lb)
+	if consistentHash := lb.GetConsistentHash(); consistentHash != nil {
+		b.applyConsistentHash(c, lb)
 		return
 	}
 
 	switch lb.GetSimple() {
 	case networking.LoadBalancerSettings_ROUND_ROBIN, networking.LoadBalancerSettings_UNSPECIFIED:
 		// Default gRPC behavior (round_robin) so no explicit `lb_policy` needed.
-	case networking.LoadBalancerSettings_LEAST_REQUEST:
+	case networking.LoadBalancerSettings_LEAST_CONN, networking.LoadBalancerSettings_LEAST_REQUEST:
 		c.LbPolicy = cluster.Cluster_LEAST_REQUEST
-	default:
-		log.Warnf("cannot apply LbPolicy %s to %s", lb.GetSimple(), b.node.ID)
 	}
 }
 
+// applyConsistentHash configures ring hash load balancing, see gRFC [A42].
+// gRPC only implements ring hash, and can only hash on request headers or on the client channel, which is used for
+// useSourceIp. The hash policies are set on the routes, see convertHashPolicies.
+//
+// [A42]: https://github.com/grpc/proposal/blob/master/A42-xds-ring-hash-lb-policy.md
+func (b *clusterBuilder) applyConsistentHash(c *cluster.Cluster, lb *networking.LoadBalancerSettings) {
+	if consistentHash := lb.GetConsistentHash(); consistentHash.GetMaglev() != nil {
+		lb = &networking.LoadBalancerSettings{
+			LbPolicy: &networking.LoadBalancerSettings_ConsistentHash{
+				ConsistentHash: &networking.LoadBalancerSettings_ConsistentHashLB{HashKey: consistentHash.GetHashKey()},
+			},
+		}
+	}
+	corexds.ApplyRingHashLoadBalancer(c, lb)
+}
+
 func (b *clusterBuilder) applyCircuitBreakers(c *cluster.Cluster, policy *networking.TrafficPolicy) {
 	if policy == nil {
 		return
@@ -246,7 +295,7 @@ func (b *clusterBuilder) applyCircuitBreakers(c *cluster.Cluster, policy *networ
 		return
 	}
 
-	// Per gRFC [A32], only max_requests is supported; other fields are not applicable to gRPC.
+	// Per gRFC [A32], only max_requests is supported.
 	// [A32]: https://github.com/grpc/proposal/blob/master/A32-xds-circuit-breaking.md
 	if cp.Http == nil || cp.Http.Http2MaxRequests <= 0 {
 		return
@@ -261,26 +310,85 @@ func (b *clusterBuilder) applyCircuitBreakers(c *cluster.Cluster, policy *networ
 	}
 }
 
+// applyOutlierDetection configures outlier detection as supported by gRFC [A50].
+// gRPC only implements success rate and failure percentage based ejection.
+//
+// [A50]: https://github.com/grpc/proposal/blob/master/A50-xds-outlier-detection.md
+func (b *clusterBuilder) applyOutlierDetection(c *cluster.Cluster, policy *networking.TrafficPolicy) {
+	outlier := policy.GetOutlierDetection()
+	if outlier == nil {
+		return
+	}
+
+	out := &cluster.OutlierDetection{
+		// SuccessRate based outlier detection should be disabled.
+		EnforcingSuccessRate: &wrapperspb.UInt32Value{Value: 0},
+		Interval:             outlier.Interval,
+		BaseEjectionTime:     outlier.BaseEjectionTime,
+	}
+	if outlier.MaxEjectionPercent > 0 {
+		out.MaxEjectionPercent = &wrapperspb.UInt32Value{Value: uint32(outlier.MaxEjectionPercent)}
+	}
+
+	// gRPC has no consecutive errors based ejection. The closest equivalent is to eject hosts where all of
+	// (at least) the configured number of requests failed during the last interval.
+	if e := outlier.GetConsecutive_5XxErrors().GetValue(); e > 0 {
+		out.FailurePercentageThreshold = &wrapperspb.UInt32Value{Value: 100}
+		out.EnforcingFailurePercentage = &wrapperspb.UInt32Value{Value: 100}
+		out.FailurePercentageRequestVolume = &wrapperspb.UInt32Value{Value: e}
+		out.FailurePercentageMinimumHosts = &wrapperspb.UInt32Value{Value: 1}
+	}
+
+	c.OutlierDetection = out
+}
+
 func (b *clusterBuilder) applyTLS(c *cluster.Cluster, policy *networking.TrafficPolicy) {
 	// TODO for now, we leave mTLS *off* by default:
 	// 1. We don't know if the client uses xds.NewClientCredentials; these settings will be ignored if not
 	// 2. We cannot reach servers in PERMISSIVE mode; gRPC doesn't allow us to override the alpn to one of Istio's
 	// 3. Once we support gRPC servers, we have no good way to detect if a server is implemented with xds.NewGrpcServer and will actually support our config
 	// For these reasons, support only explicit tls configuration.
-	switch policy.GetTls().GetMode() {
+	settings := policy.GetTls()
+	var tlsCtx *tls.UpstreamTlsContext
+	switch settings.GetMode() {
 	case networking.ClientTLSSettings_DISABLE:
 		// nothing to do
-	case networking.ClientTLSSettings_SIMPLE:
-		// TODO support this
-	case networking.ClientTLSSettings_MUTUAL:
-		// TODO support this
+	case networking.ClientTLSSettings_SIMPLE, networking.ClientTLSSettings_MUTUAL:
+		tlsCtx = buildClientTLSContext(settings)
 	case networking.ClientTLSSettings_ISTIO_MUTUAL:
-		tlsCtx := buildUpstreamTLSContext(b.push.ServiceAccounts(b.hostname, b.svc.Attributes.Namespace))
-		c.TransportSocket = &core.TransportSocket{
-			Name:       transportSocketName,
-			ConfigType: &core.TransportSocket_TypedConfig{TypedConfig: protoconv.MessageToAny(tlsCtx)},
+		tlsCtx = buildUpstreamTLSContext(b.push.ServiceAccounts(b.hostname, b.svc.Attributes.Namespace))
+	}
+	if tlsCtx == nil {
+		return
+	}
+	c.TransportSocket = &core.TransportSocket{
+		Name:       transportSocketName,
+		ConfigType: &core.TransportSocket_TypedConfig{TypedConfig: protoconv.MessageToAny(tlsCtx)},
+	}
+}
+
+// buildClientTLSContext builds the TLS context for SIMPLE and MUTUAL TLS origination.
+// gRPC cannot fetch secrets over SDS. Instead, credentialName refers to a certificate provider instance in the gRPC
+// bootstrap (see gRFC [A29]), which provides the client certificate as "default" and the CA certificate as "ROOTCA",
+// like the "default" instance used for ISTIO_MUTUAL. The bootstrap generated by the agent only has the "default"
+// instance, so clients using credentialName must add the instance to their bootstrap, for example with the
+// "file_watcher" plugin; otherwise gRPC rejects the cluster.
+// Without credentialName, SIMPLE TLS trusts the system root certificates. Settings that cannot be honored, such as
+// MUTUAL TLS without credentialName, are rejected by UnsupportedTrafficPolicy before this is called.
+//
+// [A29]: https://github.com/grpc/proposal/blob/master/A29-xds-tls-security.md
+func buildClientTLSContext(settings *networking.ClientTLSSettings) *tls.UpstreamTlsContext {
+	var identity, rootCA *certProvider
+	if name := settings.GetCredentialName(); name != "" {
+		rootCA = &certProvider{instance: name, certificate: defaultRootCAProvider.certificate}
+		if settings.GetMode() == networking.ClientTLSSettings_MUTUAL {
+			identity = &certProvider{instance: name, certificate: defaultIdentityProvider.certificate}
 		}
 	}
+	return &tls.UpstreamTlsContext{
+		CommonTlsContext: buildCertProviderTLSContext(identity, rootCA, settings.GetSubjectAltNames()),
+		Sni:              settings.GetSni(),
+	}
 }
 
 // TransportSocket proto message has a `name` field which is expected to be set to exactly this value by the
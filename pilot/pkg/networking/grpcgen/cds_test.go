@@ -15,12 +15,19 @@
 package grpcgen
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	networking "istio.io/api/networking/v1alpha3"
 	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/test/util/assert"
+	"istio.io/istio/pkg/util/sets"
 )
 
 func TestApplyCircuitBreakers(t *testing.T) {
@@ -235,3 +242,175 @@ func TestApplyTrafficPolicy(t *testing.T) {
 		}
 	})
 }
+
+func TestApplyTLS(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		tls             *networking.ClientTLSSettings
+		wantTLS         bool
+		wantRejected    bool
+		wantIdentity    *tls.CertificateProviderPluginInstance
+		wantCA          *tls.CertificateProviderPluginInstance
+		wantUnsupported []string
+	}{
+		{
+			name: "disable",
+			tls:  &networking.ClientTLSSettings{Mode: networking.ClientTLSSettings_DISABLE},
+		},
+		{
+			name:    "simple without credentialName",
+			tls:     &networking.ClientTLSSettings{Mode: networking.ClientTLSSettings_SIMPLE},
+			wantTLS: true,
+		},
+		{
+			name:    "simple with credentialName",
+			tls:     &networking.ClientTLSSettings{Mode: networking.ClientTLSSettings_SIMPLE, CredentialName: "my-cert"},
+			wantTLS: true,
+			wantCA:  &tls.CertificateProviderPluginInstance{InstanceName: "my-cert", CertificateName: "ROOTCA"},
+		},
+		{
+			name:         "mutual with credentialName",
+			tls:          &networking.ClientTLSSettings{Mode: networking.ClientTLSSettings_MUTUAL, CredentialName: "my-cert"},
+			wantTLS:      true,
+			wantIdentity: &tls.CertificateProviderPluginInstance{InstanceName: "my-cert", CertificateName: "default"},
+			wantCA:       &tls.CertificateProviderPluginInstance{InstanceName: "my-cert", CertificateName: "ROOTCA"},
+		},
+		{
+			name:            "insecureSkipVerify is ignored",
+			tls:             &networking.ClientTLSSettings{Mode: networking.ClientTLSSettings_SIMPLE, InsecureSkipVerify: wrapperspb.Bool(true)},
+			wantTLS:         true,
+			wantUnsupported: []string{"tls.insecureSkipVerify"},
+		},
+		{
+			name:            "simple with certificate files",
+			tls:             &networking.ClientTLSSettings{Mode: networking.ClientTLSSettings_SIMPLE, CaCertificates: "/etc/ca.pem"},
+			wantRejected:    true,
+			wantUnsupported: []string{"tls.caCertificates"},
+		},
+		{
+			name:            "mutual without credentialName",
+			tls:             &networking.ClientTLSSettings{Mode: networking.ClientTLSSettings_MUTUAL, ClientCertificate: "/etc/cert.pem"},
+			wantRejected:    true,
+			wantUnsupported: []string{"tls.credentialName"},
+		},
+		{
+			name:            "certificate revocation list",
+			tls:             &networking.ClientTLSSettings{Mode: networking.ClientTLSSettings_SIMPLE, CredentialName: "my-cert", CaCrl: "/etc/crl.pem"},
+			wantRejected:    true,
+			wantUnsupported: []string{"tls.caCrl"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			push := model.NewPushContext()
+			c := &cluster.Cluster{Name: "outbound|443||foo.example.com"}
+			b := &clusterBuilder{push: push, node: &model.Proxy{ID: "test-node"}, rejected: sets.New[string]()}
+			b.applyTrafficPolicy(c, &networking.TrafficPolicy{Tls: test.tls})
+
+			got := sets.New[string]()
+			for key := range push.GetMetric(model.ProxylessGRPCUnsupportedPolicy.Name()) {
+				got.Insert(strings.TrimPrefix(key, "test-node/"+c.Name+"/"))
+			}
+			assert.Equal(t, sets.SortedList(got), test.wantUnsupported)
+			// Clusters with TLS that cannot be honored are not sent rather than sent without TLS
+			assert.Equal(t, b.rejected.Contains(c.Name), test.wantRejected)
+
+			if !test.wantTLS {
+				assert.Equal(t, c.TransportSocket, nil)
+				return
+			}
+			assert.Equal(t, c.TransportSocket.GetName(), transportSocketName)
+			tlsCtx := &tls.UpstreamTlsContext{}
+			assert.NoError(t, c.TransportSocket.GetTypedConfig().UnmarshalTo(tlsCtx))
+			common := tlsCtx.GetCommonTlsContext()
+			assert.Equal(t, common.GetTlsCertificateProviderInstance(), test.wantIdentity)
+			validation := common.GetCombinedValidationContext().GetDefaultValidationContext()
+			assert.Equal(t, validation.GetCaCertificateProviderInstance(), test.wantCA)
+			assert.Equal(t, validation.GetSystemRootCerts() != nil, test.wantCA == nil)
+		})
+	}
+}
+
+func TestApplyTLSSniAndSANs(t *testing.T) {
+	c := &cluster.Cluster{}
+	b := &clusterBuilder{}
+	b.applyTLS(c, &networking.TrafficPolicy{Tls: &networking.ClientTLSSettings{
+		Mode:            networking.ClientTLSSettings_SIMPLE,
+		Sni:             "foo.example.com",
+		SubjectAltNames: []string{"foo.example.com"},
+	}})
+
+	tlsCtx := &tls.UpstreamTlsContext{}
+	assert.NoError(t, c.TransportSocket.GetTypedConfig().UnmarshalTo(tlsCtx))
+	assert.Equal(t, tlsCtx.Sni, "foo.example.com")
+	sans := tlsCtx.GetCommonTlsContext().GetCombinedValidationContext().GetDefaultValidationContext().GetMatchSubjectAltNames()
+	assert.Equal(t, len(sans), 1)
+	assert.Equal(t, sans[0].GetExact(), "foo.example.com")
+}
+
+func TestApplyOutlierDetection(t *testing.T) {
+	t.Parallel()
+
+	c := &cluster.Cluster{}
+	b := &clusterBuilder{}
+	b.applyOutlierDetection(c, &networking.TrafficPolicy{
+		OutlierDetection: &networking.OutlierDetection{
+			Consecutive_5XxErrors: wrapperspb.UInt32(5),
+			Interval:              durationpb.New(10 * time.Second),
+			BaseEjectionTime:      durationpb.New(time.Minute),
+			MaxEjectionPercent:    50,
+		},
+	})
+
+	assert.Equal(t, c.OutlierDetection, &cluster.OutlierDetection{
+		EnforcingSuccessRate:           wrapperspb.UInt32(0),
+		Interval:                       durationpb.New(10 * time.Second),
+		BaseEjectionTime:               durationpb.New(time.Minute),
+		MaxEjectionPercent:             wrapperspb.UInt32(50),
+		FailurePercentageThreshold:     wrapperspb.UInt32(100),
+		EnforcingFailurePercentage:     wrapperspb.UInt32(100),
+		FailurePercentageRequestVolume: wrapperspb.UInt32(5),
+		FailurePercentageMinimumHosts:  wrapperspb.UInt32(1),
+	})
+}
+
+func TestUnsupportedTrafficPolicyReported(t *testing.T) {
+	push := model.NewPushContext()
+	b := &clusterBuilder{push: push, node: &model.Proxy{ID: "test-node"}, rejected: sets.New[string]()}
+	c := &cluster.Cluster{Name: "outbound|8080||foo.default.svc.cluster.local"}
+	b.applyTrafficPolicy(c, &networking.TrafficPolicy{
+		LoadBalancer: &networking.LoadBalancerSettings{
+			LbPolicy: &networking.LoadBalancerSettings_ConsistentHash{
+				ConsistentHash: &networking.LoadBalancerSettings_ConsistentHashLB{
+					HashKey:       &networking.LoadBalancerSettings_ConsistentHashLB_HttpHeaderName{HttpHeaderName: "x-user"},
+					HashAlgorithm: &networking.LoadBalancerSettings_ConsistentHashLB_Maglev{Maglev: &networking.LoadBalancerSettings_ConsistentHashLB_MagLev{}},
+				},
+			},
+		},
+		OutlierDetection: &networking.OutlierDetection{
+			ConsecutiveGatewayErrors: wrapperspb.UInt32(3),
+		},
+		ConnectionPool: &networking.ConnectionPoolSettings{
+			Http: &networking.ConnectionPoolSettings_HTTPSettings{Http2MaxRequests: 10},
+		},
+	})
+
+	// Maglev falls back to ring hash
+	assert.Equal(t, c.LbPolicy, cluster.Cluster_RING_HASH)
+	assert.Equal(t, c.OutlierDetection != nil, true)
+
+	got := sets.New[string]()
+	for key, status := range push.GetMetric(model.ProxylessGRPCUnsupportedPolicy.Name()) {
+		assert.Equal(t, status.Proxy, "test-node")
+		got.Insert(key)
+	}
+	assert.Equal(t, sets.SortedList(got), []string{
+		"test-node/" + c.Name + "/loadBalancer.consistentHash.maglev",
+		"test-node/" + c.Name + "/outlierDetection.consecutiveGatewayErrors",
+	})
+}
@@ -66,6 +66,17 @@ func (g *GrpcConfigGenerator) Generate(proxy *model.Proxy, w *model.WatchedResou
 	return nil, model.DefaultXdsLogDetails, nil
 }
 
+// certProvider references a certificate of a certificate provider instance, configured in the gRPC bootstrap.
+type certProvider struct {
+	instance    string
+	certificate string
+}
+
+var (
+	defaultIdentityProvider = &certProvider{instance: "default", certificate: "default"}
+	defaultRootCAProvider   = &certProvider{instance: "default", certificate: "ROOTCA"}
+)
+
 // buildCommonTLSContext creates a TLS context that assumes 'default' name, and credentials/tls/certprovider/pemfile
 // (see grpc/xds/internal/client/xds.go securityConfigFromCluster).
 //
@@ -75,63 +86,63 @@ func (g *GrpcConfigGenerator) Generate(proxy *model.Proxy, w *model.WatchedResou
 //
 // The deprecated fields will be removed in a future release. See https://github.com/istio/istio/issues/TBD
 func buildCommonTLSContext(sans []string) *tls.CommonTlsContext {
+	return buildCertProviderTLSContext(defaultIdentityProvider, defaultRootCAProvider, sans)
+}
+
+// buildCertProviderTLSContext creates a TLS context using the given certificate providers, with the same
+// current and deprecated fields as buildCommonTLSContext.
+// If identity is nil, no certificate is presented. If rootCA is nil, the system root certificates are trusted.
+func buildCertProviderTLSContext(identity, rootCA *certProvider, sans []string) *tls.CommonTlsContext {
 	var sanMatch []*matcher.StringMatcher
 	if len(sans) > 0 {
 		sanMatch = util.StringToExactMatch(sans)
 	}
 
-	// Create certificate provider instances for deprecated fields (field 11 and field 4)
-	// These use the CommonTlsContext_CertificateProviderInstance type
-	deprecatedCertProviderInstance := &tls.CommonTlsContext_CertificateProviderInstance{
-		InstanceName:    "default",
-		CertificateName: "default",
-	}
-
-	deprecatedCaProviderInstance := &tls.CommonTlsContext_CertificateProviderInstance{
-		InstanceName:    "default",
-		CertificateName: "ROOTCA",
-	}
-
-	// Create certificate provider instances for current fields (field 14 and ca_certificate_provider_instance)
-	// These use the CertificateProviderPluginInstance type
-	currentCertProviderInstance := &tls.CertificateProviderPluginInstance{
-		InstanceName:    "default",
-		CertificateName: "default",
-	}
-
-	currentCaProviderInstance := &tls.CertificateProviderPluginInstance{
-		InstanceName:    "default",
-		CertificateName: "ROOTCA",
-	}
-
-	return &tls.CommonTlsContext{
+	ctx := &tls.CommonTlsContext{}
+	if identity != nil {
 		// Current field (field 14) - introduced in Envoy 1.28
 		// Supported by grpc-go >= 1.66, grpc-cpp >= 1.66, modern grpc-java
 		// Uses CertificateProviderPluginInstance type
-		TlsCertificateProviderInstance: currentCertProviderInstance,
+		ctx.TlsCertificateProviderInstance = &tls.CertificateProviderPluginInstance{
+			InstanceName:    identity.instance,
+			CertificateName: identity.certificate,
+		}
 
 		// DEPRECATED field (field 11) - kept for backward compatibility with older gRPC clients
 		// TODO: Remove this field after 2 releases (when all users have upgraded gRPC clients)
 		// See https://github.com/grpc/grpc-java/commit/65d0bb8a4d9ee111f1eeb02c43321bbb99143151
 		// Uses CommonTlsContext_CertificateProviderInstance type
-		TlsCertificateCertificateProviderInstance: deprecatedCertProviderInstance,
-
-		ValidationContextType: &tls.CommonTlsContext_CombinedValidationContext{
-			CombinedValidationContext: &tls.CommonTlsContext_CombinedCertificateValidationContext{
-				// DEPRECATED field (field 4) - kept for backward compatibility with older gRPC clients
-				// TODO: Remove this field after 2 releases (when all users have upgraded gRPC clients)
-				// Uses CommonTlsContext_CertificateProviderInstance type
-				ValidationContextCertificateProviderInstance: deprecatedCaProviderInstance,
-
-				DefaultValidationContext: &tls.CertificateValidationContext{
-					MatchSubjectAltNames: sanMatch,
-
-					// Current field - CA certificate provider in default_validation_context
-					// This is the recommended way to specify CA certificates per the xDS specification
-					// Uses CertificateProviderPluginInstance type
-					CaCertificateProviderInstance: currentCaProviderInstance,
-				},
-			},
+		ctx.TlsCertificateCertificateProviderInstance = &tls.CommonTlsContext_CertificateProviderInstance{
+			InstanceName:    identity.instance,
+			CertificateName: identity.certificate,
+		}
+	}
+
+	validation := &tls.CommonTlsContext_CombinedCertificateValidationContext{
+		DefaultValidationContext: &tls.CertificateValidationContext{
+			MatchSubjectAltNames: sanMatch,
 		},
 	}
+	if rootCA != nil {
+		// DEPRECATED field (field 4) - kept for backward compatibility with older gRPC clients
+		// TODO: Remove this field after 2 releases (when all users have upgraded gRPC clients)
+		// Uses CommonTlsContext_CertificateProviderInstance type
+		validation.ValidationContextCertificateProviderInstance = &tls.CommonTlsContext_CertificateProviderInstance{
+			InstanceName:    rootCA.instance,
+			CertificateName: rootCA.certificate,
+		}
+
+		// Current field - CA certificate provider in default_validation_context
+		// This is the recommended way to specify CA certificates per the xDS specification
+		// Uses CertificateProviderPluginInstance type
+		validation.DefaultValidationContext.CaCertificateProviderInstance = &tls.CertificateProviderPluginInstance{
+			InstanceName:    rootCA.instance,
+			CertificateName: rootCA.certificate,
+		}
+	} else {
+		// See gRFC A82; older gRPC clients will reject the cluster.
+		validation.DefaultValidationContext.SystemRootCerts = &tls.CertificateValidationContext_SystemRootCerts{}
+	}
+	ctx.ValidationContextType = &tls.CommonTlsContext_CombinedValidationContext{CombinedValidationContext: validation}
+	return ctx
 }
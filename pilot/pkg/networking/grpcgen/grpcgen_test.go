@@ -47,6 +47,7 @@ import (
 	security "istio.io/api/security/v1beta1"
 	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/networking/grpcgen"
 	"istio.io/istio/pilot/pkg/networking/util"
 	"istio.io/istio/pilot/pkg/serviceregistry/memory"
 	v3 "istio.io/istio/pilot/pkg/xds/v3"
@@ -1095,3 +1096,68 @@ func TestGRPCRDSSameShortNameDifferentNamespaces(t *testing.T) {
 		t.Errorf("virtual host domains %v unexpectedly contain %s", vh.Domains, svcNS2)
 	}
 }
+
+// TestGRPCCDSRejectsUnsupportedTLS verifies that clusters with TLS settings gRPC cannot honor are not sent, rather
+// than sent without TLS.
+func TestGRPCCDSRejectsUnsupportedTLS(t *testing.T) {
+	svc := "svc.test.svc.cluster.local"
+	ds := xds.NewFakeDiscoveryServer(t, xds.FakeOptions{
+		Configs: []config.Config{{
+			Meta: config.Meta{
+				GroupVersionKind: gvk.DestinationRule,
+				Name:             "svc",
+				Namespace:        "test",
+			},
+			Spec: &networking.DestinationRule{
+				Host: svc,
+				TrafficPolicy: &networking.TrafficPolicy{Tls: &networking.ClientTLSSettings{
+					Mode:           networking.ClientTLSSettings_SIMPLE,
+					CredentialName: "my-cert",
+				}},
+				Subsets: []*networking.Subset{
+					{
+						Name:   "v1",
+						Labels: map[string]string{"version": "v1"},
+					},
+					{
+						Name:   "v2",
+						Labels: map[string]string{"version": "v2"},
+						TrafficPolicy: &networking.TrafficPolicy{Tls: &networking.ClientTLSSettings{
+							Mode: networking.ClientTLSSettings_MUTUAL,
+						}},
+					},
+				},
+			},
+		}},
+		Services: []*model.Service{{
+			Attributes: model.ServiceAttributes{
+				Name:      "svc",
+				Namespace: "test",
+			},
+			Hostname:       host.Name(svc),
+			DefaultAddress: "10.0.0.1",
+			Ports: model.PortList{{
+				Name:     "grpc",
+				Port:     8080,
+				Protocol: protocol.GRPC,
+			}},
+		}},
+	})
+	proxy := ds.SetupProxy(&model.Proxy{
+		Metadata: &model.NodeMetadata{
+			Generator: "grpc",
+			Namespace: "test",
+		},
+	})
+
+	names := []string{
+		"outbound|8080||" + svc,
+		"outbound|8080|v1|" + svc,
+		"outbound|8080|v2|" + svc,
+	}
+	resources := (&grpcgen.GrpcConfigGenerator{}).BuildClusters(proxy, ds.PushContext(), names)
+	got := slices.Sort(slices.Map(resources, func(r *discovery.Resource) string { return r.Name }))
+	if want := slices.Sort([]string{names[0], names[1]}); !slices.Equal(got, want) {
+		t.Fatalf("expected clusters %v, got %v", want, got)
+	}
+}
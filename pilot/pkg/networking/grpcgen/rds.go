@@ -60,6 +60,7 @@ func buildHTTPRoute(node *model.Proxy, push *model.PushContext, routeName string
 	// the one matching the requested. Without this, the RouteConfiguration contains every service on
 	// the port from around the mesh, causing unnecessary churn pushes when unrelated services change.
 	virtualHosts = filterVirtualHostsForHostname(virtualHosts, string(hostname), port)
+	convertHashPolicies(virtualHosts)
 
 	return &route.RouteConfiguration{
 		Name:         routeName,
@@ -67,6 +68,40 @@ func buildHTTPRoute(node *model.Proxy, push *model.PushContext, routeName string
 	}
 }
 
+// grpcChannelIDHashKey is the filter state key gRPC hashes the ID of the client channel with.
+const grpcChannelIDHashKey = "io.grpc.channel_id"
+
+// convertHashPolicies replaces the hash policies of the routes with the ones gRPC supports, see gRFC [A42].
+// gRPC only hashes on request headers and on the client channel ID. Hashing on the channel ID keeps all requests of
+// a client on the same endpoint, so it is used for useSourceIp. Other hash policies would be ignored by gRPC, which
+// then hashes on a random value; they are dropped, and reported when building the cluster.
+//
+// [A42]: https://github.com/grpc/proposal/blob/master/A42-xds-ring-hash-lb-policy.md
+func convertHashPolicies(virtualHosts []*route.VirtualHost) {
+	for _, vh := range virtualHosts {
+		for _, r := range vh.Routes {
+			action := r.GetRoute()
+			if len(action.GetHashPolicy()) == 0 {
+				continue
+			}
+			var policies []*route.RouteAction_HashPolicy
+			for _, hp := range action.HashPolicy {
+				switch {
+				case hp.GetHeader() != nil:
+					policies = append(policies, hp)
+				case hp.GetConnectionProperties().GetSourceIp():
+					policies = append(policies, &route.RouteAction_HashPolicy{
+						PolicySpecifier: &route.RouteAction_HashPolicy_FilterState_{
+							FilterState: &route.RouteAction_HashPolicy_FilterState{Key: grpcChannelIDHashKey},
+						},
+					})
+				}
+			}
+			action.HashPolicy = policies
+		}
+	}
+}
+
 // filterVirtualHostsForHostname returns only the virtual hosts whose domains contain the given
 // hostname or hostname:port. Wildcard domains are matched using host.Name semantics. Uses the same
 // formatting as domain generation (util.IPv6Compliant, util.DomainName) to handle IPv6 addresses
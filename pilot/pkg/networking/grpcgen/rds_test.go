@@ -15,10 +15,19 @@
 package grpcgen
 
 import (
+	"fmt"
 	"testing"
 
 	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	"github.com/google/go-cmp/cmp"
+
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/networking/core"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/protocol"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/test/util/assert"
 )
 
 func TestFilterVirtualHostsForHostname(t *testing.T) {
@@ -122,3 +131,89 @@ func TestFilterVirtualHostsForHostname(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildHTTPRouteHashPolicy(t *testing.T) {
+	const svc = "svc-a.test.svc.cluster.local"
+	destinationRule := func(hashKey *networking.LoadBalancerSettings_ConsistentHashLB) config.Config {
+		return config.Config{
+			Meta: config.Meta{GroupVersionKind: gvk.DestinationRule, Name: "svc-a", Namespace: "test"},
+			Spec: &networking.DestinationRule{
+				Host: svc,
+				TrafficPolicy: &networking.TrafficPolicy{LoadBalancer: &networking.LoadBalancerSettings{
+					LbPolicy: &networking.LoadBalancerSettings_ConsistentHash{ConsistentHash: hashKey},
+				}},
+			},
+		}
+	}
+	virtualService := config.Config{
+		Meta: config.Meta{GroupVersionKind: gvk.VirtualService, Name: "svc-a", Namespace: "test"},
+		Spec: &networking.VirtualService{
+			Hosts: []string{svc},
+			Http: []*networking.HTTPRoute{{
+				Route: []*networking.HTTPRouteDestination{{Destination: &networking.Destination{Host: svc}}},
+			}},
+		},
+	}
+	headerHash := &route.RouteAction_HashPolicy{PolicySpecifier: &route.RouteAction_HashPolicy_Header_{
+		Header: &route.RouteAction_HashPolicy_Header{HeaderName: "x-user"},
+	}}
+	channelIDHash := &route.RouteAction_HashPolicy{PolicySpecifier: &route.RouteAction_HashPolicy_FilterState_{
+		FilterState: &route.RouteAction_HashPolicy_FilterState{Key: "io.grpc.channel_id"},
+	}}
+
+	cases := []struct {
+		name    string
+		hashKey *networking.LoadBalancerSettings_ConsistentHashLB
+		want    []*route.RouteAction_HashPolicy
+	}{
+		{
+			name: "header",
+			hashKey: &networking.LoadBalancerSettings_ConsistentHashLB{
+				HashKey: &networking.LoadBalancerSettings_ConsistentHashLB_HttpHeaderName{HttpHeaderName: "x-user"},
+			},
+			want: []*route.RouteAction_HashPolicy{headerHash},
+		},
+		{
+			name: "source IP",
+			hashKey: &networking.LoadBalancerSettings_ConsistentHashLB{
+				HashKey: &networking.LoadBalancerSettings_ConsistentHashLB_UseSourceIp{UseSourceIp: true},
+			},
+			want: []*route.RouteAction_HashPolicy{channelIDHash},
+		},
+		{
+			name: "cookie",
+			hashKey: &networking.LoadBalancerSettings_ConsistentHashLB{
+				HashKey: &networking.LoadBalancerSettings_ConsistentHashLB_HttpCookie{
+					HttpCookie: &networking.LoadBalancerSettings_ConsistentHashLB_HTTPCookie{Name: "session"},
+				},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range cases {
+		for _, withVirtualService := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/virtual service %v", tt.name, withVirtualService), func(t *testing.T) {
+				configs := []config.Config{destinationRule(tt.hashKey)}
+				if withVirtualService {
+					configs = append(configs, virtualService)
+				}
+				cg := core.NewConfigGenTest(t, core.TestOptions{
+					Configs: configs,
+					Services: []*model.Service{{
+						Attributes:     model.ServiceAttributes{Name: "svc-a", Namespace: "test"},
+						Hostname:       svc,
+						DefaultAddress: "10.0.0.1",
+						Ports:          model.PortList{{Name: "grpc", Port: 8080, Protocol: protocol.GRPC}},
+					}},
+				})
+				proxy := cg.SetupProxy(&model.Proxy{Metadata: &model.NodeMetadata{Generator: "grpc", Namespace: "test"}})
+
+				rc := buildHTTPRoute(proxy, cg.PushContext(), "outbound|8080||"+svc)
+				assert.Equal(t, len(rc.GetVirtualHosts()), 1)
+				routes := rc.GetVirtualHosts()[0].GetRoutes()
+				assert.Equal(t, len(routes), 1)
+				assert.Equal(t, routes[0].GetRoute().GetHashPolicy(), tt.want)
+			})
+		}
+	}
+}
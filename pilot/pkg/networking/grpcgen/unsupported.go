@@ -0,0 +1,127 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcgen
+
+import (
+	"fmt"
+
+	networking "istio.io/api/networking/v1alpha3"
+)
+
+// UnsupportedSetting is a traffic policy setting that proxyless gRPC clients cannot apply.
+type UnsupportedSetting struct {
+	// Setting is the path of the setting in the traffic policy, such as "tls.caCrl".
+	Setting string
+	// Reason explains why the setting is not applied.
+	Reason string
+	// Rejected settings cannot be ignored safely, such as TLS that cannot be honored. Clusters with a rejected
+	// setting are not sent, so that gRPC fails the requests instead of sending them without the requested security.
+	Rejected bool
+}
+
+// UnsupportedTrafficPolicy returns the settings of the traffic policy that proxyless gRPC clients cannot apply.
+// It is used to report them for each cluster, and by the DestinationRule analyzer.
+func UnsupportedTrafficPolicy(policy *networking.TrafficPolicy) []UnsupportedSetting {
+	var out []UnsupportedSetting
+	unsupported := func(setting, reason string) {
+		out = append(out, UnsupportedSetting{Setting: setting, Reason: reason})
+	}
+	rejected := func(setting, reason string) {
+		out = append(out, UnsupportedSetting{Setting: setting, Reason: reason + ", the cluster is not sent", Rejected: true})
+	}
+
+	if settings := policy.GetTls(); settings.GetMode() == networking.ClientTLSSettings_SIMPLE ||
+		settings.GetMode() == networking.ClientTLSSettings_MUTUAL {
+		if settings.GetCredentialName() == "" {
+			if settings.GetMode() == networking.ClientTLSSettings_MUTUAL {
+				rejected("tls.credentialName", "MUTUAL TLS requires credentialName to refer to a certificate provider instance in the gRPC bootstrap")
+			} else if settings.GetCaCertificates() != "" {
+				rejected("tls.caCertificates", "certificate files are not supported by gRPC, "+
+					"use credentialName to refer to a certificate provider instance in the gRPC bootstrap")
+			}
+		}
+		if settings.GetCaCrl() != "" {
+			rejected("tls.caCrl", "certificate revocation lists are not supported by gRPC")
+		}
+		if settings.GetInsecureSkipVerify().GetValue() {
+			unsupported("tls.insecureSkipVerify", "gRPC always verifies the server certificate")
+		}
+	}
+
+	if lb := policy.GetLoadBalancer(); lb != nil {
+		// Locality failover, distribution and failoverPriority are applied through EDS priorities and weights,
+		// see endpoints.EndpointBuilder. As with Envoy, this only happens when outlier detection is configured.
+		if lb.GetZoneAwareLbSetting() != nil {
+			unsupported("loadBalancer.zoneAwareLbSetting", "zone aware routing is not supported by gRPC")
+		}
+		if lb.GetWarmup() != nil || lb.GetWarmupDurationSecs() != nil {
+			unsupported("loadBalancer.warmup", "slow start is not supported by gRPC")
+		}
+		if consistentHash := lb.GetConsistentHash(); consistentHash != nil {
+			if consistentHash.GetMaglev() != nil {
+				unsupported("loadBalancer.consistentHash.maglev", "maglev is not supported by gRPC, using ring hash instead")
+			}
+			if consistentHash.GetHttpHeaderName() == "" && !consistentHash.GetUseSourceIp() {
+				unsupported("loadBalancer.consistentHash", "gRPC can only hash on httpHeaderName or useSourceIp")
+			}
+		} else {
+			switch lb.GetSimple() {
+			case networking.LoadBalancerSettings_UNSPECIFIED, networking.LoadBalancerSettings_ROUND_ROBIN,
+				networking.LoadBalancerSettings_LEAST_CONN, networking.LoadBalancerSettings_LEAST_REQUEST:
+			default:
+				unsupported("loadBalancer.simple", fmt.Sprintf("load balancer %s is not supported by gRPC", lb.GetSimple()))
+			}
+		}
+	}
+
+	// Per gRFC [A32], only max_requests is supported; other fields are not applicable to gRPC.
+	// [A32]: https://github.com/grpc/proposal/blob/master/A32-xds-circuit-breaking.md
+	if cp := policy.GetConnectionPool(); cp != nil {
+		if cp.Tcp != nil {
+			unsupported("connectionPool.tcp", "only connectionPool.http.http2MaxRequests is supported by gRPC")
+		}
+		if h := cp.Http; h != nil && (h.Http1MaxPendingRequests != 0 || h.MaxRequestsPerConnection != 0 || h.MaxRetries != 0 ||
+			h.IdleTimeout != nil || h.H2UpgradePolicy != networking.ConnectionPoolSettings_HTTPSettings_DEFAULT ||
+			h.UseClientProtocol || h.MaxConcurrentStreams != 0 || h.Http2KeepAlive != nil) {
+			unsupported("connectionPool.http", "only connectionPool.http.http2MaxRequests is supported by gRPC")
+		}
+	}
+
+	if outlier := policy.GetOutlierDetection(); outlier != nil {
+		if outlier.GetConsecutiveGatewayErrors().GetValue() > 0 {
+			unsupported("outlierDetection.consecutiveGatewayErrors", "only consecutive5xxErrors is supported by gRPC")
+		}
+		if outlier.SplitExternalLocalOriginErrors {
+			unsupported("outlierDetection.splitExternalLocalOriginErrors", "local origin failures are not tracked by gRPC")
+		}
+		if len(outlier.OutlierDetectionHttpErrorCodes) > 0 {
+			unsupported("outlierDetection.outlierDetectionHttpErrorCodes", "gRPC counts all non-OK statuses as failures")
+		}
+		if outlier.MinHealthPercent > 0 {
+			unsupported("outlierDetection.minHealthPercent", "panic threshold is not supported by gRPC")
+		}
+	}
+
+	if policy.GetTunnel() != nil {
+		unsupported("tunnel", "tunneling is not supported by gRPC")
+	}
+	if policy.GetProxyProtocol() != nil {
+		unsupported("proxyProtocol", "PROXY protocol is not supported by gRPC")
+	}
+	if policy.GetRetryBudget() != nil {
+		unsupported("retryBudget", "retry budgets are not supported by gRPC")
+	}
+	return out
+}
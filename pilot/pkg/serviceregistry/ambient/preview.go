@@ -0,0 +1,109 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ambient
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/activenotifier"
+	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/config/mesh/meshwatcher"
+	"istio.io/istio/pkg/config/schema/gvr"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/krt"
+	"istio.io/istio/pkg/kube/multicluster"
+	"istio.io/istio/pkg/slices"
+)
+
+// PreviewOptions configures the offline computation of the ambient index.
+type PreviewOptions struct {
+	// Objects are the Kubernetes and Istio resources the index is built from.
+	Objects         []runtime.Object
+	MeshConfig      *meshconfig.MeshConfig
+	Revision        string
+	SystemNamespace string
+	DomainSuffix    string
+	ClusterID       cluster.ID
+	Flags           FeatureFlags
+}
+
+// PreviewResult holds the resources ztunnel would receive for a set of objects, sorted by resource name.
+type PreviewResult struct {
+	Workloads []model.WorkloadInfo
+	Services  []model.ServiceInfo
+	Policies  []model.WorkloadAuthorization
+}
+
+// Preview builds the ambient index from a set of objects, without a cluster. The objects are served by a fake client,
+// so that the resources are computed by the same collections as in Istiod.
+func Preview(opts PreviewOptions) (*PreviewResult, error) {
+	client := kube.NewFakeClient(opts.Objects...)
+	for _, crd := range []schema.GroupVersionResource{
+		gvr.AuthorizationPolicy,
+		gvr.PeerAuthentication,
+		gvr.KubernetesGateway,
+		gvr.GatewayClass,
+		gvr.WorkloadEntry,
+		gvr.ServiceEntry,
+	} {
+		if err := kube.CreateFakeCRD(client, crd, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	meshConfig := meshwatcher.NewTestWatcher(opts.MeshConfig)
+	mc := multicluster.NewController(multicluster.ControllerOptions{
+		Client:          client,
+		ClusterID:       opts.ClusterID,
+		SystemNamespace: opts.SystemNamespace,
+		MeshConfig:      meshConfig,
+		Debugger:        krt.GlobalDebugHandler,
+	})
+	if err := mc.Run(stop); err != nil {
+		return nil, fmt.Errorf("failed to run multicluster controller: %v", err)
+	}
+	idx := New(Options{
+		Revision:               opts.Revision,
+		SystemNamespace:        opts.SystemNamespace,
+		DomainSuffix:           opts.DomainSuffix,
+		ClusterID:              opts.ClusterID,
+		XDSUpdater:             model.NewEndpointIndexUpdater(model.NewEndpointIndex(model.DisabledCache{})),
+		StatusNotifier:         activenotifier.New(false),
+		Flags:                  opts.Flags,
+		MeshConfig:             meshConfig,
+		Debugger:               krt.GlobalDebugHandler,
+		MultiClusterController: mc,
+	}).(*index)
+	go idx.Run(stop)
+	client.RunAndWait(stop)
+	kube.WaitForCacheSync("ambient preview", stop, idx.HasSynced)
+
+	res := &PreviewResult{
+		Workloads: idx.workloads.List(),
+		Services:  idx.services.List(),
+		Policies:  idx.Policies(nil),
+	}
+	slices.SortBy(res.Workloads, model.WorkloadInfo.ResourceName)
+	slices.SortBy(res.Services, model.ServiceInfo.ResourceName)
+	slices.SortBy(res.Policies, model.WorkloadAuthorization.ResourceName)
+	return res, nil
+}
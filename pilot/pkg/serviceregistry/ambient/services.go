@@ -614,7 +614,7 @@ func constructServiceEntries(
 			Hostname:        h,
 			Addresses:       hostsAddresses,
 			Ports:           ports,
-			Waypoint:        w.GetAddress(),
+			Waypoint:        w.AddressFor(svc.ObjectMeta),
 			SubjectAltNames: svc.Spec.SubjectAltNames,
 			LoadBalancing:   lb,
 		})
@@ -696,7 +696,7 @@ func constructService(
 		Hostname:      string(kube.ServiceHostname(svc.Name, svc.Namespace, domainSuffix)),
 		Addresses:     addresses,
 		Ports:         ports,
-		Waypoint:      w.GetAddress(),
+		Waypoint:      w.AddressFor(svc.ObjectMeta),
 		LoadBalancing: lb,
 		IpFamilies:    ipFamily,
 		// A kubernetes service is always considered to be canonical, overrides for this host must be namespace-local
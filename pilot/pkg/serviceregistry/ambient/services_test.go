@@ -15,6 +15,7 @@
 package ambient
 
 import (
+	"fmt"
 	"net/netip"
 	"testing"
 	"time"
@@ -1515,12 +1516,60 @@ func TestServiceServices(t *testing.T) {
 	nsNS := &v1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{Name: "ns", Labels: map[string]string{v1.LabelMetadataName: "ns"}},
 	}
+	shardAddr := func(shard int) *workloadapi.GatewayAddress {
+		return &workloadapi.GatewayAddress{
+			Destination: &workloadapi.GatewayAddress_Hostname{
+				Hostname: &workloadapi.NamespacedHostname{Namespace: "ns", Hostname: fmt.Sprintf("sharded-shard-%d.example", shard)},
+			},
+			HboneMtlsPort: 15008,
+		}
+	}
+	shardedWaypoint := Waypoint{
+		Named:         krt.Named{Name: "sharded", Namespace: "ns"},
+		TrafficType:   constants.AllTraffic,
+		Address:       waypointAddr,
+		AllowedRoutes: allowNS,
+		Shards:        []*workloadapi.GatewayAddress{shardAddr(0), shardAddr(1), shardAddr(2)},
+	}
 	cases := []struct {
 		name   string
 		inputs []any
 		svc    *v1.Service
 		result *workloadapi.Service
 	}{
+		{
+			name:   "sharded waypoint",
+			inputs: []any{shardedWaypoint, nsNS},
+			svc: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "ns",
+					Labels: map[string]string{
+						label.IoIstioUseWaypoint.Name: "sharded",
+						constants.WaypointShardLabel:  "2",
+					},
+				},
+				Spec: v1.ServiceSpec{
+					ClusterIP: "1.2.3.4",
+					Ports:     []v1.ServicePort{{Port: 80, Name: "http"}},
+				},
+			},
+			result: &workloadapi.Service{
+				Name:      "name",
+				Namespace: "ns",
+				Hostname:  "name.ns.svc.domain.suffix",
+				Addresses: []*workloadapi.NetworkAddress{{
+					Network: testNW,
+					Address: netip.AddrFrom4([4]byte{1, 2, 3, 4}).AsSlice(),
+				}},
+				Waypoint: shardAddr(2),
+				Ports: []*workloadapi.Port{{
+					ServicePort: 80,
+					AppProtocol: workloadapi.AppProtocol_HTTP11,
+				}},
+				Canonical: true,
+			},
+		},
 		{
 			name:   "simple",
 			inputs: []any{},
@@ -68,6 +68,10 @@ type Waypoint struct {
 	// the ServiceAccounts directly on a Gateway resource.
 	ServiceAccounts []string
 	AllowedRoutes   WaypointSelector
+
+	// Shards are the addresses of each shard of a sharded waypoint, indexed by shard.
+	// Services are bound to a single shard, while workloads use the Address, which spans all shards.
+	Shards []*workloadapi.GatewayAddress
 }
 
 type ClusteredNamespace struct {
@@ -81,7 +85,10 @@ func (w Waypoint) Equals(other Waypoint) bool {
 		ptr.Equal(w.DefaultBinding, other.DefaultBinding) &&
 		w.AllowedRoutes.Equals(other.AllowedRoutes) &&
 		slices.Equal(w.ServiceAccounts, other.ServiceAccounts) &&
-		proto.Equal(w.Address, other.Address)
+		proto.Equal(w.Address, other.Address) &&
+		slices.EqualFunc(w.Shards, other.Shards, func(a, b *workloadapi.GatewayAddress) bool {
+			return proto.Equal(a, b)
+		})
 }
 
 // fetchWaypointForInstance attempts to find a Waypoint a given object is an instance of.
@@ -333,8 +340,8 @@ func buildWeightedWaypoints(
 		return nil
 	}
 	return []*workloadapi.WeightedWaypoint{
-		{Destination: primary.GetAddress(), Weight: 100 - weight},
-		{Destination: canary.GetAddress(), Weight: weight},
+		{Destination: primary.AddressFor(o), Weight: 100 - weight},
+		{Destination: canary.AddressFor(o), Weight: weight},
 	}
 }
 
@@ -528,16 +535,46 @@ func makeWaypoint(
 	netw network.ID,
 ) *Waypoint {
 	binding := makeInboundBinding(gateway, gatewayClass)
+	address := getGatewayAddress(gateway, netw)
 	return &Waypoint{
 		Named:           krt.NewNamed(gateway),
-		Address:         getGatewayAddress(gateway, netw),
+		Address:         address,
 		DefaultBinding:  binding,
 		AllowedRoutes:   makeAllowedRoutes(gateway, binding),
 		TrafficType:     trafficType,
 		ServiceAccounts: slices.Sort(serviceAccounts),
+		Shards:          getShardAddresses(gateway, address),
 	}
 }
 
+// getShardAddresses returns the addresses of the shards of a sharded waypoint. Each shard has its own Service, named
+// after the Service of the waypoint, so shards can only be addressed by hostname.
+func getShardAddresses(gateway *gatewayv1.Gateway, address *workloadapi.GatewayAddress) []*workloadapi.GatewayAddress {
+	shards := model.WaypointShards(gateway.Annotations)
+	if shards == 0 {
+		return nil
+	}
+	hostname := address.GetHostname()
+	if hostname == nil {
+		log.Warnf("waypoint %s/%s is sharded but has no hostname address, ignoring shards", gateway.Namespace, gateway.Name)
+		return nil
+	}
+	name, domain, _ := strings.Cut(hostname.Hostname, ".")
+	res := make([]*workloadapi.GatewayAddress, 0, shards)
+	for i := range shards {
+		res = append(res, &workloadapi.GatewayAddress{
+			Destination: &workloadapi.GatewayAddress_Hostname{
+				Hostname: &workloadapi.NamespacedHostname{
+					Namespace: hostname.Namespace,
+					Hostname:  model.WaypointShardName(name, i) + "." + domain,
+				},
+			},
+			HboneMtlsPort: address.HboneMtlsPort,
+		})
+	}
+	return res
+}
+
 type WaypointSelector struct {
 	FromNamespaces gatewayv1.FromNamespaces
 	Selector       labels.Selector
@@ -593,6 +630,18 @@ func (w *Waypoint) GetAddress() *workloadapi.GatewayAddress {
 	return w.Address
 }
 
+// AddressFor returns the address a Service uses to reach the waypoint. This is the address of the shard of the
+// Service for sharded waypoints.
+func (w *Waypoint) AddressFor(o metav1.ObjectMeta) *workloadapi.GatewayAddress {
+	if w == nil {
+		return nil
+	}
+	if len(w.Shards) == 0 {
+		return w.Address
+	}
+	return w.Shards[model.WaypointShardFor(o.Namespace, o.Name, o.Labels, len(w.Shards))]
+}
+
 // makeAllowedRoutes returns a WaypointSelector that matches the listener with the given binding
 // if we don't have a binding we use the default HBONE listener
 // if we have a binding we use the protocol and port defined in the binding
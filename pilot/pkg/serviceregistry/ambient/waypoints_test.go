@@ -377,6 +377,59 @@ func TestGetCanaryWeight(t *testing.T) {
 	}
 }
 
+func TestGetShardAddresses(t *testing.T) {
+	hostnameAddress := func(hostname string) *workloadapi.GatewayAddress {
+		return &workloadapi.GatewayAddress{
+			Destination: &workloadapi.GatewayAddress_Hostname{
+				Hostname: &workloadapi.NamespacedHostname{Namespace: "ns", Hostname: hostname},
+			},
+			HboneMtlsPort: 15008,
+		}
+	}
+	gateway := func(shards string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{ObjectMeta: metav1.ObjectMeta{
+			Name:        "waypoint",
+			Namespace:   "ns",
+			Annotations: map[string]string{constants.WaypointShardsAnnotation: shards},
+		}}
+	}
+	cases := []struct {
+		name    string
+		gateway *gatewayv1.Gateway
+		address *workloadapi.GatewayAddress
+		want    []*workloadapi.GatewayAddress
+	}{
+		{
+			name:    "not sharded",
+			gateway: &gatewayv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "waypoint", Namespace: "ns"}},
+			address: hostnameAddress("waypoint.ns.svc.cluster.local"),
+		},
+		{
+			name:    "sharded",
+			gateway: gateway("2"),
+			address: hostnameAddress("waypoint.ns.svc.cluster.local"),
+			want: []*workloadapi.GatewayAddress{
+				hostnameAddress("waypoint-shard-0.ns.svc.cluster.local"),
+				hostnameAddress("waypoint-shard-1.ns.svc.cluster.local"),
+			},
+		},
+		{
+			name:    "sharded without hostname",
+			gateway: gateway("2"),
+			address: &workloadapi.GatewayAddress{
+				Destination: &workloadapi.GatewayAddress_Address{
+					Address: &workloadapi.NetworkAddress{Address: []byte{10, 0, 0, 1}},
+				},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, getShardAddresses(tc.gateway, tc.address), tc.want)
+		})
+	}
+}
+
 func TestServiceOwningWaypoints(t *testing.T) {
 	primary := &workloadapi.GatewayAddress{
 		Destination:   &workloadapi.GatewayAddress_Hostname{Hostname: &workloadapi.NamespacedHostname{Namespace: "ns", Hostname: "primary"}},
@@ -751,6 +751,9 @@ func podWorkloadBuilder(
 		if p.Spec.HostNetwork {
 			w.NetworkMode = workloadapi.NetworkMode_HOST_NETWORK
 		}
+		if capacity := kube.PodCapacity(p); capacity > 0 {
+			w.Capacity = wrappers.UInt32(capacity)
+		}
 
 		w.WorkloadName = workloadName(p)
 		w.WorkloadType = workloadapi.WorkloadType_POD // backwards compatibility
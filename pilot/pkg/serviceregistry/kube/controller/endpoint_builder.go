@@ -37,6 +37,7 @@ type EndpointBuilder struct {
 	tlsMode        string
 	workloadName   string
 	namespace      string
+	capacity       uint32
 
 	// Values used to build dns name tables per pod.
 	// The hostname of the Pod, by default equals to pod name.
@@ -76,6 +77,7 @@ func (c *Controller) NewEndpointBuilder(pod *v1.Pod) *EndpointBuilder {
 		tlsMode:      kube.PodTLSMode(pod),
 		workloadName: dm.Name,
 		namespace:    namespace,
+		capacity:     kube.PodCapacity(pod),
 		hostname:     hostname,
 		subDomain:    subdomain,
 		labels:       podLabels,
@@ -122,6 +124,7 @@ func (b *EndpointBuilder) buildIstioEndpoint(
 		HealthStatus:           healthStatus,
 		SendUnhealthyEndpoints: sendUnhealthy,
 		NodeName:               b.nodeName,
+		LbWeight:               b.capacity,
 	}
 }
 
@@ -19,6 +19,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/gateway-api/pkg/consts"
 
 	meshconfig "istio.io/api/mesh/v1alpha1"
 	"istio.io/istio/pilot/pkg/model"
@@ -28,6 +29,7 @@ import (
 	"istio.io/istio/pkg/cluster"
 	"istio.io/istio/pkg/config/mesh"
 	"istio.io/istio/pkg/config/mesh/meshwatcher"
+	"istio.io/istio/pkg/config/schema/gvr"
 	kubelib "istio.io/istio/pkg/kube"
 	"istio.io/istio/pkg/kube/kclient"
 	"istio.io/istio/pkg/kube/kclient/clienttest"
@@ -144,6 +146,13 @@ func NewFakeControllerWithOptions(t test.Failer, opts FakeControllerOptions) (*F
 		})
 	}
 	for _, crd := range opts.CRDs {
+		if crd.Group == gvr.KubernetesGateway.Group {
+			// Gateway API CRDs are only watched if they carry a recent enough bundle version.
+			clienttest.MakeCRDWithAnnotations(t, c.client, crd, map[string]string{
+				consts.BundleVersionAnnotation: consts.BundleVersion,
+			})
+			continue
+		}
 		clienttest.MakeCRD(t, c.client, crd)
 	}
 	opts.Client.RunAndWait(c.stop)
@@ -24,6 +24,7 @@ import (
 	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/kube/kclient"
 	"istio.io/istio/pkg/maps"
 	"istio.io/istio/pkg/slices"
@@ -137,8 +138,9 @@ func GetPodConditionFromList(conditions []v1.PodCondition, conditionType v1.PodC
 func (pc *PodCache) labelFilter(old, cur *v1.Pod) bool {
 	// If labels/annotations updated, trigger proxy push
 	labelsChanged := !maps.Equal(old.Labels, cur.Labels)
-	// Annotations are only used in endpoints in one case, so just compare that one
-	relevantAnnotationsChanged := old.Annotations[annotation.AmbientRedirection.Name] != cur.Annotations[annotation.AmbientRedirection.Name]
+	// Annotations are only used in endpoints for ambient redirection and capacity, so just compare those
+	relevantAnnotationsChanged := old.Annotations[annotation.AmbientRedirection.Name] != cur.Annotations[annotation.AmbientRedirection.Name] ||
+		old.Annotations[constants.WorkloadCapacityAnnotation] != cur.Annotations[constants.WorkloadCapacityAnnotation]
 	changed := labelsChanged || relevantAnnotationsChanged
 	return changed
 }
@@ -28,6 +28,7 @@ import (
 
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pilot/pkg/serviceregistry/util/xdsfake"
+	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/labels"
 	"istio.io/istio/pkg/kube/kclient/clienttest"
 	"istio.io/istio/pkg/test"
@@ -147,6 +148,35 @@ func TestPodLabelUpdate(t *testing.T) {
 	assert.Equal(t, map[string]string{"app": "test", "foo": "not-bar"}, got[0].Labels)
 }
 
+func TestPodCapacityUpdate(t *testing.T) {
+	c, fx := NewFakeControllerWithOptions(t, FakeControllerOptions{
+		WatchedNamespaces: "nsa,nsb",
+	})
+
+	initTestEnv(t, c.client.Kube(), fx)
+	createServiceWait(c, "ratings", "nsa", []string{"10.0.0.1"},
+		nil, nil, []int32{8080}, map[string]string{"app": "test"}, t)
+	pod := generatePod([]string{"128.0.0.1"}, "cpod1", "nsa", "", "", map[string]string{"app": "test"}, map[string]string{})
+	addPods(t, c, fx, pod)
+	createEndpoints(t, c, "ratings", "nsa", []string{"tcp-port"}, []string{"128.0.0.1"}, []*v1.ObjectReference{
+		{
+			Kind:      "Pod",
+			Namespace: "nsa",
+			Name:      "cpod1",
+		},
+	}, nil)
+	fx.WaitOrFail(t, "eds")
+	fx.Clear()
+
+	pod.Annotations[constants.WorkloadCapacityAnnotation] = "3"
+	clienttest.Wrap(t, c.podsClient).CreateOrUpdate(pod)
+	ev := fx.WaitOrFail(t, "eds cache")
+	assert.Equal(t, ev.ID, "ratings.nsa.svc.company.com")
+	assert.Equal(t, len(ev.Endpoints), 1)
+	assert.Equal(t, ev.Endpoints[0].LbWeight, uint32(3))
+	fx.MatchOrFail(t, xdsfake.Event{Type: "xds", ID: "ratings.nsa.svc.company.com"})
+}
+
 func TestHostNetworkPod(t *testing.T) {
 	c, fx := NewFakeControllerWithOptions(t, FakeControllerOptions{})
 	pods := clienttest.Wrap(t, c.podsClient)
@@ -16,6 +16,7 @@ package kube
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -188,6 +189,22 @@ func PodTLSMode(pod *corev1.Pod) string {
 	return model.GetTLSModeFromEndpointLabels(pod.Labels)
 }
 
+// PodCapacity returns the capacity of the pod set with the capacity annotation, or 0 if unset or invalid.
+func PodCapacity(pod *corev1.Pod) uint32 {
+	if pod == nil {
+		return 0
+	}
+	v, f := pod.Annotations[constants.WorkloadCapacityAnnotation]
+	if !f {
+		return 0
+	}
+	capacity, err := strconv.ParseUint(v, 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint32(capacity)
+}
+
 // IsAutoPassthrough determines if a listener should use auto passthrough mode. This is used for
 // multi-network. In the Istio API, this is an explicit tls.Mode. However, this mode is not part of
 // the gateway-api, and leaks implementation details. We already have an API to declare a Gateway as
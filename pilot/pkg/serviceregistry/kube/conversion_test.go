@@ -26,6 +26,7 @@ import (
 
 	"istio.io/api/annotation"
 	"istio.io/istio/pkg/cluster"
+	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/kube"
 	"istio.io/istio/pkg/config/protocol"
 	"istio.io/istio/pkg/config/visibility"
@@ -527,3 +528,28 @@ func TestSecureNamingSAN(t *testing.T) {
 		t.Fatalf("SAN match failed, SAN:%v  expectedSAN:%v", san, expectedSAN)
 	}
 }
+
+func TestPodCapacity(t *testing.T) {
+	cases := []struct {
+		value string
+		want  uint32
+	}{
+		{"", 0},
+		{"abc", 0},
+		{"-1", 0},
+		{"0", 0},
+		{"3", 3},
+		{"4294967296", 0},
+	}
+	for _, tt := range cases {
+		t.Run(tt.value, func(t *testing.T) {
+			pod := &corev1.Pod{}
+			if tt.value != "" {
+				pod.Annotations = map[string]string{constants.WorkloadCapacityAnnotation: tt.value}
+			}
+			if got := PodCapacity(pod); got != tt.want {
+				t.Fatalf("got capacity %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
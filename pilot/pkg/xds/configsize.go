@@ -0,0 +1,287 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"google.golang.org/protobuf/proto"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/networking/util"
+	"istio.io/istio/pilot/pkg/serviceregistry/provider"
+	v3 "istio.io/istio/pilot/pkg/xds/v3"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/config/schema/kind"
+)
+
+// ConfigSizeDebug summarizes the size of the configuration generated for a proxy.
+type ConfigSizeDebug struct {
+	ProxyID    string              `json:"proxy"`
+	TotalBytes int                 `json:"total_bytes"`
+	Resources  ConfigSizeResources `json:"resources"`
+	// Contributors lists the config objects responsible for the generated configuration,
+	// largest first. It is only populated when a single proxy is requested.
+	Contributors []ConfigSizeContributor `json:"contributors,omitempty"`
+}
+
+// ConfigSizeResources holds the size of each type of generated resource.
+type ConfigSizeResources struct {
+	Listeners    ConfigSizeResource `json:"listeners"`
+	Routes       ConfigSizeResource `json:"routes"`
+	VirtualHosts ConfigSizeResource `json:"virtual_hosts"`
+	Clusters     ConfigSizeResource `json:"clusters"`
+	Endpoints    ConfigSizeResource `json:"endpoints"`
+}
+
+// ConfigSizeResource is the number of resources of a type, and their serialized size.
+type ConfigSizeResource struct {
+	Count int `json:"count"`
+	Bytes int `json:"bytes"`
+}
+
+// ConfigSizeContributor is a config object along with the generated configuration attributed to it.
+type ConfigSizeContributor struct {
+	Kind         string `json:"kind"`
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	Clusters     int    `json:"clusters,omitempty"`
+	VirtualHosts int    `json:"virtual_hosts,omitempty"`
+	Endpoints    int    `json:"endpoints,omitempty"`
+	Bytes        int    `json:"bytes"`
+}
+
+// ConfigSize reports the size of the configuration generated for proxies connected to this instance.
+// With a proxyID, the configuration is attributed to the Services, ServiceEntries, VirtualServices and
+// DestinationRules that caused it to be generated, ranked by size; "top" limits the number of entries.
+// Without a proxyID, a summary is returned for each connected proxy in "namespace", largest first.
+// Measuring a proxy regenerates its full configuration, so one of proxyID or namespace is required
+// to keep the cost of a request bounded.
+func (s *DiscoveryServer) ConfigSize(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Query().Get("proxyID") == "" {
+		namespace := req.URL.Query().Get("namespace")
+		if namespace == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("one of proxyID or namespace is required\n"))
+			return
+		}
+		sizes := make([]ConfigSizeDebug, 0)
+		for _, con := range s.SortedClients() {
+			if con.proxy == nil || con.proxy.GetNamespace() != namespace {
+				continue
+			}
+			out := *con
+			out.proxy = cloneProxy(con.proxy)
+			sizes = append(sizes, s.configSize(&out, false))
+		}
+		sort.SliceStable(sizes, func(i, j int) bool {
+			return sizes[i].TotalBytes > sizes[j].TotalBytes
+		})
+		writeJSON(w, sizes, req)
+		return
+	}
+
+	proxyID, con := s.getDebugConnection(req)
+	if con == nil {
+		s.errorHandler(w, proxyID, con)
+		return
+	}
+	size := s.configSize(con, true)
+	if top := req.URL.Query().Get("top"); top != "" {
+		n, err := strconv.Atoi(top)
+		if err != nil || n < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("top must be a non-negative integer\n"))
+			return
+		}
+		if n < len(size.Contributors) {
+			size.Contributors = size.Contributors[:n]
+		}
+	}
+	writeJSON(w, size, req)
+}
+
+type contributorKey struct {
+	kind      string
+	name      string
+	namespace string
+}
+
+// configSizeAttribution accumulates the generated configuration attributed to each config object.
+type configSizeAttribution struct {
+	proxy        *model.Proxy
+	contributors map[contributorKey]*ConfigSizeContributor
+}
+
+func (a *configSizeAttribution) get(k contributorKey) *ConfigSizeContributor {
+	c, f := a.contributors[k]
+	if !f {
+		c = &ConfigSizeContributor{Kind: k.kind, Name: k.name, Namespace: k.namespace}
+		a.contributors[k] = c
+	}
+	return c
+}
+
+// serviceOwner returns the Service or ServiceEntry that defines the hostname, as seen by the proxy.
+func (a *configSizeAttribution) serviceOwner(hostname host.Name) (contributorKey, bool) {
+	if a.proxy.SidecarScope == nil {
+		return contributorKey{}, false
+	}
+	svcs := a.proxy.SidecarScope.ServicesForHostname(hostname)
+	if len(svcs) == 0 {
+		return contributorKey{}, false
+	}
+	svc := svcs[0]
+	if svc.Attributes.ServiceRegistry == provider.External && svc.Attributes.K8sAttributes.ObjectName != "" {
+		return contributorKey{kind: kind.ServiceEntry.String(), name: svc.Attributes.K8sAttributes.ObjectName, namespace: svc.Attributes.Namespace}, true
+	}
+	return contributorKey{kind: kind.Service.String(), name: svc.Attributes.Name, namespace: svc.Attributes.Namespace}, true
+}
+
+// clusterOwner returns the config object responsible for an outbound cluster. Subset clusters only
+// exist because of a DestinationRule, so they are attributed to it; other clusters belong to the service.
+func (a *configSizeAttribution) clusterOwner(name string, md *core.Metadata) (contributorKey, bool) {
+	dir, subset, hostname, _ := model.ParseSubsetKey(name)
+	if dir != model.TrafficDirectionOutbound && dir != model.TrafficDirectionOutboundUDP {
+		return contributorKey{}, false
+	}
+	if subset != "" {
+		if k, ok := configFromMetadata(md, kind.DestinationRule); ok {
+			return k, true
+		}
+	}
+	return a.serviceOwner(hostname)
+}
+
+// virtualHostOwner returns the config object responsible for a virtual host: the VirtualService
+// that configured its routes if there is one, or else the service it was generated for.
+func (a *configSizeAttribution) virtualHostOwner(vh *route.VirtualHost) (contributorKey, bool) {
+	for _, r := range vh.GetRoutes() {
+		if k, ok := configFromMetadata(r.GetMetadata(), kind.VirtualService); ok {
+			return k, true
+		}
+	}
+	hostname := vh.GetName()
+	if idx := strings.LastIndex(hostname, ":"); idx > 0 {
+		hostname = hostname[:idx]
+	}
+	return a.serviceOwner(host.Name(hostname))
+}
+
+// configFromMetadata extracts the config object recorded by util.AddConfigInfoMetadata, if it is of the given kind.
+func configFromMetadata(md *core.Metadata, k kind.Kind) (contributorKey, bool) {
+	path := md.GetFilterMetadata()[util.IstioMetadataKey].GetFields()["config"].GetStringValue()
+	// Format: /apis/<group>/<version>/namespaces/<namespace>/<kind>/<name>
+	parts := strings.Split(path, "/")
+	if len(parts) != 8 || parts[6] != gvk.KebabKind(k.String()) {
+		return contributorKey{}, false
+	}
+	return contributorKey{kind: k.String(), name: parts[7], namespace: parts[5]}, true
+}
+
+// configSize generates the configuration for the connection and measures it. If attribute is set, the
+// configuration is also attributed to the config objects that contributed to it.
+func (s *DiscoveryServer) configSize(con *Connection, attribute bool) ConfigSizeDebug {
+	out := ConfigSizeDebug{ProxyID: con.proxy.ID}
+	a := &configSizeAttribution{proxy: con.proxy, contributors: map[contributorKey]*ConfigSizeContributor{}}
+
+	dump := s.getConfigDumpByResourceType(con, nil, []string{v3.ListenerType, v3.RouteType, v3.ClusterType, v3.EndpointType})
+	for _, rr := range dump[v3.ListenerType] {
+		out.Resources.Listeners.Count++
+		out.Resources.Listeners.Bytes += len(rr.GetResource().GetValue())
+	}
+	for _, rr := range dump[v3.RouteType] {
+		out.Resources.Routes.Count++
+		out.Resources.Routes.Bytes += len(rr.GetResource().GetValue())
+		rc := &route.RouteConfiguration{}
+		if err := rr.GetResource().UnmarshalTo(rc); err != nil {
+			log.Warnf("failed to unmarshal route configuration %s: %v", rr.Name, err)
+			continue
+		}
+		for _, vh := range rc.GetVirtualHosts() {
+			size := proto.Size(vh)
+			out.Resources.VirtualHosts.Count++
+			out.Resources.VirtualHosts.Bytes += size
+			if !attribute {
+				continue
+			}
+			if k, ok := a.virtualHostOwner(vh); ok {
+				c := a.get(k)
+				c.VirtualHosts++
+				c.Bytes += size
+			}
+		}
+	}
+	owners := map[string]contributorKey{}
+	for _, rr := range dump[v3.ClusterType] {
+		size := len(rr.GetResource().GetValue())
+		out.Resources.Clusters.Count++
+		out.Resources.Clusters.Bytes += size
+		if !attribute {
+			continue
+		}
+		c := &cluster.Cluster{}
+		if err := rr.GetResource().UnmarshalTo(c); err != nil {
+			log.Warnf("failed to unmarshal cluster %s: %v", rr.Name, err)
+			continue
+		}
+		if k, ok := a.clusterOwner(c.GetName(), c.GetMetadata()); ok {
+			owners[c.GetName()] = k
+			contributor := a.get(k)
+			contributor.Clusters++
+			contributor.Bytes += size
+		}
+	}
+	for _, rr := range dump[v3.EndpointType] {
+		size := len(rr.GetResource().GetValue())
+		out.Resources.Endpoints.Count++
+		out.Resources.Endpoints.Bytes += size
+		if k, ok := owners[rr.Name]; ok {
+			c := a.get(k)
+			c.Endpoints++
+			c.Bytes += size
+		}
+	}
+
+	// Virtual hosts are already accounted for in the size of the routes.
+	out.TotalBytes = out.Resources.Listeners.Bytes + out.Resources.Routes.Bytes + out.Resources.Clusters.Bytes + out.Resources.Endpoints.Bytes
+	if attribute {
+		out.Contributors = make([]ConfigSizeContributor, 0, len(a.contributors))
+		for _, c := range a.contributors {
+			out.Contributors = append(out.Contributors, *c)
+		}
+		sort.Slice(out.Contributors, func(i, j int) bool {
+			ci, cj := out.Contributors[i], out.Contributors[j]
+			if ci.Bytes != cj.Bytes {
+				return ci.Bytes > cj.Bytes
+			}
+			if ci.Kind != cj.Kind {
+				return ci.Kind < cj.Kind
+			}
+			if ci.Namespace != cj.Namespace {
+				return ci.Namespace < cj.Namespace
+			}
+			return ci.Name < cj.Name
+		})
+	}
+	return out
+}
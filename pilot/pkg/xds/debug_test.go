@@ -227,6 +227,118 @@ func getConfigDump(t *testing.T, s *xds.DiscoveryServer, proxyID string, wantCod
 	return got
 }
 
+func TestConfigSize(t *testing.T) {
+	s := xdsfake.NewFakeDiscoveryServer(t, xdsfake.FakeOptions{ConfigString: `
+apiVersion: networking.istio.io/v1
+kind: ServiceEntry
+metadata:
+  name: example
+  namespace: default
+spec:
+  hosts: [example.com]
+  ports:
+  - number: 80
+    name: http
+    protocol: HTTP
+  resolution: STATIC
+  endpoints:
+  - address: 1.1.1.1
+    labels:
+      version: v1
+---
+apiVersion: networking.istio.io/v1
+kind: DestinationRule
+metadata:
+  name: example
+  namespace: default
+spec:
+  host: example.com
+  subsets:
+  - name: v1
+    labels:
+      version: v1
+  - name: v2
+    labels:
+      version: v2
+---
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: example
+  namespace: default
+spec:
+  hosts: [example.com]
+  http:
+  - route:
+    - destination:
+        host: example.com
+        subset: v1
+`})
+	ads := s.ConnectADS().WithMetadata(model.NodeMetadata{Namespace: "default"})
+	ads.RequestResponseAck(t, &discovery.DiscoveryRequest{TypeUrl: v3.ClusterType})
+	ads.RequestResponseAck(t, &discovery.DiscoveryRequest{TypeUrl: v3.ListenerType})
+	ads.RequestResponseAck(t, &discovery.DiscoveryRequest{TypeUrl: v3.RouteType, ResourceNames: []string{"80"}})
+	ads.RequestResponseAck(t, &discovery.DiscoveryRequest{
+		TypeUrl:       v3.EndpointType,
+		ResourceNames: []string{"outbound|80||example.com", "outbound|80|v1|example.com", "outbound|80|v2|example.com"},
+	})
+
+	getConfigSize := func(path string, wantCode int) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(s.Discovery.ConfigSize).ServeHTTP(rr, req)
+		if rr.Code != wantCode {
+			t.Fatalf("wanted response code %v, got %v", wantCode, rr.Code)
+		}
+		return rr
+	}
+
+	t.Run("proxy", func(t *testing.T) {
+		got := xds.ConfigSizeDebug{}
+		assert.NoError(t, json.Unmarshal(getConfigSize("/configsize?proxyID=test.default", 200).Body.Bytes(), &got))
+		if got.TotalBytes == 0 || got.Resources.Clusters.Count == 0 || got.Resources.Listeners.Count == 0 {
+			t.Fatalf("expected generated config to be measured, got %+v", got)
+		}
+		assert.Equal(t, got.Resources.Endpoints.Count, 3)
+		contributors := map[string]xds.ConfigSizeContributor{}
+		for _, c := range got.Contributors {
+			c.Bytes = 0
+			contributors[c.Kind+"/"+c.Namespace+"/"+c.Name] = c
+		}
+		assert.Equal(t, contributors["DestinationRule/default/example"], xds.ConfigSizeContributor{
+			Kind: "DestinationRule", Name: "example", Namespace: "default", Clusters: 2, Endpoints: 2,
+		})
+		assert.Equal(t, contributors["ServiceEntry/default/example"], xds.ConfigSizeContributor{
+			Kind: "ServiceEntry", Name: "example", Namespace: "default", Clusters: 1, Endpoints: 1,
+		})
+		assert.Equal(t, contributors["VirtualService/default/example"], xds.ConfigSizeContributor{
+			Kind: "VirtualService", Name: "example", Namespace: "default", VirtualHosts: 1,
+		})
+	})
+	t.Run("top", func(t *testing.T) {
+		got := xds.ConfigSizeDebug{}
+		assert.NoError(t, json.Unmarshal(getConfigSize("/configsize?proxyID=test.default&top=1", 200).Body.Bytes(), &got))
+		assert.Equal(t, len(got.Contributors), 1)
+		getConfigSize("/configsize?proxyID=test.default&top=x", 400)
+	})
+	t.Run("namespace", func(t *testing.T) {
+		got := []xds.ConfigSizeDebug{}
+		assert.NoError(t, json.Unmarshal(getConfigSize("/configsize?namespace=default", 200).Body.Bytes(), &got))
+		assert.Equal(t, len(got), 1)
+		assert.Equal(t, got[0].ProxyID, "test.default")
+		assert.Equal(t, len(got[0].Contributors), 0)
+		assert.NoError(t, json.Unmarshal(getConfigSize("/configsize?namespace=other", 200).Body.Bytes(), &got))
+		assert.Equal(t, len(got), 0)
+		getConfigSize("/configsize", 400)
+	})
+	t.Run("not found", func(t *testing.T) {
+		getConfigSize("/configsize?proxyID=not-found", 404)
+	})
+}
+
 func TestDebugHandlers(t *testing.T) {
 	s := xdsfake.NewFakeDiscoveryServer(t, xdsfake.FakeOptions{})
 	req, err := http.NewRequest(http.MethodGet, "/debug", nil)
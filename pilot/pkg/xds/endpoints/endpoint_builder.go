@@ -151,7 +151,11 @@ func (b *EndpointBuilder) servicePort(port int) *model.Port {
 		log.Debugf("can not find the service %s for cluster %s", b.hostname, b.clusterName)
 		return nil
 	}
-	svcPort, f := b.service.Ports.GetByPort(port)
+	getByPort := b.service.Ports.GetByPort
+	if b.dir == model.TrafficDirectionOutboundUDP {
+		getByPort = b.service.Ports.GetUDPByPort
+	}
+	svcPort, f := getByPort(port)
 	if !f {
 		log.Debugf("can not find the service port %d for cluster %s", b.port, b.clusterName)
 		return nil
@@ -51,6 +51,7 @@ func All() []analysis.Analyzer {
 		&deployment.ApplicationUIDAnalyzer{},
 		&destinationrule.CaCertificateAnalyzer{},
 		&destinationrule.PodNotSelectedAnalyzer{},
+		&destinationrule.ProxylessGRPCAnalyzer{},
 		&deprecation.FieldAnalyzer{},
 		&envoyfilter.EnvoyPatchAnalyzer{},
 		&externalcontrolplane.ExternalControlPlaneAnalyzer{},
@@ -1088,6 +1088,16 @@ var testGrid = []testCase{
 			// - "mixed-labels": matches both version from pod AND topology.kubernetes.io/region from node
 		},
 	},
+	{
+		name:       "DestinationRuleProxylessGRPC",
+		inputFiles: []string{"testdata/destinationrule-proxyless-grpc.yaml"},
+		analyzer:   &destinationrule.ProxylessGRPCAnalyzer{},
+		expected: []message{
+			{msg.UnsupportedProxylessGRPCTrafficPolicy, "DestinationRule default/mutual-without-credential"},
+			{msg.UnsupportedProxylessGRPCTrafficPolicy, "DestinationRule default/subset-outlier"},
+			{msg.UnsupportedProxylessGRPCTrafficPolicy, "DestinationRule default/subset-outlier"},
+		},
+	},
 	{
 		name:       "DestinationRuleEmptyTopologyLabels",
 		inputFiles: []string{"testdata/destinationrule-empty-topology-labels.yaml"},
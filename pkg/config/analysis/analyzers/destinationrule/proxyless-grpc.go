@@ -0,0 +1,118 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destinationrule
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"istio.io/api/annotation"
+	"istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pilot/pkg/networking/grpcgen"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/analysis"
+	"istio.io/istio/pkg/config/analysis/msg"
+	"istio.io/istio/pkg/config/resource"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/slices"
+	"istio.io/istio/pkg/util/sets"
+)
+
+// proxylessGRPCTemplates are the injection templates of proxyless gRPC workloads.
+var proxylessGRPCTemplates = sets.New("grpc-agent", "grpc-simple")
+
+// ProxylessGRPCAnalyzer checks for DestinationRules visible to proxyless gRPC workloads that use traffic policy
+// settings gRPC cannot apply. Istiod only reports these per proxy, in the pilot_grpc_unsupported_traffic_policy metric.
+type ProxylessGRPCAnalyzer struct{}
+
+var _ analysis.Analyzer = &ProxylessGRPCAnalyzer{}
+
+func (a *ProxylessGRPCAnalyzer) Metadata() analysis.Metadata {
+	return analysis.Metadata{
+		Name:        "destinationrule.ProxylessGRPCAnalyzer",
+		Description: "Checks for DestinationRule settings that proxyless gRPC workloads cannot apply",
+		Inputs: []config.GroupVersionKind{
+			gvk.DestinationRule,
+			gvk.Pod,
+		},
+	}
+}
+
+func (a *ProxylessGRPCAnalyzer) Analyze(ctx analysis.Context) {
+	var pods []*resource.Instance
+	ctx.ForEach(gvk.Pod, func(r *resource.Instance) bool {
+		templates := strings.Split(r.Metadata.Annotations[annotation.InjectTemplates.Name], ",")
+		if slices.FindFunc(templates, func(t string) bool { return proxylessGRPCTemplates.Contains(strings.TrimSpace(t)) }) != nil {
+			pods = append(pods, r)
+		}
+		return true
+	})
+	if len(pods) == 0 {
+		return
+	}
+
+	ctx.ForEach(gvk.DestinationRule, func(r *resource.Instance) bool {
+		workloads := visibleWorkloads(r, pods)
+		if len(workloads) == 0 {
+			return true
+		}
+		dr := r.Message.(*v1alpha3.DestinationRule)
+		report := func(prefix string, policy *v1alpha3.TrafficPolicy) {
+			for _, u := range grpcgen.UnsupportedTrafficPolicy(policy) {
+				ctx.Report(gvk.DestinationRule, msg.NewUnsupportedProxylessGRPCTrafficPolicy(r, workloads, prefix+u.Setting, u.Reason))
+			}
+		}
+		report("trafficPolicy.", dr.GetTrafficPolicy())
+		for i, p := range dr.GetTrafficPolicy().GetPortLevelSettings() {
+			report(fmt.Sprintf("trafficPolicy.portLevelSettings[%d].", i), &v1alpha3.TrafficPolicy{
+				LoadBalancer:     p.GetLoadBalancer(),
+				ConnectionPool:   p.GetConnectionPool(),
+				OutlierDetection: p.GetOutlierDetection(),
+				Tls:              p.GetTls(),
+			})
+		}
+		for _, subset := range dr.GetSubsets() {
+			report(fmt.Sprintf("subsets[%s].trafficPolicy.", subset.GetName()), subset.GetTrafficPolicy())
+		}
+		return true
+	})
+}
+
+// visibleWorkloads returns the proxyless gRPC pods the DestinationRule may apply to, based on its workload selector
+// and exportTo.
+func visibleWorkloads(r *resource.Instance, pods []*resource.Instance) string {
+	dr := r.Message.(*v1alpha3.DestinationRule)
+	drNs := r.Metadata.FullName.Namespace.String()
+	exportTo := sets.New(dr.GetExportTo()...)
+	var selector labels.Selector
+	if dr.GetWorkloadSelector() != nil {
+		selector = labels.SelectorFromSet(dr.GetWorkloadSelector().GetMatchLabels())
+	}
+	var names []string
+	for _, pod := range pods {
+		podNs := pod.Metadata.FullName.Namespace.String()
+		if selector != nil {
+			if podNs != drNs || !selector.Matches(labels.Set(pod.Metadata.Labels)) {
+				continue
+			}
+		} else if podNs != drNs && len(exportTo) > 0 && !exportTo.Contains("*") && !exportTo.Contains(podNs) {
+			continue
+		}
+		names = append(names, pod.Metadata.FullName.String())
+	}
+	return strings.Join(slices.Sort(names), ", ")
+}
@@ -279,6 +279,10 @@ var (
 	// ConflictingServiceEntryProtocol defines a diag.MessageType for message "ConflictingServiceEntryProtocol".
 	// Description: Multiple ServiceEntries define the same host and port with conflicting protocols.
 	ConflictingServiceEntryProtocol = diag.NewMessageType(diag.Warning, "IST0177", "Multiple ServiceEntries (%s) define the same host %q and port %d with conflicting protocols (%s).")
+
+	// UnsupportedProxylessGRPCTrafficPolicy defines a diag.MessageType for message "UnsupportedProxylessGRPCTrafficPolicy".
+	// Description: A DestinationRule visible to proxyless gRPC workloads uses a traffic policy setting that gRPC cannot apply.
+	UnsupportedProxylessGRPCTrafficPolicy = diag.NewMessageType(diag.Warning, "IST0178", "The DestinationRule is visible to proxyless gRPC workloads (%s), which cannot apply %s: %s.")
 )
 
 // All returns a list of all known message types.
@@ -352,6 +356,7 @@ func All() []*diag.MessageType {
 		JwksUriFetchUnrestricted,
 		GatewayAPICRDVersionBelowMinimum,
 		ConflictingServiceEntryProtocol,
+		UnsupportedProxylessGRPCTrafficPolicy,
 	}
 }
 
@@ -1026,3 +1031,14 @@ func NewConflictingServiceEntryProtocol(r *resource.Instance, serviceEntryNames
 		protocols,
 	)
 }
+
+// NewUnsupportedProxylessGRPCTrafficPolicy returns a new diag.Message based on UnsupportedProxylessGRPCTrafficPolicy.
+func NewUnsupportedProxylessGRPCTrafficPolicy(r *resource.Instance, workloads string, setting string, reason string) diag.Message {
+	return diag.NewMessage(
+		UnsupportedProxylessGRPCTrafficPolicy,
+		r,
+		workloads,
+		setting,
+		reason,
+	)
+}
@@ -146,6 +146,9 @@ const (
 	GatewaySemanticsGateway       = "gateway"
 	InternalServiceSemantics      = "internal.istio.io/service-semantics"
 	ServiceSemanticsInferencePool = "inferencepool"
+	// InternalBackendClientCertificate contains the credential resource name of the client certificate
+	// a Gateway presents to backends that require TLS.
+	InternalBackendClientCertificate = "internal.istio.io/backend-client-certificate"
 
 	// ThirdPartyJwtPath is the default 3P token to authenticate with third party services
 	ThirdPartyJwtPath = "./var/run/secrets/tokens/istio-token"
@@ -188,6 +191,19 @@ const (
 	AgentgatewayWaypointClassName = "istio-agentgateway-waypoint"
 	EastWestGatewayClassName      = "istio-east-west"
 
+	// WaypointShardsAnnotation splits a waypoint Gateway into the given number of shards, each with its own
+	// Deployment and Service. Services using the waypoint are assigned to a shard by a consistent hash.
+	// TODO formalize this API
+	WaypointShardsAnnotation = "ambient.istio.io/waypoint-shards"
+	// WaypointShardLabel is set on the pods and Service of each waypoint shard to its index. Set on a Service using a
+	// sharded waypoint, it pins the Service to a shard instead.
+	WaypointShardLabel = "ambient.istio.io/waypoint-shard"
+
+	// WorkloadCapacityAnnotation sets the relative capacity of a pod, used to weight the traffic it receives against the
+	// other endpoints of a Service. WorkloadEntries use their weight instead.
+	// TODO formalize this API
+	WorkloadCapacityAnnotation = "ambient.istio.io/capacity"
+
 	// TODO formalize this API
 	// TODO additional values to represent passthrough and hbone or both
 	ListenerModeOption          = "gateway.istio.io/listener-protocol"
@@ -93,6 +93,7 @@ import (
 	"istio.io/istio/pkg/config"
 	"istio.io/istio/pkg/config/schema/collections"
 	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/config/schema/gvr"
 	"istio.io/istio/pkg/kube/informerfactory"
 	"istio.io/istio/pkg/kube/kubetypes"
 	"istio.io/istio/pkg/kube/mcs"
@@ -468,6 +469,35 @@ func NewFakeClientWithVersion(minor string, objects ...runtime.Object) CLIClient
 	return c
 }
 
+// CreateFakeCRD registers a CRD with a fake client, so that informers waiting for the CRD to be installed are started.
+// The metadata client fake is not kept in sync with the CRDs of the fake client, so the CRD is created there directly.
+// Clients that are not fake are left unchanged.
+func CreateFakeCRD(c Client, g schema.GroupVersionResource, annotations map[string]string) error {
+	fmc, ok := c.Metadata().(*metadatafake.FakeMetadataClient)
+	if !ok {
+		return nil
+	}
+	fmd, ok := fmc.Resource(gvr.CustomResourceDefinition).(metadatafake.MetadataClient)
+	if !ok {
+		return nil
+	}
+	obj := &metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s.%s", g.Resource, g.Group),
+			Annotations: annotations,
+		},
+	}
+	if _, err := fmd.CreateFake(obj, metav1.CreateOptions{}); err != nil {
+		if !kerrors.IsAlreadyExists(err) {
+			return err
+		}
+		if _, err := fmd.UpdateFake(obj, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // NewFakeClientWithNFailures creates a fake client that fails for get operations on the specified
 // resource kinds for the first failureCount attempts, then succeeds.
 // failingResources is a set of resource (e.g., "pods", "namespaces") that should fail.
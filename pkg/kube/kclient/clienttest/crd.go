@@ -15,15 +15,8 @@
 package clienttest
 
 import (
-	"fmt"
-
-	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	kerrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	metadatafake "k8s.io/client-go/metadata/fake"
 
-	"istio.io/istio/pkg/config/schema/gvr"
 	"istio.io/istio/pkg/kube"
 	"istio.io/istio/pkg/test"
 )
@@ -35,34 +28,7 @@ func MakeCRD(t test.Failer, c kube.Client, g schema.GroupVersionResource) {
 
 func MakeCRDWithAnnotations(t test.Failer, c kube.Client, g schema.GroupVersionResource, annotations map[string]string) {
 	t.Helper()
-	crd := &v1.CustomResourceDefinition{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:        fmt.Sprintf("%s.%s", g.Resource, g.Group),
-			Annotations: annotations,
-		},
-	}
-	// Metadata client fake is not kept in sync, so if using a fake client update that as well
-	fmc, ok := c.Metadata().(*metadatafake.FakeMetadataClient)
-	if !ok {
-		return
-	}
-	fmg := fmc.Resource(gvr.CustomResourceDefinition)
-	fmd, ok := fmg.(metadatafake.MetadataClient)
-	if !ok {
-		return
-	}
-	obj := &metav1.PartialObjectMetadata{
-		TypeMeta:   crd.TypeMeta,
-		ObjectMeta: crd.ObjectMeta,
-	}
-	if _, err := fmd.CreateFake(obj, metav1.CreateOptions{}); err != nil {
-		if kerrors.IsAlreadyExists(err) {
-			_, err = fmd.UpdateFake(obj, metav1.UpdateOptions{})
-			if err != nil {
-				t.Fatal(err)
-			}
-		} else {
-			t.Fatal(err)
-		}
+	if err := kube.CreateFakeCRD(c, g, annotations); err != nil {
+		t.Fatal(err)
 	}
 }
@@ -0,0 +1,144 @@
+//  Copyright Istio Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package loadbalancer
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"istio.io/istio/pkg/test/loadbalancersim/network"
+)
+
+// overprovisioningFactor is the default overprovisioning factor of Envoy, which allows a priority level to keep all
+// of its traffic until less than about 71% of its connections are healthy.
+const overprovisioningFactor = 1.4
+
+// FailoverSettings configures a load balancer that sends requests to the highest priority level, and spills them
+// over to lower priority levels as connections become unhealthy, following the priority levels of Envoy.
+type FailoverSettings struct {
+	// Connections for each priority level, 0 being the highest priority.
+	Connections [][]*WeightedConnection
+	// Healthy returns false if a connection must not be selected. If not set, all connections are healthy.
+	Healthy func(c *WeightedConnection) bool
+	// NewLB creates the load balancer for the healthy connections of a priority level.
+	NewLB func(conns []*WeightedConnection) network.Connection
+}
+
+func NewFailover(s FailoverSettings) network.Connection {
+	var all []*WeightedConnection
+	for _, conns := range s.Connections {
+		all = append(all, conns...)
+	}
+	if len(all) == 0 {
+		panic("attempting to create load balancer with zero connections")
+	}
+	if s.Healthy == nil {
+		s.Healthy = func(*WeightedConnection) bool { return true }
+	}
+
+	return &failover{
+		weightedConnections: newLBConnection("FailoverLB", all),
+		s:                   s,
+		levels:              make([]failoverLevel, len(s.Connections)),
+		r:                   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+type failover struct {
+	*weightedConnections
+	s      FailoverSettings
+	levels []failoverLevel
+	r      *rand.Rand
+	mutex  sync.Mutex
+}
+
+// failoverLevel caches the load balancer of a priority level, for as long as the same connections are healthy.
+type failoverLevel struct {
+	healthy string
+	lb      network.Connection
+}
+
+func (lb *failover) Request(onDone func()) {
+	lb.mutex.Lock()
+	selected := lb.pick()
+	lb.mutex.Unlock()
+
+	lb.helper.Request(selected.Request, onDone)
+}
+
+func (lb *failover) pick() network.Connection {
+	healthy := make([][]*WeightedConnection, len(lb.s.Connections))
+	health := make([]float64, len(lb.s.Connections))
+	totalHealth := 0.0
+	for p, conns := range lb.s.Connections {
+		for _, c := range conns {
+			if lb.s.Healthy(c) {
+				healthy[p] = append(healthy[p], c)
+			}
+		}
+		if len(conns) > 0 {
+			health[p] = math.Min(100, overprovisioningFactor*100*float64(len(healthy[p]))/float64(len(conns)))
+		}
+		totalHealth += health[p]
+	}
+
+	if totalHealth == 0 {
+		// No connection is healthy: like Envoy in panic mode, use all connections of the highest priority level.
+		for p, conns := range lb.s.Connections {
+			if len(conns) > 0 {
+				return lb.level(p, conns)
+			}
+		}
+	}
+
+	// Assign the load of each priority level in order, normalized by the total health.
+	totalHealth = math.Min(100, totalHealth)
+	x := lb.r.Float64() * 100
+	for p := range lb.s.Connections {
+		load := health[p] * 100 / totalHealth
+		if x < load || p == len(lb.s.Connections)-1 {
+			if len(healthy[p]) > 0 {
+				return lb.level(p, healthy[p])
+			}
+		}
+		x -= load
+	}
+
+	// Only reached because of rounding errors: use the lowest priority level with healthy connections.
+	for p := len(healthy) - 1; p >= 0; p-- {
+		if len(healthy[p]) > 0 {
+			return lb.level(p, healthy[p])
+		}
+	}
+	return nil
+}
+
+func (lb *failover) level(p int, conns []*WeightedConnection) network.Connection {
+	names := make([]string, 0, len(conns))
+	for _, c := range conns {
+		names = append(names, c.Name())
+	}
+	key := strings.Join(names, ",")
+	if lb.levels[p].lb == nil || lb.levels[p].healthy != key {
+		lb.levels[p] = failoverLevel{
+			healthy: key,
+			lb:      lb.s.NewLB(conns),
+		}
+	}
+	return lb.levels[p].lb
+}
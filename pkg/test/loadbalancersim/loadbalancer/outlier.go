@@ -0,0 +1,132 @@
+//  Copyright Istio Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package loadbalancer
+
+import (
+	"sync"
+	"time"
+
+	"istio.io/istio/pkg/test/loadbalancersim/network"
+)
+
+// OutlierDetectionSettings configures the ejection of connections returning consecutive errors.
+type OutlierDetectionSettings struct {
+	ConsecutiveErrors  int
+	BaseEjectionTime   time.Duration
+	MaxEjectionPercent int
+}
+
+// OutlierDetector ejects connections after consecutive errors, like the consecutive 5xx outlier detection of Envoy.
+// A connection is ejected for the base ejection time multiplied by the number of times it was ejected.
+type OutlierDetector struct {
+	s         OutlierDetectionSettings
+	mutex     sync.Mutex
+	hosts     map[*WeightedConnection]*hostState
+	ejections int
+}
+
+type hostState struct {
+	consecutiveErrors int
+	numEjections      int
+	ejectedUntil      time.Time
+}
+
+func NewOutlierDetector(s OutlierDetectionSettings) *OutlierDetector {
+	return &OutlierDetector{
+		s:     s,
+		hosts: make(map[*WeightedConnection]*hostState),
+	}
+}
+
+// Track returns a connection whose requests are recorded by the detector. When a request completes, failed is
+// called to determine whether it returned an error.
+func (d *OutlierDetector) Track(c *WeightedConnection, failed func() bool) *WeightedConnection {
+	state := &hostState{}
+	tracked := &WeightedConnection{
+		Connection: &outlierConnection{
+			Connection: c.Connection,
+			d:          d,
+			state:      state,
+			failed:     failed,
+		},
+		Weight: c.Weight,
+	}
+
+	d.mutex.Lock()
+	d.hosts[tracked] = state
+	d.mutex.Unlock()
+	return tracked
+}
+
+// Healthy returns false if the connection is currently ejected.
+func (d *OutlierDetector) Healthy(c *WeightedConnection) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	state := d.hosts[c]
+	return state == nil || !state.ejectedUntil.After(time.Now())
+}
+
+// Ejections returns the number of times connections were ejected.
+func (d *OutlierDetector) Ejections() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.ejections
+}
+
+func (d *OutlierDetector) record(state *hostState, failed bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if !failed {
+		state.consecutiveErrors = 0
+		return
+	}
+	state.consecutiveErrors++
+
+	now := time.Now()
+	if state.consecutiveErrors < d.s.ConsecutiveErrors || state.ejectedUntil.After(now) {
+		return
+	}
+
+	// Like Envoy, only eject if the maximum percentage of ejected connections has not been reached yet.
+	ejected := 0
+	for _, s := range d.hosts {
+		if s.ejectedUntil.After(now) {
+			ejected++
+		}
+	}
+	if ejected*100 >= d.s.MaxEjectionPercent*len(d.hosts) {
+		return
+	}
+
+	state.consecutiveErrors = 0
+	state.numEjections++
+	state.ejectedUntil = now.Add(d.s.BaseEjectionTime * time.Duration(state.numEjections))
+	d.ejections++
+}
+
+type outlierConnection struct {
+	network.Connection
+	d      *OutlierDetector
+	state  *hostState
+	failed func() bool
+}
+
+func (c *outlierConnection) Request(onDone func()) {
+	c.Connection.Request(func() {
+		c.d.record(c.state, c.failed())
+		onDone()
+	})
+}
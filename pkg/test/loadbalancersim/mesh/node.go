@@ -18,6 +18,8 @@ import (
 	"math"
 	"time"
 
+	"go.uber.org/atomic"
+
 	"istio.io/istio/pkg/test/loadbalancersim/locality"
 	"istio.io/istio/pkg/test/loadbalancersim/network"
 	"istio.io/istio/pkg/test/loadbalancersim/timer"
@@ -36,6 +38,7 @@ type Node struct {
 	qLatencyEnabled bool
 	qLength         timeseries.Instance
 	qLatency        timeseries.Instance
+	failing         *atomic.Bool
 }
 
 func newNode(name string, serviceTime time.Duration, enableQueueLatency bool, l locality.Instance) *Node {
@@ -45,6 +48,7 @@ func newNode(name string, serviceTime time.Duration, enableQueueLatency bool, l
 		q:               timer.NewQueue(),
 		serviceTime:     serviceTime,
 		qLatencyEnabled: enableQueueLatency,
+		failing:         atomic.NewBool(false),
 	}
 }
 
@@ -109,6 +113,16 @@ func (n *Node) Request(onDone func()) {
 	}, onDone)
 }
 
+// SetFailing sets whether the node responds to requests with errors.
+func (n *Node) SetFailing(failing bool) {
+	n.failing.Store(failing)
+}
+
+// Failing returns true if the node responds to requests with errors.
+func (n *Node) Failing() bool {
+	return n.failing.Load()
+}
+
 func (n *Node) Locality() locality.Instance {
 	return n.locality
 }
@@ -103,6 +103,8 @@ const (
 	HTTPInspector = "envoy.filters.listener.http_inspector"
 	// OriginalSource listener filter
 	OriginalSource = "envoy.filters.listener.original_src"
+	// UDPProxy UDP listener filter
+	UDPProxy = "envoy.filters.udp_listener.udp_proxy"
 )
 
 // Access log sink names
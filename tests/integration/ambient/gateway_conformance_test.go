@@ -67,12 +67,8 @@ var conformanceNamespaces = []string{
 
 var skippedTests = map[string]string{
 	// The following tests were added in v1.5.0
-
-	"GatewayBackendClientCertificateFeature":                     "TODO",
 	"GatewayFrontendClientCertificateValidationInsecureFallback": "TODO",
 
-	"HTTPRouteHTTPSListenerDetectMisdirectedRequests": "TODO",
-
 	// Fixed upstream, waiting for new gateway api release to pick up fix
 	"MeshHTTPRoute307Redirect": "TODO",
 
@@ -120,6 +116,7 @@ func TestGatewayConformance(t *testing.T) {
 						suite.GatewayTLSConformanceProfileName,
 						suite.GatewayGRPCConformanceProfileName,
 						suite.GatewayTCPConformanceProfileName,
+						suite.GatewayUDPConformanceProfileName,
 						suite.MeshHTTPConformanceProfileName,
 					},
 					Implementation: confv1.Implementation{
@@ -63,12 +63,6 @@ var conformanceNamespaces = []string{
 }
 
 var skippedTests = map[string]string{
-	// The following tests were added in v1.5.0
-	"GatewayBackendClientCertificateFeature": "TODO",
-	"GatewayInvalidTLSBackendConfiguration":  "TODO",
-
-	"HTTPRouteHTTPSListenerDetectMisdirectedRequests": "TODO",
-
 	// Fixed upstream, waiting for new gateway api release to pick up fix
 	"MeshHTTPRoute307Redirect": "TODO",
 
@@ -130,6 +124,7 @@ func TestGatewayConformance(t *testing.T) {
 						suite.GatewayTLSConformanceProfileName,
 						suite.GatewayGRPCConformanceProfileName,
 						suite.GatewayTCPConformanceProfileName,
+						suite.GatewayUDPConformanceProfileName,
 						suite.MeshHTTPConformanceProfileName,
 					},
 					Implementation: confv1.Implementation{